@@ -5,9 +5,11 @@ import (
 	"watchAlert/alert/consumer"
 	"watchAlert/alert/eval"
 	"watchAlert/alert/probing"
+	"watchAlert/alert/process"
 	"watchAlert/internal/ctx"
 	"watchAlert/internal/global"
 	"watchAlert/pkg/client"
+	"watchAlert/pkg/sender"
 	"watchAlert/pkg/tools"
 
 	"github.com/zeromicro/go-zero/core/logc"
@@ -26,6 +28,12 @@ var (
 	// 消息订阅取消函数
 	subscriberCancels []context.CancelFunc
 
+	// 通知重试及风暴摘要任务取消函数
+	retryWorkerCancel context.CancelFunc
+
+	// 数据源健康检查任务取消函数
+	healthWorkerCancel context.CancelFunc
+
 	// 选举开关
 	leaderElectionEnabled bool
 )
@@ -71,10 +79,51 @@ func loadRules() {
 	// 重启所有拨测任务
 	ProductProbing.RePushRule(&ConsumeProbing)
 
+	// 启动通知重试及风暴摘要任务
+	startRetryWorker()
+
+	// 启动数据源健康检查任务
+	startHealthWorker()
+
 	// 启动 Redis 消息订阅，监听规则变更
 	startMessageSubscribers()
 }
 
+// startRetryWorker 启动通知重试及风暴摘要任务，仅 Leader 节点处理，避免重复发送
+func startRetryWorker() {
+	stopRetryWorker()
+
+	var retryCtx context.Context
+	retryCtx, retryWorkerCancel = context.WithCancel(ctx.Ctx)
+	go sender.StartRetryWorker(retryCtx, ctx.DO())
+	go process.StartStormDigestWorker(retryCtx, ctx.DO())
+}
+
+// stopRetryWorker 停止通知重试及风暴摘要任务
+func stopRetryWorker() {
+	if retryWorkerCancel != nil {
+		retryWorkerCancel()
+		retryWorkerCancel = nil
+	}
+}
+
+// startHealthWorker 启动数据源健康检查任务，仅 Leader 节点处理，避免重复产生事件
+func startHealthWorker() {
+	stopHealthWorker()
+
+	var healthCtx context.Context
+	healthCtx, healthWorkerCancel = context.WithCancel(ctx.Ctx)
+	go process.StartDatasourceHealthWorker(healthCtx, ctx.DO(), global.Config.DatasourceHealth)
+}
+
+// stopHealthWorker 停止数据源健康检查任务
+func stopHealthWorker() {
+	if healthWorkerCancel != nil {
+		healthWorkerCancel()
+		healthWorkerCancel = nil
+	}
+}
+
 // startMessageSubscribers 启动消息订阅器
 func startMessageSubscribers() {
 	subscriberCancels = make([]context.CancelFunc, 0)
@@ -196,6 +245,12 @@ func unloadRules() {
 	// 停止消息订阅
 	stopMessageSubscribers()
 
+	// 停止通知重试及风暴摘要任务
+	stopRetryWorker()
+
+	// 停止数据源健康检查任务
+	stopHealthWorker()
+
 	// 停止所有告警规则评估器
 	AlertRule.StopAllEvals()
 
@@ -210,6 +210,8 @@ func (c *Consume) executeTask(faultCenter models.FaultCenter, taskChan chan stru
 	}()
 	// 处理静默规则
 	c.processSilenceRule(faultCenter)
+	// 处理已稳定的抖动事件
+	c.processFlapping(faultCenter)
 	// 获取故障中心的所有告警事件
 	data, err := c.ctx.Redis.Alert().GetAllEvents(models.BuildAlertEventCacheKey(faultCenter.TenantId, faultCenter.ID))
 	if err != nil {
@@ -219,11 +221,16 @@ func (c *Consume) executeTask(faultCenter models.FaultCenter, taskChan chan stru
 
 	// 事件过滤
 	filterEvents := c.filterAlertEvents(faultCenter, data)
-	// 事件分组
-	var alertGroups AlertGroups
-	c.alarmGrouping(faultCenter, &alertGroups, filterEvents)
-	// 发送事件
-	c.sendAlerts(faultCenter, &alertGroups)
+	if faultCenter.GetAlarmAggregationType() == models.AggregationTypeLabel {
+		// 按标签分组发送事件
+		c.processLabelGroups(faultCenter, filterEvents)
+	} else {
+		// 事件分组
+		var alertGroups AlertGroups
+		c.alarmGrouping(faultCenter, &alertGroups, filterEvents)
+		// 发送事件
+		c.sendAlerts(faultCenter, &alertGroups)
+	}
 	// 处理告警升级
 	err = alarmUpgrade(c.ctx, faultCenter, data)
 	if err != nil {
@@ -241,6 +248,11 @@ func (c *Consume) filterAlertEvents(faultCenter models.FaultCenter, alerts map[s
 			continue
 		}
 
+		// 抖动期间暂停通知
+		if c.isHeldFlappingEvent(event, faultCenter) {
+			continue
+		}
+
 		if c.isMutedEvent(event, faultCenter) {
 			// 当告警处于静默状态时触发了恢复告警，直接移除即可 不需要发送消息。
 			if event.Status == models.StateRecovered {
@@ -249,6 +261,16 @@ func (c *Consume) filterAlertEvents(faultCenter models.FaultCenter, alerts map[s
 			continue
 		}
 
+		if c.isInhibitedEvent(event, faultCenter, alerts) {
+			continue
+		}
+
+		// 标签分组的发送时机由分组状态决定
+		if faultCenter.GetAlarmAggregationType() == models.AggregationTypeLabel {
+			newEvents = append(newEvents, event)
+			continue
+		}
+
 		if valid := c.validateEvent(event, faultCenter); valid {
 			newEvents = append(newEvents, event)
 		}
@@ -269,6 +291,58 @@ func (c *Consume) isMutedEvent(event *models.AlertCurEvent, faultCenter models.F
 	})
 }
 
+// isInhibitedEvent 抑制检查，被抑制的事件标记为「抑制中」状态，抑制源消失后恢复为「告警中」
+func (c *Consume) isInhibitedEvent(event *models.AlertCurEvent, faultCenter models.FaultCenter, alerts map[string]*models.AlertCurEvent) bool {
+	inhibited := len(faultCenter.InhibitRules) > 0 && mute.IsInhibited(event, alerts, faultCenter.InhibitRules)
+
+	switch event.Status {
+	case models.StateAlerting:
+		if !inhibited {
+			return false
+		}
+		return c.transitionCachedEvent(event, models.StateAlerting, models.StateInhibited)
+	case models.StateInhibited:
+		if inhibited {
+			return true
+		}
+		return !c.transitionCachedEvent(event, models.StateInhibited, models.StateAlerting)
+	case models.StateRecovered:
+		// 抑制期间恢复的告警无需发送恢复通知，但需要保留告警历史
+		if inhibited {
+			if err := process.RecordAlertHisEvent(c.ctx, *event); err != nil {
+				logc.Error(c.ctx.Ctx, fmt.Sprintf("Failed to record alert history: %v", err))
+			}
+			c.ctx.Mux.Lock()
+			c.removeAlertFromCache(event)
+			c.ctx.Mux.Unlock()
+			return true
+		}
+	}
+
+	return false
+}
+
+// transitionCachedEvent 基于缓存中的最新事件切换状态，与 PushEventToFaultCenter 共用 ctx.Mux，
+// 事件状态已被评估协程修改时放弃本次切换
+func (c *Consume) transitionCachedEvent(event *models.AlertCurEvent, from, to models.AlertStatus) bool {
+	c.ctx.Mux.Lock()
+	defer c.ctx.Mux.Unlock()
+
+	current, err := c.ctx.Redis.Alert().GetEventFromCache(event.TenantId, event.FaultCenterId, event.Fingerprint)
+	if err != nil || current.Status != from {
+		return false
+	}
+
+	if err := current.TransitionStatus(to); err != nil {
+		logc.Errorf(c.ctx.Ctx, "Failed to transition to「%s」state for fingerprint %s: %v", to, event.Fingerprint, err)
+		return false
+	}
+	c.ctx.Redis.Alert().PushAlertEvent(&current)
+	*event = current
+
+	return true
+}
+
 // validateEvent 事件验证
 func (c *Consume) validateEvent(event *models.AlertCurEvent, faultCenter models.FaultCenter) bool {
 	return event.IsRecovered || event.LastSendTime == 0 ||
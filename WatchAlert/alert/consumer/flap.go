@@ -0,0 +1,99 @@
+package consumer
+
+import (
+	"fmt"
+	"time"
+	"watchAlert/alert/process"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// isHeldFlappingEvent 抖动期间暂停通知，恢复的事件直接移除并记录历史，稳定后由 processFlapping 发送摘要
+func (c *Consume) isHeldFlappingEvent(event *models.AlertCurEvent, faultCenter models.FaultCenter) bool {
+	if !event.Flapping || !faultCenter.FlapDetection.GetHoldNotice() {
+		return false
+	}
+
+	if event.IsRecovered {
+		c.removeAlertFromCache(event)
+		if err := process.RecordAlertHisEvent(c.ctx, *event); err != nil {
+			logc.Error(c.ctx.Ctx, fmt.Sprintf("Failed to record alert history: %v", err))
+		}
+	}
+
+	return true
+}
+
+// processFlapping 处理已稳定的抖动事件，取消抖动标记并发送一条摘要
+func (c *Consume) processFlapping(faultCenter models.FaultCenter) {
+	detection := faultCenter.FlapDetection
+	if !detection.GetEnabled() {
+		return
+	}
+
+	now := time.Now().Unix()
+	for _, state := range c.ctx.Redis.Flap().ListFlapping(faultCenter.TenantId, faultCenter.ID) {
+		if now-state.LastTransition < detection.GetStableTime() {
+			continue
+		}
+		c.ctx.Redis.Flap().DeleteFlapping(state.TenantId, state.FaultCenterId, state.Fingerprint)
+
+		// 事件仍在告警中时取消抖动标记，摘要视为本次通知，之后按重复通知间隔发送
+		event, err := c.ctx.Redis.Alert().GetEventFromCache(state.TenantId, state.FaultCenterId, state.Fingerprint)
+		exists := err == nil && event.Fingerprint != ""
+		if exists {
+			event.Flapping = false
+			if !event.IsRecovered {
+				event.LastSendTime = now
+			}
+			c.ctx.Redis.Alert().PushAlertEvent(&event)
+		}
+
+		logc.Infof(c.ctx.Ctx, "事件抖动已稳定，规则: %s，指纹: %s，抖动期间状态切换 %d 次", state.RuleName, state.Fingerprint, state.Transitions)
+		if !detection.GetHoldNotice() {
+			continue
+		}
+
+		summary := buildFlapSummaryEvent(faultCenter, state, event, exists)
+		var ag AlertGroups
+		for _, noticeId := range ag.getNoticeId(summary, faultCenter) {
+			if err := process.HandleAlert(c.ctx, "flap", faultCenter, noticeId, []*models.AlertCurEvent{summary}); err != nil {
+				logc.Error(c.ctx.Ctx, fmt.Sprintf("Failed to send flap summary: %v", err))
+			}
+		}
+	}
+}
+
+// buildFlapSummaryEvent 构建抖动摘要事件，事件仍存在时以当前事件为主体，已恢复并移除时按抖动记录生成
+func buildFlapSummaryEvent(faultCenter models.FaultCenter, state models.FlapState, event models.AlertCurEvent, exists bool) *models.AlertCurEvent {
+	summary := event
+	if !exists {
+		summary = models.AlertCurEvent{
+			TenantId:      state.TenantId,
+			EventId:       "flap-" + state.Fingerprint,
+			RuleId:        state.RuleId,
+			RuleName:      state.RuleName,
+			Fingerprint:   state.Fingerprint,
+			Severity:      state.Severity,
+			Labels:        state.Labels,
+			FaultCenterId: faultCenter.ID,
+			IsRecovered:   true,
+			RecoverTime:   state.LastTransition,
+		}
+	}
+
+	status := "告警中"
+	if summary.IsRecovered {
+		status = "已恢复"
+	}
+	summary.Flapping = false
+	summary.Annotations = fmt.Sprintf("告警在 %s 至 %s 期间反复触发与恢复，共切换状态 %d 次，现已稳定，当前状态: %s",
+		time.Unix(state.Since, 0).Format(time.DateTime), time.Unix(state.LastTransition, 0).Format(time.DateTime),
+		state.Transitions, status)
+	if event.Annotations != "" {
+		summary.Annotations += "\n" + event.Annotations
+	}
+
+	return &summary
+}
@@ -0,0 +1,150 @@
+package consumer
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"watchAlert/alert/process"
+	"watchAlert/internal/cache"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// labelGroup 标签分组
+type labelGroup struct {
+	NoticeID string
+	Events   []*models.AlertCurEvent
+}
+
+// processLabelGroups 按标签分组发送告警，参考 Alertmanager 的 group_by / group_wait / group_interval / repeat_interval
+func (c *Consume) processLabelGroups(faultCenter models.FaultCenter, alerts []*models.AlertCurEvent) {
+	var ag AlertGroups
+	groups := make(map[string]*labelGroup)
+	for _, alert := range alerts {
+		for _, noticeId := range ag.getNoticeId(alert, faultCenter) {
+			key := buildLabelGroupKey(noticeId, alert, faultCenter.GroupStrategy.GroupBy)
+			group, ok := groups[key]
+			if !ok {
+				group = &labelGroup{NoticeID: noticeId}
+				groups[key] = group
+			}
+			group.Events = append(group.Events, alert)
+		}
+	}
+
+	curTime := time.Now().Unix()
+	groupCache := c.ctx.Redis.AlertGroup()
+	states := groupCache.List(faultCenter.TenantId, faultCenter.ID)
+
+	// 同一恢复事件可能属于多个通知对象的分组，全部分组发送后才能移除
+	pendingRecovered := make(map[string]bool)
+	var recovered []*models.AlertCurEvent
+	for key, group := range groups {
+		state, ok := states[key]
+		if !ok {
+			state = cache.AlertGroupState{FirstTime: curTime}
+		}
+
+		send := shouldSendLabelGroup(faultCenter, state, group.Events, curTime)
+		for _, event := range group.Events {
+			if !event.IsRecovered {
+				continue
+			}
+			if _, exist := pendingRecovered[event.Fingerprint]; !exist {
+				recovered = append(recovered, event)
+			}
+			pendingRecovered[event.Fingerprint] = pendingRecovered[event.Fingerprint] || !send
+		}
+
+		if !send {
+			if !ok {
+				groupCache.Set(faultCenter.TenantId, faultCenter.ID, key, state)
+			}
+			continue
+		}
+
+		if err := process.HandleGroupAlert(c.ctx, faultCenter, group.NoticeID, group.Events); err != nil {
+			logc.Error(c.ctx.Ctx, fmt.Sprintf("Alert label group processing failed, group: %s, err: %v", key, err))
+		}
+		if err := c.handleSubscribe(group.Events); err != nil {
+			logc.Error(c.ctx.Ctx, fmt.Sprintf("Alert label group subscribe failed, group: %s, err: %v", key, err))
+		}
+
+		state.LastSendTime = curTime
+		state.Fingerprints = firingFingerprints(group.Events)
+		if len(state.Fingerprints) == 0 {
+			groupCache.Delete(faultCenter.TenantId, faultCenter.ID, key)
+			continue
+		}
+		groupCache.Set(faultCenter.TenantId, faultCenter.ID, key, state)
+	}
+
+	for _, event := range recovered {
+		if pendingRecovered[event.Fingerprint] {
+			continue
+		}
+		c.removeAlertFromCache(event)
+		// 记录告警历史事件
+		if err := process.RecordAlertHisEvent(c.ctx, *event); err != nil {
+			logc.Error(c.ctx.Ctx, fmt.Sprintf("Failed to record alert history: %v", err))
+		}
+	}
+
+	// 清理已不存在事件的分组
+	for key := range states {
+		if _, ok := groups[key]; !ok {
+			groupCache.Delete(faultCenter.TenantId, faultCenter.ID, key)
+		}
+	}
+}
+
+// shouldSendLabelGroup 判断分组是否到达发送时间
+// 新分组等待 groupWait 后发送；分组内新增或恢复事件时按 groupInterval 发送；未变化时按重复通知间隔发送
+func shouldSendLabelGroup(faultCenter models.FaultCenter, state cache.AlertGroupState, events []*models.AlertCurEvent, curTime int64) bool {
+	strategy := faultCenter.GroupStrategy
+	if state.LastSendTime == 0 {
+		return curTime >= state.FirstTime+strategy.GroupWait
+	}
+
+	changed := false
+	for _, event := range events {
+		if event.IsRecovered || !slices.Contains(state.Fingerprints, event.Fingerprint) {
+			changed = true
+			break
+		}
+	}
+
+	if changed {
+		return curTime >= state.LastSendTime+strategy.GroupInterval
+	}
+
+	return curTime >= state.LastSendTime+faultCenter.GetGroupRepeatInterval()
+}
+
+// buildLabelGroupKey 通知对象 + 分组标签值 = 分组 Key
+func buildLabelGroupKey(noticeId string, alert *models.AlertCurEvent, groupBy []string) string {
+	keys := slices.Clone(groupBy)
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, alert.Labels[k]))
+	}
+
+	return noticeId + ":" + strings.Join(pairs, ",")
+}
+
+// firingFingerprints 获取分组内告警中事件的指纹
+func firingFingerprints(events []*models.AlertCurEvent) []string {
+	var fingerprints []string
+	for _, event := range events {
+		if !event.IsRecovered {
+			fingerprints = append(fingerprints, event.Fingerprint)
+		}
+	}
+
+	return fingerprints
+}
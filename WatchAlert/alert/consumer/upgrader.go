@@ -2,6 +2,7 @@ package consumer
 
 import (
 	"fmt"
+	"strings"
 	"time"
 	"watchAlert/alert/mute"
 	"watchAlert/alert/process"
@@ -15,8 +16,8 @@ import (
 type AggregatedAlert struct {
 	Fingerprints []string
 	Events       []*models.AlertCurEvent
-	Status       int64
-	Timeout      int64
+	Level        int
+	Strategy     models.EscalationLevel
 }
 
 // alarmUpgrade 处理告警升级主入口
@@ -26,37 +27,61 @@ func alarmUpgrade(ctx *ctx.Context, faultCenter models.FaultCenter, alerts map[s
 		return nil
 	}
 
+	policy, err := getEscalationPolicy(ctx, faultCenter)
+	if err != nil {
+		return fmt.Errorf("get escalation policy failed: %w", err)
+	}
+	if len(policy.Levels) == 0 {
+		return nil
+	}
+
 	// 过滤告警事件
 	filterAlerts := filterAlertEvents(faultCenter, alerts)
 	if len(filterAlerts) == 0 {
 		return nil
 	}
 
-	confirmAggregated := createAggregatedAlert(models.ConfirmStatus, faultCenter)
-	// 遍历事件并处理升级阶段
+	aggregates := make([]*AggregatedAlert, 0, len(policy.Levels))
+	for i, level := range policy.Levels {
+		aggregates = append(aggregates, createAggregatedAlert(i, level))
+	}
+
+	// 遍历事件并依次处理各升级级别
 	for _, event := range filterAlerts {
-		// 确认阶段
-		if !event.ConfirmState.IsOk {
-			if err := processStage(ctx, faultCenter, event, currentTime, confirmAggregated, models.ConfirmStatus); err != nil {
-				logc.Error(ctx.Ctx, fmt.Errorf("process confirm stage failed: %w", err))
+		escalated := initEscalationState(event)
+		for _, aggregated := range aggregates {
+			if processStage(event, currentTime, aggregated) {
+				escalated = true
 			}
 		}
+		if escalated {
+			ctx.Redis.Alert().PushAlertEvent(event)
+		}
 	}
 
-	if confirmAggregated != nil {
-		sendIfNotEmpty(ctx, faultCenter, confirmAggregated)
+	for _, aggregated := range aggregates {
+		sendIfNotEmpty(ctx, faultCenter, aggregated)
 	}
 
 	return nil
 }
 
+// getEscalationPolicy 获取故障中心的升级策略，未关联升级策略时使用单级升级策略
+func getEscalationPolicy(ctx *ctx.Context, faultCenter models.FaultCenter) (models.EscalationPolicy, error) {
+	if faultCenter.EscalationPolicyId == "" {
+		return faultCenter.UpgradeStrategy.ToEscalationPolicy(), nil
+	}
+
+	return ctx.DB.EscalationPolicy().Get(faultCenter.TenantId, faultCenter.EscalationPolicyId)
+}
+
 // filterAlertEvents 过滤告警事件
 func filterAlertEvents(faultCenter models.FaultCenter, alerts map[string]*models.AlertCurEvent) []*models.AlertCurEvent {
 	newEvents := make([]*models.AlertCurEvent, 0, len(alerts))
 
 	for _, event := range alerts {
 		switch event.Status {
-		case models.StatePreAlert, models.StatePendingRecovery:
+		case models.StatePreAlert, models.StatePendingRecovery, models.StateInhibited:
 			continue
 		}
 
@@ -75,6 +100,11 @@ func filterAlertEvents(faultCenter models.FaultCenter, alerts map[string]*models
 			continue
 		}
 
+		// 抖动期间暂停升级通知
+		if event.Flapping && faultCenter.FlapDetection.GetHoldNotice() {
+			continue
+		}
+
 		newEvents = append(newEvents, event)
 	}
 
@@ -93,99 +123,121 @@ func isMutedEvent(event *models.AlertCurEvent, faultCenter models.FaultCenter) b
 	})
 }
 
+// initEscalationState 升级策略上线前的事件仅记录了 ConfirmTimeoutSendTime，首次处理时据此初始化第 1 级的通知状态，避免重复通知
+func initEscalationState(alert *models.AlertCurEvent) bool {
+	if len(alert.EscalationState) > 0 || alert.ConfirmState.ConfirmTimeoutSendTime == 0 {
+		return false
+	}
+
+	alert.SetEscalationState(models.EscalationLevelState{
+		Level:        0,
+		Times:        1,
+		LastSendTime: alert.ConfirmState.ConfirmTimeoutSendTime,
+	})
+	return true
+}
+
 // createAggregatedAlert 创建聚合告警对象
-func createAggregatedAlert(status int64, faultCenter models.FaultCenter) *AggregatedAlert {
+func createAggregatedAlert(level int, strategy models.EscalationLevel) *AggregatedAlert {
 	return &AggregatedAlert{
 		Fingerprints: make([]string, 0),
 		Events:       make([]*models.AlertCurEvent, 0),
-		Status:       status,
-		Timeout:      faultCenter.GetTimeout(),
+		Level:        level,
+		Strategy:     strategy,
 	}
 }
 
-// processStage 统一处理确认和处理阶段的逻辑
-func processStage(ctx *ctx.Context, faultCenter models.FaultCenter, alert *models.AlertCurEvent, currentTime int64, aggregated *AggregatedAlert, status int64) error {
-	// 检查是否超时 (达到升级条件)
-	statusStrategy := faultCenter.UpgradeStrategy
-	startTime := getStartTime(alert)
-	timeoutDuration := statusStrategy.Timeout
-
-	isUpgradeTimeout, err := checkTimeout(startTime, currentTime, timeoutDuration)
-	if err != nil {
-		return fmt.Errorf("check confirm upgrade timeout failed: %w", err)
-	}
-	if !isUpgradeTimeout {
-		return nil
+// processStage 处理单个升级级别，满足升级条件时加入聚合并更新通知状态
+func processStage(alert *models.AlertCurEvent, currentTime int64, aggregated *AggregatedAlert) bool {
+	strategy := aggregated.Strategy
+	if strategy.NoticeId == "" {
+		return false
 	}
 
-	// 检查是否需要重新通知 (达到通知间隔)
-	lastNoticeTime := getLastNoticeTime(alert)
-	if lastNoticeTime != 0 {
-		reNotifyDuration := statusStrategy.RepeatInterval
-
-		// 检查通知间隔是否超时
-		isReNotifyTimeout, err := checkTimeout(lastNoticeTime, currentTime, reNotifyDuration)
-		if err != nil {
-			return fmt.Errorf("check confirm re-notify interval failed: %w", err)
-		}
-		if !isReNotifyTimeout {
-			return nil
+	switch strategy.GetTrigger() {
+	case models.EscalationTriggerNotAcknowledged:
+		if alert.ConfirmState.IsOk {
+			return false
 		}
+	case models.EscalationTriggerNotResolved:
+		// 已恢复的事件在过滤阶段排除
+	default:
+		return false
 	}
 
-	aggregated.Fingerprints = append(aggregated.Fingerprints, alert.Fingerprint)
-	aggregated.Events = append(aggregated.Events, alert)
-	// 更新通知时间，并推送到 Redis
-	setLastNoticeTime(ctx, alert, currentTime)
+	// 检查是否超时 (达到升级条件)
+	if !checkTimeout(alert.FirstTriggerTime, currentTime, strategy.Delay) {
+		return false
+	}
 
-	return nil
-}
+	state := alert.GetEscalationState(aggregated.Level)
+	if maxTimes := strategy.GetMaxTimes(); maxTimes >= 0 && state.Times >= maxTimes {
+		return false
+	}
 
-// getStartTime 根据状态获取开始时间
-func getStartTime(alert *models.AlertCurEvent) int64 {
-	return alert.FirstTriggerTime
-}
+	// 检查是否需要重新通知 (达到通知间隔)
+	if state.LastSendTime != 0 && !checkTimeout(state.LastSendTime, currentTime, strategy.RepeatInterval) {
+		return false
+	}
 
-// getLastNoticeTime 根据状态获取上次通知时间
-func getLastNoticeTime(alert *models.AlertCurEvent) int64 {
-	return alert.ConfirmState.ConfirmTimeoutSendTime
-}
+	state.Times++
+	state.LastSendTime = currentTime
+	alert.SetEscalationState(state)
+	if strategy.GetTrigger() == models.EscalationTriggerNotAcknowledged {
+		alert.ConfirmState.ConfirmTimeoutSendTime = currentTime
+	}
 
-// setLastNoticeTime 根据状态设置上次通知时间
-func setLastNoticeTime(ctx *ctx.Context, alert *models.AlertCurEvent, currentTime int64) {
-	alert.ConfirmState.ConfirmTimeoutSendTime = currentTime
-	ctx.Redis.Alert().PushAlertEvent(alert)
+	aggregated.Fingerprints = append(aggregated.Fingerprints, alert.Fingerprint)
+	aggregated.Events = append(aggregated.Events, alert)
+	return true
 }
 
-// sendIfNotEmpty 检查聚合告警是否不为空，如果不为空则发送
+// sendIfNotEmpty 检查聚合告警是否不为空，如果不为空则发送并记录时间线
 func sendIfNotEmpty(ctx *ctx.Context, faultCenter models.FaultCenter, aggregated *AggregatedAlert) {
 	if len(aggregated.Events) == 0 {
 		return
 	}
 
+	strategy := aggregated.Strategy
+	logc.Alert(ctx.Ctx, fmt.Sprintf("Aggregated alarm escalation level %d fingerprints: %v, trigger: %s, exceeded %d min",
+		aggregated.Level+1,
+		aggregated.Fingerprints,
+		strategy.GetTrigger(),
+		strategy.Delay))
+
 	// 仅保留第一个事件发送
+	first := *aggregated.Events[0]
 	if len(aggregated.Events) > 1 {
-		aggregated.Events[0].Annotations = fmt.Sprintf("%s%s", aggregated.Events[0].Annotations, getContent(len(aggregated.Events)))
-		aggregated.Events = aggregated.Events[:1]
+		first.Annotations = fmt.Sprintf("%s%s", first.Annotations, getContent(len(aggregated.Events)))
+	}
+
+	if err := process.HandleEscalation(ctx, strategy, faultCenter.TenantId, []models.AlertCurEvent{first}); err != nil {
+		logc.Error(ctx.Ctx, fmt.Errorf("send aggregated escalation alert failed: %w", err))
 	}
 
-	if err := sendAggregatedAlert(ctx, faultCenter, aggregated); err != nil {
-		logc.Error(ctx.Ctx, fmt.Errorf("send aggregated confirm alert failed: %w", err))
+	for _, event := range aggregated.Events {
+		state := event.GetEscalationState(aggregated.Level)
+		process.RecordEventTimeline(ctx, *event, models.EventTimelineEscalation, getTimelineContent(aggregated, state), "system")
 	}
 }
 
-// sendAggregatedAlert 发送聚合后的告警函数
-func sendAggregatedAlert(ctx *ctx.Context, faultCenter models.FaultCenter, aggregated *AggregatedAlert) error {
-	noticeId := faultCenter.GetUpgradeNoticeId()
-	if noticeId == "" {
-		return nil
+// getTimelineContent 生成升级时间线内容
+func getTimelineContent(aggregated *AggregatedAlert, state models.EscalationLevelState) string {
+	strategy := aggregated.Strategy
+	trigger := "超时未认领"
+	if strategy.GetTrigger() == models.EscalationTriggerNotResolved {
+		trigger = "超时未恢复"
 	}
 
-	logc.Alert(ctx.Ctx, fmt.Sprintf("Aggregated alarm confirm timeout fingerprints: %v, exceeded %d min",
-		aggregated.Fingerprints,
-		aggregated.Timeout))
+	target := "通知对象 " + strategy.NoticeId
+	switch strategy.GetTargetType() {
+	case models.EscalationTargetDuty:
+		target = "值班表 " + strategy.DutyId
+	case models.EscalationTargetUser:
+		target = "用户 " + strings.Join(strategy.UserIds, ",")
+	}
 
-	return process.HandleAlert(ctx, "upgrade", faultCenter, noticeId, aggregated.Events)
+	return fmt.Sprintf("触发第 %d 级升级, %s %d 分钟, 通知%s, 第 %d 次通知", aggregated.Level+1, trigger, strategy.Delay, target, state.Times)
 }
 
 // getContent 生成聚合通知内容
@@ -194,9 +246,9 @@ func getContent(number int) string {
 }
 
 // checkTimeout 检查是否超时 duration 单位为分钟
-func checkTimeout(startTime, currentTime int64, duration int64) (bool, error) {
+func checkTimeout(startTime, currentTime int64, duration int64) bool {
 	timeoutSeconds := duration * 60
 
 	// 如果 currentTime 超过 startTime + timeoutSeconds，则超时
-	return currentTime > startTime+timeoutSeconds, nil
+	return currentTime > startTime+timeoutSeconds
 }
@@ -0,0 +1,246 @@
+package eval
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/provider"
+)
+
+const (
+	// 单次历史查询的最大数据点数，超过时自动放大步长
+	anomalyMaxPoints = 10000
+	// 计算基线所需的最少历史数据点
+	anomalyMinPoints = 5
+
+	// Holt-Winters 平滑系数
+	holtWintersAlpha = 0.3
+	holtWintersBeta  = 0.05
+	holtWintersGamma = 0.3
+)
+
+// anomalyBaseline 序列的基线，告警区间为 Expected ± k·Sigma
+type anomalyBaseline struct {
+	Expected float64
+	Sigma    float64
+}
+
+// anomalyBand 基线区间及当前值的偏离程度
+type anomalyBand struct {
+	Lower     float64
+	Upper     float64
+	Deviation float64 // 偏离基线的标准差倍数
+	Anomalous bool
+}
+
+// band 计算灵敏度 k 下的基线区间，当前值超出区间且符合检测方向时视为异常
+func (b anomalyBaseline) band(value, k float64, direction string) anomalyBand {
+	sigma := b.Sigma
+	// 序列几乎无波动时以基线的 1% 作为最小波动，避免微小变化触发告警
+	if minSigma := math.Abs(b.Expected) * 0.01; sigma < minSigma {
+		sigma = minSigma
+	}
+	if sigma == 0 {
+		sigma = 1e-9
+	}
+
+	band := anomalyBand{
+		Lower:     b.Expected - k*sigma,
+		Upper:     b.Expected + k*sigma,
+		Deviation: (value - b.Expected) / sigma,
+	}
+
+	switch direction {
+	case models.AnomalyDirectionUp:
+		band.Anomalous = value > band.Upper
+	case models.AnomalyDirectionDown:
+		band.Anomalous = value < band.Lower
+	default:
+		band.Anomalous = value > band.Upper || value < band.Lower
+	}
+
+	return band
+}
+
+// anomalyBaselines 查询历史数据并按序列指纹计算基线
+func anomalyBaselines(cli provider.MetricsFactoryProvider, config models.AnomalyConfig, query string, now time.Time) (map[string]anomalyBaseline, error) {
+	var (
+		start, end time.Time
+		step       = config.GetStep()
+	)
+	switch config.Algorithm {
+	case models.AnomalyAlgorithmWeekOverWeek:
+		lastWeek := now.Add(-7 * 24 * time.Hour)
+		start, end = lastWeek.Add(-config.GetWindow()/2), lastWeek.Add(config.GetWindow()/2)
+	case models.AnomalyAlgorithmStdDev:
+		// 不包含当前评估点，避免异常值拉高基线
+		start, end = now.Add(-config.GetWindow()), now.Add(-step)
+	case models.AnomalyAlgorithmHoltWinters:
+		start, end = now.Add(-2*config.GetSeason()), now.Add(-step)
+	default:
+		return nil, fmt.Errorf("unsupported anomaly algorithm: %s", config.Algorithm)
+	}
+
+	step = anomalyStep(start, end, step, config)
+
+	res, err := cli.QueryRange(query, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make(map[string][]provider.Metrics)
+	for _, v := range res {
+		fingerprint := v.GetFingerprint()
+		series[fingerprint] = append(series[fingerprint], v)
+	}
+
+	slots := int(end.Sub(start)/step) + 1
+	baselines := make(map[string]anomalyBaseline, len(series))
+	for fingerprint, points := range series {
+		values, observed := alignSeries(points, start, step, slots)
+		if len(observed) < anomalyMinPoints {
+			continue
+		}
+
+		if config.Algorithm == models.AnomalyAlgorithmHoltWinters {
+			// 按时间戳对齐后，相隔 seasonLength 个数据点即为相邻季节的同一时刻
+			seasonLength := int(config.GetSeason() / step)
+			baseline, ok := holtWinters(values, seasonLength)
+			if !ok {
+				continue
+			}
+			baselines[fingerprint] = baseline
+			continue
+		}
+
+		mean, stddev := meanStdDev(observed)
+		baselines[fingerprint] = anomalyBaseline{Expected: mean, Sigma: stddev}
+	}
+
+	return baselines, nil
+}
+
+// anomalyStep 数据点超过上限时放大步长（整秒），Holt-Winters 的步长需能整除季节周期，保证每个季节的数据点数一致
+func anomalyStep(start, end time.Time, step time.Duration, config models.AnomalyConfig) time.Duration {
+	if end.Sub(start)/step <= anomalyMaxPoints {
+		return step
+	}
+
+	seconds := int64(math.Ceil(end.Sub(start).Seconds() / anomalyMaxPoints))
+	if config.Algorithm == models.AnomalyAlgorithmHoltWinters {
+		season := int64(config.GetSeason().Seconds())
+		for season%seconds != 0 {
+			seconds++
+		}
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// alignSeries 按时间戳将数据点对齐到 start + i*step 的时间槽，同一时间槽保留最后一个数据点；
+// 返回对齐后的序列（缺失的时间槽沿用前一个值，开头缺失沿用第一个值）及实际采集到的数据点
+func alignSeries(points []provider.Metrics, start time.Time, step time.Duration, slots int) ([]float64, []float64) {
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Timestamp < points[j].Timestamp
+	})
+
+	var (
+		values   = make([]float64, slots)
+		filled   = make([]bool, slots)
+		stepSec  = step.Seconds()
+		observed []float64
+	)
+	for _, p := range points {
+		offset := float64(backtestTimestamp(p.Timestamp) - start.Unix())
+		slot := int(math.Round(offset / stepSec))
+		if slot < 0 || slot >= slots {
+			continue
+		}
+		values[slot] = p.Value
+		filled[slot] = true
+	}
+
+	first := -1
+	for i := range values {
+		if filled[i] {
+			observed = append(observed, values[i])
+			if first < 0 {
+				first = i
+			}
+			continue
+		}
+		if first >= 0 {
+			values[i] = values[i-1]
+		}
+	}
+	if first < 0 {
+		return nil, nil
+	}
+	for i := 0; i < first; i++ {
+		values[i] = values[first]
+	}
+
+	return values, observed
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(values))
+
+	return mean, math.Sqrt(variance)
+}
+
+// holtWinters 加法 Holt-Winters 预测下一个点，Sigma 为一步预测残差的标准差；历史数据不足两个季节周期时返回 false
+func holtWinters(values []float64, seasonLength int) (anomalyBaseline, bool) {
+	if seasonLength < 2 || len(values) < 2*seasonLength {
+		return anomalyBaseline{}, false
+	}
+
+	firstMean, _ := meanStdDev(values[:seasonLength])
+	secondMean, _ := meanStdDev(values[seasonLength : 2*seasonLength])
+
+	level := firstMean
+	trend := (secondMean - firstMean) / float64(seasonLength)
+	seasonal := make([]float64, seasonLength)
+	for i := 0; i < seasonLength; i++ {
+		seasonal[i] = values[i] - level
+	}
+
+	residuals := make([]float64, 0, len(values)-seasonLength)
+	for t := seasonLength; t < len(values); t++ {
+		idx := t % seasonLength
+		forecast := level + trend + seasonal[idx]
+		residuals = append(residuals, values[t]-forecast)
+
+		newLevel := holtWintersAlpha*(values[t]-seasonal[idx]) + (1-holtWintersAlpha)*(level+trend)
+		trend = holtWintersBeta*(newLevel-level) + (1-holtWintersBeta)*trend
+		seasonal[idx] = holtWintersGamma*(values[t]-newLevel) + (1-holtWintersGamma)*seasonal[idx]
+		level = newLevel
+	}
+
+	_, sigma := meanStdDev(residuals)
+	return anomalyBaseline{
+		Expected: level + trend + seasonal[len(values)%seasonLength],
+		Sigma:    sigma,
+	}, true
+}
+
+// roundFloat 保留两位小数，便于在告警模版中展示
+func roundFloat(v float64) float64 {
+	return math.Round(v*100) / 100
+}
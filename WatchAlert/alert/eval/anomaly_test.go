@@ -0,0 +1,143 @@
+package eval
+
+import (
+	"math"
+	"testing"
+	"time"
+	"watchAlert/pkg/provider"
+)
+
+func TestMeanStdDev(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     []float64
+		wantMean   float64
+		wantStdDev float64
+	}{
+		{name: "empty", values: nil},
+		{name: "single value", values: []float64{5}, wantMean: 5},
+		{name: "constant", values: []float64{3, 3, 3, 3}, wantMean: 3},
+		{name: "population stddev", values: []float64{2, 4, 4, 4, 5, 5, 7, 9}, wantMean: 5, wantStdDev: 2},
+		{name: "negative values", values: []float64{-1, 1}, wantMean: 0, wantStdDev: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mean, stddev := meanStdDev(tt.values)
+			if math.Abs(mean-tt.wantMean) > 1e-9 || math.Abs(stddev-tt.wantStdDev) > 1e-9 {
+				t.Fatalf("meanStdDev(%v) = (%v, %v), want (%v, %v)", tt.values, mean, stddev, tt.wantMean, tt.wantStdDev)
+			}
+		})
+	}
+}
+
+func TestHoltWinters(t *testing.T) {
+	// 周期为 4 的季节性序列
+	seasonal := func(seasons int, trend float64) []float64 {
+		pattern := []float64{10, 20, 30, 20}
+		var values []float64
+		for i := 0; i < seasons*len(pattern); i++ {
+			values = append(values, pattern[i%len(pattern)]+trend*float64(i))
+		}
+		return values
+	}
+
+	tests := []struct {
+		name         string
+		values       []float64
+		seasonLength int
+		wantOk       bool
+		wantExpected float64
+		tolerance    float64
+	}{
+		{name: "season length too short", values: seasonal(3, 0), seasonLength: 1},
+		{name: "less than two seasons", values: seasonal(3, 0)[:7], seasonLength: 4},
+		{name: "constant series", values: []float64{5, 5, 5, 5, 5, 5, 5, 5}, seasonLength: 4, wantOk: true, wantExpected: 5, tolerance: 1e-9},
+		{name: "pure seasonality predicts next phase", values: seasonal(6, 0), seasonLength: 4, wantOk: true, wantExpected: 10, tolerance: 0.5},
+		{name: "seasonality with trend", values: seasonal(6, 1), seasonLength: 4, wantOk: true, wantExpected: 10 + 24, tolerance: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline, ok := holtWinters(tt.values, tt.seasonLength)
+			if ok != tt.wantOk {
+				t.Fatalf("holtWinters ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if math.Abs(baseline.Expected-tt.wantExpected) > tt.tolerance {
+				t.Fatalf("expected = %v, want %v ± %v", baseline.Expected, tt.wantExpected, tt.tolerance)
+			}
+			if baseline.Sigma < 0 || math.IsNaN(baseline.Sigma) {
+				t.Fatalf("invalid sigma: %v", baseline.Sigma)
+			}
+		})
+	}
+}
+
+func TestAlignSeries(t *testing.T) {
+	const base = 1700000000
+	start := time.Unix(base, 0)
+	// offset 为相对 start 的秒数
+	point := func(offset, value float64) provider.Metrics {
+		return provider.Metrics{Timestamp: base + offset, Value: value}
+	}
+	pointMs := func(offset, value float64) provider.Metrics {
+		return provider.Metrics{Timestamp: (base + offset) * 1000, Value: value}
+	}
+
+	tests := []struct {
+		name         string
+		points       []provider.Metrics
+		wantValues   []float64
+		wantObserved []float64
+	}{
+		{name: "empty", points: nil},
+		{
+			name:         "aligned",
+			points:       []provider.Metrics{point(0, 1), point(10, 2), point(20, 3), point(30, 4)},
+			wantValues:   []float64{1, 2, 3, 4},
+			wantObserved: []float64{1, 2, 3, 4},
+		},
+		{
+			name:         "unordered with jitter",
+			points:       []provider.Metrics{point(31, 4), point(9, 2), point(1, 1), point(22, 3)},
+			wantValues:   []float64{1, 2, 3, 4},
+			wantObserved: []float64{1, 2, 3, 4},
+		},
+		{
+			name:         "gaps keep positions",
+			points:       []provider.Metrics{point(10, 2), point(30, 4)},
+			wantValues:   []float64{2, 2, 2, 4},
+			wantObserved: []float64{2, 4},
+		},
+		{
+			name:         "milliseconds and out of range",
+			points:       []provider.Metrics{point(-10, 9), pointMs(0, 1), pointMs(30, 4), point(100, 9)},
+			wantValues:   []float64{1, 1, 1, 4},
+			wantObserved: []float64{1, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, observed := alignSeries(tt.points, start, 10*time.Second, 4)
+			if !equalFloats(values, tt.wantValues) || !equalFloats(observed, tt.wantObserved) {
+				t.Fatalf("alignSeries = (%v, %v), want (%v, %v)", values, observed, tt.wantValues, tt.wantObserved)
+			}
+		})
+	}
+}
+
+func equalFloats(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,404 @@
+package eval
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+	"watchAlert/alert/process"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/provider"
+	"watchAlert/pkg/tools"
+)
+
+const (
+	// BacktestMaxMetricPoints 单条序列最多的评估点数，与 Prometheus query_range 限制一致
+	BacktestMaxMetricPoints = 11000
+	// BacktestMaxLogEvaluations 日志回测最多的查询次数，日志需按评估点逐次查询
+	BacktestMaxLogEvaluations = 200
+)
+
+// backtestEvalFunc 按数据源类型回放规则
+type backtestEvalFunc func(*ctx.Context, interface{}, string, models.AlertRule, *backtestSimulator) error
+
+// Backtest 使用历史数据回放规则，模拟持续时间、等级优先级及恢复等待时间，不会写入任何告警事件
+func Backtest(ctx *ctx.Context, rule models.AlertRule, datasourceIds []string, startAt, endAt, step, recoverWaitTime int64) (models.RuleBacktestResult, error) {
+	result := models.RuleBacktestResult{
+		StartAt:         startAt,
+		EndAt:           endAt,
+		RecoverWaitTime: recoverWaitTime,
+		Events:          []models.RuleBacktestEvent{},
+	}
+
+	if startAt <= 0 || endAt <= startAt {
+		return result, fmt.Errorf("回测时间范围无效")
+	}
+	if endAt > time.Now().Unix() {
+		result.EndAt = time.Now().Unix()
+	}
+	if len(datasourceIds) == 0 {
+		return result, fmt.Errorf("数据源不能为空")
+	}
+
+	if step <= 0 {
+		step = rule.EvalInterval
+		if rule.EvalTimeType == TimeTypeMillisecond {
+			step = rule.EvalInterval / 1000
+		}
+		if step <= 0 {
+			step = 1
+		}
+	}
+
+	var (
+		evalFunc  backtestEvalFunc
+		maxPoints int64
+	)
+	switch rule.DatasourceType {
+	case DatasourceTypePrometheus, DatasourceTypeVictoriaMetrics, DatasourceTypeGraphite, DatasourceTypeInfluxDB, DatasourceTypeOpenTSDB:
+		if rule.PrometheusConfig.IsAnomalyMode() {
+			return result, fmt.Errorf("异常检测模式的规则暂不支持回测")
+		}
+		evalFunc, maxPoints = backtestMetrics, BacktestMaxMetricPoints
+	case DatasourceTypeLoki, DatasourceTypeAliCloudSLS, DatasourceTypeVictoriaLogs:
+		evalFunc, maxPoints = backtestLogs, BacktestMaxLogEvaluations
+	default:
+		return result, fmt.Errorf("数据源类型 %s 不支持回测", rule.DatasourceType)
+	}
+
+	// 评估点过多时放大步长
+	if points := (result.EndAt - startAt) / step; points > maxPoints {
+		step = int64(math.Ceil(float64(result.EndAt-startAt) / float64(maxPoints)))
+	}
+	result.Step = step
+
+	for _, dsId := range datasourceIds {
+		cli, err := ctx.Redis.ProviderPools().GetClient(dsId)
+		if err != nil {
+			return result, err
+		}
+
+		sim := newBacktestSimulator(dsId, startAt, result.EndAt, step, recoverWaitTime)
+		if err := evalFunc(ctx, cli, dsId, rule, sim); err != nil {
+			return result, fmt.Errorf("数据源 %s 回测失败, %s", dsId, err.Error())
+		}
+
+		result.Events = append(result.Events, sim.finish()...)
+		result.Evaluations += sim.points()
+	}
+
+	sort.SliceStable(result.Events, func(i, j int) bool {
+		return result.Events[i].FiredAt < result.Events[j].FiredAt
+	})
+	result.Summary = summarizeBacktest(result.Events)
+
+	return result, nil
+}
+
+// backtestMetrics 通过 QueryRange 获取历史数据后按评估点回放
+func backtestMetrics(ctx *ctx.Context, cli interface{}, dsId string, rule models.AlertRule, sim *backtestSimulator) error {
+	metricsCli, ok := cli.(provider.MetricsFactoryProvider)
+	if !ok {
+		return fmt.Errorf("数据源不支持范围查询")
+	}
+
+	resQuery, err := metricsCli.QueryRange(rule.PrometheusConfig.PromQL, time.Unix(sim.start, 0), time.Unix(sim.end, 0), time.Duration(sim.step)*time.Second)
+	if err != nil {
+		return err
+	}
+
+	type series struct {
+		labels map[string]interface{}
+		values map[int64]float64
+	}
+	var (
+		seriesList []*series
+		seriesMap  = make(map[string]*series)
+	)
+	for _, v := range resQuery {
+		key := v.GetFingerprint()
+		s, ok := seriesMap[key]
+		if !ok {
+			s = &series{labels: v.GetMetric(), values: make(map[int64]float64)}
+			seriesMap[key] = s
+			seriesList = append(seriesList, s)
+		}
+		s.values[sim.index(backtestTimestamp(v.Timestamp))] = v.GetValue()
+	}
+
+	// 按优先级排序规则（P0 > P1 > P2）
+	type ruleExpr struct {
+		models.Rules
+		operator string
+		value    float64
+	}
+	var exprs []ruleExpr
+	for _, r := range sortRulesByPriority(rule.PrometheusConfig.Rules) {
+		operator, value, err := tools.ProcessRuleExpr(r.Expr)
+		if err != nil {
+			return err
+		}
+		exprs = append(exprs, ruleExpr{Rules: r, operator: operator, value: value})
+	}
+
+	for i := int64(0); i < sim.points(); i++ {
+		ts := sim.timestamp(i)
+		matched := make(map[string]struct{})
+		for _, s := range seriesList {
+			value, ok := s.values[i]
+			if !ok {
+				continue
+			}
+
+			// 同一序列满足多个等级时，低等级告警视为被高等级抑制
+			var higherMatched bool
+			for _, expr := range exprs {
+				if !process.EvalCondition(models.EvalCondition{
+					Operator:      expr.operator,
+					QueryValue:    value,
+					ExpectedValue: expr.value,
+				}) {
+					continue
+				}
+
+				fingerprintLabels := make(map[string]interface{})
+				for k, val := range s.labels {
+					fingerprintLabels[k] = val
+				}
+				fingerprintLabels["rule_id"] = rule.RuleId
+				fingerprintLabels["rule_name"] = rule.RuleName
+				fingerprintLabels["severity"] = expr.Severity
+				fingerprint := provider.Metrics{Metric: fingerprintLabels}.GetFingerprint()
+
+				labels := make(map[string]interface{})
+				for k, val := range s.labels {
+					labels[k] = val
+				}
+				labels["severity"] = expr.Severity
+
+				sim.observe(ts, fingerprint, expr.Severity, labels, value, expr.ForDuration, higherMatched)
+				matched[fingerprint] = struct{}{}
+				higherMatched = true
+			}
+		}
+		sim.advance(ts, matched)
+	}
+
+	return nil
+}
+
+// backtestLogs 按评估点逐次查询日志窗口内的数量
+func backtestLogs(ctx *ctx.Context, cli interface{}, dsId string, rule models.AlertRule, sim *backtestSimulator) error {
+	logsCli, ok := cli.(provider.LogsFactoryProvider)
+	if !ok {
+		return fmt.Errorf("数据源不支持日志查询")
+	}
+
+	operator, expected, err := tools.ProcessRuleExpr(rule.LogEvalCondition)
+	if err != nil {
+		return err
+	}
+
+	for i := int64(0); i < sim.points(); i++ {
+		ts := sim.timestamp(i)
+		curAt := time.Unix(ts, 0)
+
+		var queryOptions provider.LogQueryOptions
+		switch rule.DatasourceType {
+		case DatasourceTypeLoki:
+			queryOptions = provider.LogQueryOptions{
+				Loki:    provider.Loki{Query: rule.LokiConfig.LogQL},
+				StartAt: tools.ParserDuration(curAt, rule.LokiConfig.LogScope, "m").Unix(),
+				EndAt:   curAt.Unix(),
+			}
+		case DatasourceTypeAliCloudSLS:
+			queryOptions = provider.LogQueryOptions{
+				AliCloudSLS: provider.AliCloudSLS{
+					Query:    rule.AliCloudSLSConfig.LogQL,
+					Project:  rule.AliCloudSLSConfig.Project,
+					LogStore: rule.AliCloudSLSConfig.Logstore,
+				},
+				StartAt: int32(tools.ParserDuration(curAt, rule.AliCloudSLSConfig.LogScope, "m").Unix()),
+				EndAt:   int32(curAt.Unix()),
+			}
+		case DatasourceTypeVictoriaLogs:
+			queryOptions = provider.LogQueryOptions{
+				VictoriaLogs: provider.VictoriaLogs{
+					Query: rule.VictoriaLogsConfig.LogQL,
+					Limit: rule.VictoriaLogsConfig.Limit,
+				},
+				StartAt: int32(tools.ParserDuration(curAt, rule.VictoriaLogsConfig.LogScope, "m").Unix()),
+				EndAt:   int32(curAt.Unix()),
+			}
+		}
+
+		log, count, err := logsCli.Query(queryOptions)
+		if err != nil {
+			return err
+		}
+
+		matched := make(map[string]struct{})
+		if count > 0 && process.EvalCondition(models.EvalCondition{
+			Operator:      operator,
+			QueryValue:    float64(count),
+			ExpectedValue: expected,
+		}) {
+			// 唯一指纹基于 RuleId
+			fingerprint := log.GenerateFingerprint(rule.RuleId)
+			sim.observe(ts, fingerprint, rule.Severity, map[string]interface{}{"severity": rule.Severity}, float64(count), 0, false)
+			matched[fingerprint] = struct{}{}
+		}
+		sim.advance(ts, matched)
+	}
+
+	return nil
+}
+
+// backtestTimestamp Prometheus 返回毫秒时间戳，VictoriaMetrics 返回秒级时间戳
+func backtestTimestamp(ts float64) int64 {
+	if ts > 1e11 {
+		return int64(ts / 1000)
+	}
+	return int64(ts)
+}
+
+// backtestState 回测中单个指纹的状态
+type backtestState struct {
+	event          models.RuleBacktestEvent
+	firing         bool
+	pendingRecover int64 // 进入待恢复状态的时间
+}
+
+// backtestSimulator 按评估点模拟 预告警 -> 告警 -> 待恢复 -> 已恢复 的状态流转
+type backtestSimulator struct {
+	datasourceId    string
+	start           int64
+	end             int64
+	step            int64
+	recoverWaitTime int64
+	states          map[string]*backtestState
+	events          []models.RuleBacktestEvent
+}
+
+func newBacktestSimulator(datasourceId string, start, end, step, recoverWaitTime int64) *backtestSimulator {
+	return &backtestSimulator{
+		datasourceId:    datasourceId,
+		start:           start,
+		end:             end,
+		step:            step,
+		recoverWaitTime: recoverWaitTime,
+		states:          make(map[string]*backtestState),
+	}
+}
+
+func (s *backtestSimulator) points() int64 {
+	return (s.end-s.start)/s.step + 1
+}
+
+func (s *backtestSimulator) timestamp(index int64) int64 {
+	return s.start + index*s.step
+}
+
+func (s *backtestSimulator) index(ts int64) int64 {
+	return int64(math.Round(float64(ts-s.start) / float64(s.step)))
+}
+
+// observe 记录评估点满足告警条件的事件
+func (s *backtestSimulator) observe(ts int64, fingerprint, severity string, labels map[string]interface{}, value float64, forDuration int64, suppressed bool) {
+	state, ok := s.states[fingerprint]
+	if !ok {
+		state = &backtestState{
+			event: models.RuleBacktestEvent{
+				DatasourceId: s.datasourceId,
+				Fingerprint:  fingerprint,
+				Severity:     severity,
+				Labels:       labels,
+				PendingAt:    ts,
+				FirstValue:   value,
+				MaxValue:     value,
+			},
+		}
+		s.states[fingerprint] = state
+	}
+
+	if value > state.event.MaxValue {
+		state.event.MaxValue = value
+	}
+
+	// 待恢复期间再次触发，回到告警状态
+	state.pendingRecover = 0
+
+	// 与 IsArriveForDuration 保持一致
+	if !state.firing && ts-state.event.PendingAt > forDuration {
+		state.firing = true
+		state.event.FiredAt = ts
+		state.event.Suppressed = suppressed
+	}
+}
+
+// advance 处理当前评估点未满足条件的事件
+func (s *backtestSimulator) advance(ts int64, matched map[string]struct{}) {
+	for fingerprint, state := range s.states {
+		if _, ok := matched[fingerprint]; ok {
+			continue
+		}
+
+		// 预告警状态的事件直接移除
+		if !state.firing {
+			delete(s.states, fingerprint)
+			continue
+		}
+
+		if state.pendingRecover == 0 {
+			state.pendingRecover = ts
+			continue
+		}
+
+		if ts >= state.pendingRecover+s.recoverWaitTime {
+			state.event.RecoveredAt = ts
+			state.event.Duration = ts - state.event.FiredAt
+			s.events = append(s.events, state.event)
+			delete(s.states, fingerprint)
+		}
+	}
+}
+
+// finish 结束回测，回测结束时仍在告警的事件一并返回
+func (s *backtestSimulator) finish() []models.RuleBacktestEvent {
+	for _, state := range s.states {
+		if !state.firing {
+			continue
+		}
+		state.event.Duration = s.end - state.event.FiredAt
+		s.events = append(s.events, state.event)
+	}
+
+	return s.events
+}
+
+func summarizeBacktest(events []models.RuleBacktestEvent) models.RuleBacktestSummary {
+	summary := models.RuleBacktestSummary{
+		Severities: make(map[string]int64),
+	}
+
+	var recovered, totalDuration int64
+	for _, event := range events {
+		summary.Total++
+		summary.Severities[event.Severity]++
+		if !event.Suppressed {
+			summary.Notified++
+		}
+		if event.RecoveredAt == 0 {
+			summary.StillFiring++
+			continue
+		}
+		recovered++
+		totalDuration += event.Duration
+	}
+	if recovered > 0 {
+		summary.AvgDuration = totalDuration / recovered
+	}
+
+	return summary
+}
@@ -0,0 +1,139 @@
+package eval
+
+import (
+	"testing"
+	"watchAlert/internal/models"
+)
+
+func TestBacktestTimestamp(t *testing.T) {
+	tests := []struct {
+		name string
+		ts   float64
+		want int64
+	}{
+		{name: "seconds", ts: 1700000000, want: 1700000000},
+		{name: "seconds with fraction", ts: 1700000000.5, want: 1700000000},
+		{name: "milliseconds", ts: 1700000000123, want: 1700000000},
+		{name: "zero", ts: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backtestTimestamp(tt.ts); got != tt.want {
+				t.Fatalf("backtestTimestamp(%v) = %d, want %d", tt.ts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBacktestSimulatorIndex(t *testing.T) {
+	sim := newBacktestSimulator("ds", 1000, 1100, 10, 0)
+	if sim.points() != 11 {
+		t.Fatalf("points = %d, want 11", sim.points())
+	}
+	if sim.timestamp(3) != 1030 {
+		t.Fatalf("timestamp(3) = %d, want 1030", sim.timestamp(3))
+	}
+	// 数据点时间戳与评估点存在偏差时按最近的评估点对齐
+	if sim.index(1034) != 3 || sim.index(1036) != 4 {
+		t.Fatalf("index(1034) = %d, index(1036) = %d", sim.index(1034), sim.index(1036))
+	}
+}
+
+func TestBacktestSimulator(t *testing.T) {
+	tests := []struct {
+		name            string
+		matches         []bool // 各评估点是否满足告警条件，未列出的评估点视为不满足
+		forDuration     int64
+		recoverWaitTime int64
+		want            []models.RuleBacktestEvent
+	}{
+		{
+			name:        "fires after for duration and recovers",
+			matches:     []bool{true, true, true, true, true},
+			forDuration: 15,
+			want:        []models.RuleBacktestEvent{{PendingAt: 1000, FiredAt: 1020, RecoveredAt: 1060, Duration: 40}},
+		},
+		{
+			name:        "spike shorter than for duration does not fire",
+			matches:     []bool{true, true},
+			forDuration: 15,
+		},
+		{
+			name:            "refire during recover wait keeps one event",
+			matches:         []bool{true, true, true, false, true},
+			recoverWaitTime: 30,
+			want:            []models.RuleBacktestEvent{{PendingAt: 1000, FiredAt: 1010, RecoveredAt: 1080, Duration: 70}},
+		},
+		{
+			name:    "still firing at end",
+			matches: []bool{true, true, true, true, true, true, true, true, true, true, true},
+			want:    []models.RuleBacktestEvent{{PendingAt: 1000, FiredAt: 1010, Duration: 90}},
+		},
+		{
+			name:    "separate firings produce separate events",
+			matches: []bool{true, true, false, false, false, true, true},
+			want: []models.RuleBacktestEvent{
+				{PendingAt: 1000, FiredAt: 1010, RecoveredAt: 1030, Duration: 20},
+				{PendingAt: 1050, FiredAt: 1060, RecoveredAt: 1080, Duration: 20},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := newBacktestSimulator("ds", 1000, 1100, 10, tt.recoverWaitTime)
+			for i := int64(0); i < sim.points(); i++ {
+				ts := sim.timestamp(i)
+				matched := make(map[string]struct{})
+				if i < int64(len(tt.matches)) && tt.matches[i] {
+					sim.observe(ts, "fp", "P1", nil, float64(i), tt.forDuration, false)
+					matched["fp"] = struct{}{}
+				}
+				sim.advance(ts, matched)
+			}
+
+			events := sim.finish()
+			if len(events) != len(tt.want) {
+				t.Fatalf("got %d events, want %d: %+v", len(events), len(tt.want), events)
+			}
+			for i, want := range tt.want {
+				got := events[i]
+				if got.PendingAt != want.PendingAt || got.FiredAt != want.FiredAt || got.RecoveredAt != want.RecoveredAt || got.Duration != want.Duration {
+					t.Fatalf("event %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBacktestSimulatorMaxValue(t *testing.T) {
+	sim := newBacktestSimulator("ds", 0, 20, 10, 0)
+	for i, value := range []float64{3, 9, 5} {
+		sim.observe(sim.timestamp(int64(i)), "fp", "P0", nil, value, 0, i == 0)
+		sim.advance(sim.timestamp(int64(i)), map[string]struct{}{"fp": {}})
+	}
+
+	events := sim.finish()
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	if events[0].FirstValue != 3 || events[0].MaxValue != 9 || events[0].Suppressed {
+		t.Fatalf("unexpected event: %+v", events[0])
+	}
+}
+
+func TestSummarizeBacktest(t *testing.T) {
+	summary := summarizeBacktest([]models.RuleBacktestEvent{
+		{Severity: "P0", RecoveredAt: 100, Duration: 60},
+		{Severity: "P1", RecoveredAt: 200, Duration: 120, Suppressed: true},
+		{Severity: "P1"},
+	})
+
+	if summary.Total != 3 || summary.Notified != 2 || summary.StillFiring != 1 || summary.AvgDuration != 90 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if summary.Severities["P0"] != 1 || summary.Severities["P1"] != 2 {
+		t.Fatalf("unexpected severities: %v", summary.Severities)
+	}
+}
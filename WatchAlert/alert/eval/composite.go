@@ -0,0 +1,254 @@
+package eval
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"watchAlert/alert/process"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/provider"
+	"watchAlert/pkg/tools"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// compositeResult 单个条件在某个关联分组上的结果
+type compositeResult struct {
+	firing bool
+	value  float64
+	labels map[string]interface{}
+}
+
+// composite 执行组合规则，按关联标签分组计算各条件，表达式成立的分组产生告警事件，返回当前告警的指纹
+func (t *AlertRule) composite(rule models.AlertRule) ([]string, error) {
+	config := rule.CompositeConfig
+	expr, err := tools.ParseBoolExpr(config.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		results = make(map[string]map[string]compositeResult, len(config.Conditions))
+		groups  = make(map[string]map[string]interface{})
+	)
+	for _, condition := range config.Conditions {
+		var res []compositeResult
+		if condition.RuleId != "" {
+			res, err = t.compositeRuleResults(rule, condition)
+		} else {
+			res, err = t.compositeQueryResults(rule, condition)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("condition %s: %w", condition.Alias, err)
+		}
+
+		grouped := make(map[string]compositeResult)
+		for _, r := range res {
+			key, joinLabels := compositeJoinKey(config.JoinLabels, r.labels)
+			// 同一分组内任一结果成立即视为条件成立
+			if exist, ok := grouped[key]; ok && exist.firing && !r.firing {
+				continue
+			}
+			grouped[key] = r
+			groups[key] = joinLabels
+		}
+		results[condition.Alias] = grouped
+	}
+
+	var fingerprints []string
+	for key, joinLabels := range groups {
+		firing := expr.Eval(func(alias string) bool {
+			return results[alias][key].firing
+		})
+		if !firing {
+			continue
+		}
+		fingerprints = append(fingerprints, t.pushCompositeEvent(rule, joinLabels, key, results))
+	}
+
+	return fingerprints, nil
+}
+
+// compositeRuleResults 引用规则的结果，取该规则在其故障中心未恢复的事件，告警中、静默中、抑制中视为成立
+func (t *AlertRule) compositeRuleResults(rule models.AlertRule, condition models.CompositeCondition) ([]compositeResult, error) {
+	refRule, err := t.ctx.DB.Rule().GetTenantRule(rule.TenantId, condition.RuleId)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s not found in tenant %s: %w", condition.RuleId, rule.TenantId, err)
+	}
+	if refRule.IsCompositeRule() {
+		return nil, fmt.Errorf("rule %s is a composite rule", condition.RuleId)
+	}
+
+	events, err := t.ctx.Redis.Alert().GetAllEvents(models.BuildAlertEventCacheKey(rule.TenantId, refRule.FaultCenterId))
+	if err != nil {
+		return nil, err
+	}
+
+	var results []compositeResult
+	for _, event := range events {
+		if event.RuleId != refRule.RuleId || event.IsRecovered {
+			continue
+		}
+		if state, ok := event.Labels["alertstate"]; ok && (state == models.AlertStateNoData || state == models.AlertStateDatasourceError) {
+			continue
+		}
+
+		status := event.GetEventStatus()
+		results = append(results, compositeResult{
+			firing: status == models.StateAlerting || status == models.StateSilenced || status == models.StateInhibited,
+			value:  compositeValue(event.Labels["value"]),
+			labels: event.Labels,
+		})
+	}
+
+	return results, nil
+}
+
+// compositeQueryResults 直接查询指标数据源，满足条件表达式的序列视为成立，仅允许查询同一租户的数据源
+func (t *AlertRule) compositeQueryResults(rule models.AlertRule, condition models.CompositeCondition) ([]compositeResult, error) {
+	operator, expectedValue, err := tools.ProcessRuleExpr(condition.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	ds, err := t.ctx.DB.Datasource().GetInstance(condition.DatasourceId)
+	if err != nil {
+		return nil, err
+	}
+	if ds.TenantId != rule.TenantId {
+		return nil, fmt.Errorf("datasource %s does not belong to tenant %s", condition.DatasourceId, rule.TenantId)
+	}
+
+	cli, err := t.ctx.Redis.ProviderPools().GetClient(condition.DatasourceId)
+	if err != nil {
+		return nil, err
+	}
+	metricsCli, ok := cli.(provider.MetricsFactoryProvider)
+	if !ok {
+		return nil, fmt.Errorf("invalid metrics client, datasource: %s", condition.DatasourceId)
+	}
+
+	res, err := metricsCli.Query(condition.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]compositeResult, 0, len(res))
+	for _, v := range res {
+		results = append(results, compositeResult{
+			firing: process.EvalCondition(models.EvalCondition{
+				Operator:      operator,
+				QueryValue:    v.Value,
+				ExpectedValue: expectedValue,
+			}),
+			value:  v.Value,
+			labels: v.Metric,
+		})
+	}
+
+	return results, nil
+}
+
+// compositeJoinKey 按关联标签生成分组键
+func compositeJoinKey(joinLabels []string, labels map[string]interface{}) (string, map[string]interface{}) {
+	values := make(map[string]interface{}, len(joinLabels))
+	parts := make([]string, 0, len(joinLabels))
+	for _, name := range joinLabels {
+		v := fmt.Sprintf("%v", labels[name])
+		if labels[name] == nil {
+			v = ""
+		}
+		values[name] = v
+		parts = append(parts, name+"="+v)
+	}
+	return strings.Join(parts, ","), values
+}
+
+func compositeValue(v interface{}) float64 {
+	switch value := v.(type) {
+	case float64:
+		return value
+	case int:
+		return float64(value)
+	case int64:
+		return float64(value)
+	case string:
+		f, _ := strconv.ParseFloat(value, 64)
+		return f
+	default:
+		return 0
+	}
+}
+
+// pushCompositeEvent 推送组合规则事件，标签携带关联标签及各条件的当前值
+func (t *AlertRule) pushCompositeEvent(rule models.AlertRule, joinLabels map[string]interface{}, key string, results map[string]map[string]compositeResult) string {
+	fingerprintLabels := map[string]interface{}{"rule_id": rule.RuleId}
+	for k, v := range joinLabels {
+		fingerprintLabels[k] = v
+	}
+	fingerprint := provider.Metrics{Metric: fingerprintLabels}.GetFingerprint()
+
+	conditions := append([]models.CompositeCondition(nil), rule.CompositeConfig.Conditions...)
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i].Alias < conditions[j].Alias })
+
+	lines := []string{fmt.Sprintf("组合条件 %s 成立", rule.CompositeConfig.Expr)}
+	if key != "" {
+		lines = append(lines, "关联标签: "+key)
+	}
+	for _, condition := range conditions {
+		res, ok := results[condition.Alias][key]
+		state := "无数据"
+		if ok {
+			state = "未满足"
+			if res.firing {
+				state = "满足"
+			}
+		}
+
+		source := condition.Query + " " + condition.Expr
+		if condition.RuleId != "" {
+			source = "规则 " + condition.RuleId
+		}
+		lines = append(lines, fmt.Sprintf("- %s(%s): %s, 当前值: %v", condition.Alias, source, state, res.value))
+	}
+
+	event := process.BuildEvent(rule, func() map[string]interface{} {
+		labels := map[string]interface{}{
+			"rule_name":   rule.RuleName,
+			"severity":    rule.Severity,
+			"fingerprint": fingerprint,
+		}
+		for k, v := range joinLabels {
+			labels[k] = v
+		}
+		for _, condition := range conditions {
+			if res, ok := results[condition.Alias][key]; ok {
+				labels[condition.Alias+"_value"] = res.value
+			}
+		}
+		for k, v := range rule.ExternalLabels {
+			labels[k] = v
+		}
+		return labels
+	})
+	event.Fingerprint = fingerprint
+	event.Annotations = strings.Join(lines, "\n")
+
+	process.PushEventToFaultCenter(t.ctx, &event)
+	return fingerprint
+}
+
+// evalComposite 执行组合规则，条件查询失败时保持现有告警，避免误恢复
+func (t *AlertRule) evalComposite(rule models.AlertRule) {
+	fingerprints, err := t.composite(rule)
+	if err != nil {
+		logc.Errorf(t.ctx.Ctx, "Composite rule %s(%s) eval failed: %s", rule.RuleName, rule.RuleId, err.Error())
+		return
+	}
+
+	t.Recover(rule.TenantId, rule.RuleId,
+		models.BuildAlertEventCacheKey(rule.TenantId, rule.FaultCenterId),
+		models.BuildFaultCenterInfoCacheKey(rule.TenantId, rule.FaultCenterId),
+		fingerprints)
+}
@@ -2,6 +2,7 @@ package eval
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime/debug"
 	"slices"
@@ -22,12 +23,19 @@ const (
 	// 数据源类型
 	DatasourceTypePrometheus      = "Prometheus"
 	DatasourceTypeVictoriaMetrics = "VictoriaMetrics"
+	DatasourceTypeGraphite        = "Graphite"
+	DatasourceTypeInfluxDB        = "InfluxDB"
+	DatasourceTypeOpenTSDB        = "OpenTSDB"
 	DatasourceTypeAliCloudSLS     = "AliCloudSLS"
 	DatasourceTypeLoki            = "Loki"
 	DatasourceTypeElasticSearch   = "ElasticSearch"
 	DatasourceTypeVictoriaLogs    = "VictoriaLogs"
 	DatasourceTypeClickHouse      = "ClickHouse"
+	DatasourceTypeMySQL           = "MySQL"
+	DatasourceTypePostgreSQL      = "PostgreSQL"
 	DatasourceTypeJaeger          = "Jaeger"
+	DatasourceTypeTempo           = "Tempo"
+	DatasourceTypeZipkin          = "Zipkin"
 	DatasourceTypeCloudWatch      = "CloudWatch"
 	DatasourceTypeKubernetesEvent = "KubernetesEvent"
 
@@ -42,16 +50,26 @@ const (
 	TaskChannelBufferSize = 1
 )
 
+// errNoData 查询成功但没有返回任何数据，按规则的无数据策略处理
+var errNoData = errors.New("no data")
+
 // 数据源处理器映射
-var datasourceHandlers = map[string]func(*ctx.Context, string, string, models.AlertRule) []string{
+var datasourceHandlers = map[string]func(*ctx.Context, string, string, models.AlertRule) ([]string, error){
 	DatasourceTypePrometheus:      metrics,
 	DatasourceTypeVictoriaMetrics: metrics,
+	DatasourceTypeGraphite:        metrics,
+	DatasourceTypeInfluxDB:        metrics,
+	DatasourceTypeOpenTSDB:        metrics,
 	DatasourceTypeAliCloudSLS:     logs,
 	DatasourceTypeLoki:            logs,
 	DatasourceTypeElasticSearch:   logs,
 	DatasourceTypeVictoriaLogs:    logs,
 	DatasourceTypeClickHouse:      logs,
+	DatasourceTypeMySQL:           sqlQuery,
+	DatasourceTypePostgreSQL:      sqlQuery,
 	DatasourceTypeJaeger:          traces,
+	DatasourceTypeTempo:           traces,
+	DatasourceTypeZipkin:          traces,
 	DatasourceTypeCloudWatch:      cloudWatch,
 	DatasourceTypeKubernetesEvent: kubernetesEvent,
 }
@@ -70,6 +88,8 @@ type (
 	// AlertRule 告警规则
 	AlertRule struct {
 		ctx *ctx.Context
+		// 规则在各数据源上连续无数据的评估次数，key 为 ruleId:datasourceId
+		noDataTimes sync.Map
 	}
 )
 
@@ -142,6 +162,18 @@ func (t *AlertRule) executeTask(rule models.AlertRule, taskChan chan struct{}) {
 		return
 	}
 
+	// 记录规则只写入预计算结果，不产生告警事件
+	if rule.IsRecordingRule() {
+		t.record(rule)
+		return
+	}
+
+	// 组合规则基于其他规则或查询的结果计算，不直接关联数据源
+	if rule.IsCompositeRule() {
+		t.evalComposite(rule)
+		return
+	}
+
 	// 并发处理数据源
 	curFingerprints := t.processDatasources(rule)
 
@@ -192,18 +224,18 @@ func (t *AlertRule) processSingleDatasource(dsId string, rule models.AlertRule)
 		return nil
 	}
 
-	// 检查数据源健康状态
-	if ok, _ := provider.CheckDatasourceHealth(instance); !ok {
-		logc.Errorf(t.ctx.Ctx, "Datasource %s is unhealthy", dsId)
-		return nil
-	}
-
 	// 检查数据源是否启用
 	if !*instance.Enabled {
 		logc.Errorf(t.ctx.Ctx, "Datasource %s is disabled", dsId)
 		return nil
 	}
 
+	// 检查数据源健康状态，不可用时按数据源异常策略处理，KeepLast 策略可避免已有告警被误恢复
+	if ok, err := provider.CheckDatasourceHealth(instance); !ok {
+		logc.Errorf(t.ctx.Ctx, "Datasource %s is unhealthy", dsId)
+		return t.handleDatasourceError(instance, rule, err)
+	}
+
 	// 调用处理器
 	handler, exists := datasourceHandlers[rule.DatasourceType]
 	if !exists {
@@ -211,7 +243,16 @@ func (t *AlertRule) processSingleDatasource(dsId string, rule models.AlertRule)
 		return nil
 	}
 
-	return handler(t.ctx, dsId, instance.Type, rule)
+	fingerprints, err := handler(t.ctx, dsId, instance.Type, rule)
+	switch {
+	case errors.Is(err, errNoData):
+		return t.handleNoData(instance, rule)
+	case err != nil:
+		return t.handleDatasourceError(instance, rule, err)
+	}
+
+	t.noDataTimes.Delete(noDataKey(rule.RuleId, dsId))
+	return fingerprints
 }
 
 // getEvalTimeDuration 获取评估时间间隔
@@ -292,6 +333,8 @@ func (t *AlertRule) Recover(tenantId, ruleId string, eventCacheKey models.AlertE
 				logc.Errorf(t.ctx.Ctx, "Failed to transition to「alerting」state for fingerprint %s: %v", fingerprint, err)
 				continue
 			}
+			// 待恢复期间重新触发，同样记录状态切换，用于抖动检测
+			process.RecordFlapTransition(t.ctx, newEvent)
 			t.ctx.Redis.Alert().PushAlertEvent(newEvent)
 			t.ctx.Redis.PendingRecover().Delete(tenantId, ruleId, fingerprint)
 		}
@@ -338,6 +381,8 @@ func (t *AlertRule) Recover(tenantId, ruleId string, eventCacheKey models.AlertE
 				logc.Errorf(t.ctx.Ctx, "Failed to transition to recovered state for fingerprint %s: %v", fingerprint, err)
 				continue
 			}
+			// 记录状态切换，用于抖动检测
+			process.RecordFlapTransition(t.ctx, newEvent)
 			// 更新告警事件到 Redis
 			t.ctx.Redis.Alert().PushAlertEvent(newEvent)
 			// 推送事件到故障中心，触发状态变化处理（包括发布告警恢复事件）
@@ -0,0 +1,131 @@
+package eval
+
+import (
+	"fmt"
+	"watchAlert/alert/process"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/provider"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+func noDataKey(ruleId, datasourceId string) string {
+	return ruleId + ":" + datasourceId
+}
+
+// incNoDataTimes 累加连续无数据次数并返回当前值
+func (t *AlertRule) incNoDataTimes(key string) int64 {
+	var times int64 = 1
+	if v, ok := t.noDataTimes.Load(key); ok {
+		times = v.(int64) + 1
+	}
+	t.noDataTimes.Store(key, times)
+	return times
+}
+
+// handleNoData 按规则的无数据策略处理查询无数据的情况，返回需要保持告警的指纹
+func (t *AlertRule) handleNoData(instance models.AlertDataSource, rule models.AlertRule) []string {
+	times := t.incNoDataTimes(noDataKey(rule.RuleId, instance.ID))
+	annotations := fmt.Sprintf("规则 %s 在数据源 %s 上连续 %d 次评估无数据", rule.RuleName, instance.Name, times)
+
+	switch rule.NoDataConfig.GetPolicy() {
+	case models.NoDataPolicyKeepLast:
+		return t.activeFingerprints(instance.ID, rule)
+	case models.NoDataPolicyAlerting:
+		if fingerprints := t.activeFingerprints(instance.ID, rule); len(fingerprints) > 0 {
+			return fingerprints
+		}
+		return []string{t.pushStateEvent(instance, rule, models.AlertStateNoData, annotations)}
+	case models.NoDataPolicyNoData:
+		if times < rule.NoDataConfig.GetTimes() {
+			return nil
+		}
+		return []string{t.pushStateEvent(instance, rule, models.AlertStateNoData, annotations)}
+	default:
+		return nil
+	}
+}
+
+// handleDatasourceError 数据源不可用或查询失败时按规则的异常策略处理，返回需要保持告警的指纹
+func (t *AlertRule) handleDatasourceError(instance models.AlertDataSource, rule models.AlertRule, err error) []string {
+	if err != nil {
+		logc.Errorf(t.ctx.Ctx, "Rule %s query datasource %s failed: %s", rule.RuleId, instance.ID, err.Error())
+	}
+
+	switch rule.NoDataConfig.GetErrorPolicy() {
+	case models.NoDataPolicyKeepLast:
+		return t.activeFingerprints(instance.ID, rule)
+	case models.NoDataPolicyAlerting:
+		annotations := fmt.Sprintf("规则 %s 查询数据源 %s 异常", rule.RuleName, instance.Name)
+		if err != nil {
+			annotations = fmt.Sprintf("%s: %s", annotations, err.Error())
+		}
+		fingerprints := t.activeFingerprints(instance.ID, rule)
+		return append(fingerprints, t.pushStateEvent(instance, rule, models.AlertStateDatasourceError, annotations))
+	default:
+		return nil
+	}
+}
+
+// activeFingerprints 获取规则在该数据源上未恢复的告警指纹，不包含无数据、数据源异常事件
+func (t *AlertRule) activeFingerprints(datasourceId string, rule models.AlertRule) []string {
+	events, err := t.ctx.Redis.Alert().GetAllEvents(models.BuildAlertEventCacheKey(rule.TenantId, rule.FaultCenterId))
+	if err != nil {
+		logc.Errorf(t.ctx.Ctx, "Get fault center events failed: %s", err.Error())
+		return nil
+	}
+
+	var fingerprints []string
+	for fingerprint, event := range events {
+		if event.RuleId != rule.RuleId || event.DatasourceId != datasourceId {
+			continue
+		}
+		if event.IsRecovered || event.Status == models.StateRecovered || event.Status == models.StatePendingRecovery {
+			continue
+		}
+		if state, ok := event.Labels["alertstate"]; ok && (state == models.AlertStateNoData || state == models.AlertStateDatasourceError) {
+			continue
+		}
+		fingerprints = append(fingerprints, fingerprint)
+	}
+
+	return fingerprints
+}
+
+// pushStateEvent 推送无数据、数据源异常事件，指纹由规则、数据源和状态生成，数据恢复后按恢复流程处理
+func (t *AlertRule) pushStateEvent(instance models.AlertDataSource, rule models.AlertRule, alertState, annotations string) string {
+	fingerprint := provider.Metrics{Metric: map[string]interface{}{
+		"rule_id":       rule.RuleId,
+		"datasource_id": instance.ID,
+		"alertstate":    alertState,
+	}}.GetFingerprint()
+
+	severity := rule.Severity
+	if severity == "" && len(rule.PrometheusConfig.Rules) > 0 {
+		severity = sortRulesByPriority(rule.PrometheusConfig.Rules)[0].Severity
+	}
+
+	event := process.BuildEvent(rule, func() map[string]interface{} {
+		labels := map[string]interface{}{
+			"rule_name":     rule.RuleName,
+			"severity":      severity,
+			"fingerprint":   fingerprint,
+			"alertstate":    alertState,
+			"datasource_id": instance.ID,
+		}
+		for ek, ev := range instance.Labels {
+			labels[ek] = ev
+		}
+		for ek, ev := range rule.ExternalLabels {
+			labels[ek] = ev
+		}
+		return labels
+	})
+	event.DatasourceId = instance.ID
+	event.Fingerprint = fingerprint
+	event.Severity = severity
+	event.Annotations = annotations
+
+	process.PushEventToFaultCenter(t.ctx, &event)
+	return fingerprint
+}
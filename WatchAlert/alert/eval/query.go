@@ -2,6 +2,7 @@ package eval
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 	"time"
@@ -17,8 +18,8 @@ import (
 	v1 "k8s.io/api/core/v1"
 )
 
-// Metrics 包含 Prometheus、VictoriaMetrics 数据源
-func metrics(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) []string {
+// Metrics 包含 Prometheus、VictoriaMetrics、Graphite、InfluxDB、OpenTSDB 数据源
+func metrics(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) ([]string, error) {
 	pools := ctx.Redis.ProviderPools()
 	var (
 		resQuery       []provider.Metrics
@@ -32,7 +33,7 @@ func metrics(ctx *ctx.Context, datasourceId, datasourceType string, rule models.
 	cli, err := pools.GetClient(datasourceId)
 	if err != nil {
 		logc.Errorf(ctx.Ctx, err.Error())
-		return nil
+		return nil, err
 	}
 
 	switch datasourceType {
@@ -40,7 +41,7 @@ func metrics(ctx *ctx.Context, datasourceId, datasourceType string, rule models.
 		resQuery, err = cli.(provider.PrometheusProvider).Query(rule.PrometheusConfig.PromQL)
 		if err != nil {
 			logc.Error(ctx.Ctx, err.Error())
-			return nil
+			return nil, err
 		}
 
 		externalLabels = cli.(provider.PrometheusProvider).GetExternalLabels()
@@ -48,23 +49,63 @@ func metrics(ctx *ctx.Context, datasourceId, datasourceType string, rule models.
 		resQuery, err = cli.(provider.VictoriaMetricsProvider).Query(rule.PrometheusConfig.PromQL)
 		if err != nil {
 			logc.Error(ctx.Ctx, err.Error())
-			return nil
+			return nil, err
 		}
 
 		externalLabels = cli.(provider.VictoriaMetricsProvider).GetExternalLabels()
+	case provider.GraphiteDsProvider, provider.InfluxDBDsProvider, provider.OpenTSDBDsProvider:
+		// 查询语句复用 PromQL 字段，分别为 Graphite target、InfluxQL/Flux 语句、OpenTSDB m 表达式
+		metricsCli, ok := cli.(provider.MetricsFactoryProvider)
+		if !ok {
+			logc.Errorf(ctx.Ctx, "Invalid metrics client, type: %s", datasourceType)
+			return nil, fmt.Errorf("invalid metrics client, type: %s", datasourceType)
+		}
+		resQuery, err = metricsCli.Query(rule.PrometheusConfig.PromQL)
+		if err != nil {
+			logc.Error(ctx.Ctx, err.Error())
+			return nil, err
+		}
+
+		externalLabels = metricsCli.GetExternalLabels()
 	default:
 		logc.Errorf(ctx.Ctx, fmt.Sprintf("Unsupported metrics type, type: %s", datasourceType))
-		return nil
+		return nil, fmt.Errorf("unsupported metrics type: %s", datasourceType)
 	}
 
-	if resQuery == nil {
-		return nil
+	if len(resQuery) == 0 {
+		return nil, errNoData
+	}
+
+	// 异常检测模式，按序列计算历史基线
+	var baselines map[string]anomalyBaseline
+	if rule.PrometheusConfig.IsAnomalyMode() {
+		if !provider.SupportsRangeStep(datasourceType) {
+			logc.Errorf(ctx.Ctx, "数据源不支持异常检测模式, ruleId: %s, type: %s", rule.RuleId, datasourceType)
+			return nil, fmt.Errorf("anomaly mode is not supported by datasource type: %s", datasourceType)
+		}
+		metricsCli, ok := cli.(provider.MetricsFactoryProvider)
+		if !ok {
+			logc.Errorf(ctx.Ctx, "Invalid metrics client, type: %s", datasourceType)
+			return nil, fmt.Errorf("invalid metrics client, type: %s", datasourceType)
+		}
+		baselines, err = anomalyBaselines(metricsCli, rule.PrometheusConfig.AnomalyConfig, rule.PrometheusConfig.PromQL, time.Now())
+		if err != nil {
+			logc.Error(ctx.Ctx, err.Error())
+			return nil, err
+		}
 	}
 
 	// 按优先级排序规则（P0 > P1 > P2）
 	rules := sortRulesByPriority(rule.PrometheusConfig.Rules)
 
 	for _, v := range resQuery {
+		seriesFingerprint := v.GetFingerprint()
+		if _, ok := baselines[seriesFingerprint]; rule.PrometheusConfig.IsAnomalyMode() && !ok {
+			// 历史数据不足，无法计算基线，该序列本轮不参与评估
+			logc.Infof(ctx.Ctx, "异常检测历史数据不足, 跳过序列, ruleId: %s, metric: %v", rule.RuleId, v.GetMetric())
+			continue
+		}
+
 		// 避免共享引用导致的指纹不一致问题
 		metricLabels := make(map[string]interface{})
 		for k, val := range v.GetMetric() {
@@ -82,10 +123,37 @@ func metrics(ctx *ctx.Context, datasourceId, datasourceType string, rule models.
 		// 遍历按优先级排序后的规则
 		for _, ruleExpr := range rules {
 			fingerprintLabels["severity"] = ruleExpr.Severity
-			operator, value, err := tools.ProcessRuleExpr(ruleExpr.Expr)
-			if err != nil {
-				logc.Errorf(ctx.Ctx, err.Error())
-				continue
+
+			var (
+				firing        bool
+				searchQL      string
+				anomalyLabels map[string]interface{}
+			)
+			if rule.PrometheusConfig.IsAnomalyMode() {
+				baseline := baselines[seriesFingerprint]
+				anomalyConfig := rule.PrometheusConfig.AnomalyConfig
+				band := baseline.band(v.Value, ruleExpr.GetSensitivity(), anomalyConfig.GetDirection())
+				firing = band.Anomalous
+				searchQL = fmt.Sprintf("%s anomaly(%s, k=%v)", rule.PrometheusConfig.PromQL, anomalyConfig.Algorithm, ruleExpr.GetSensitivity())
+				anomalyLabels = map[string]interface{}{
+					"baseline":    roundFloat(baseline.Expected),
+					"lower_bound": roundFloat(band.Lower),
+					"upper_bound": roundFloat(band.Upper),
+					"deviation":   roundFloat(band.Deviation),
+				}
+			} else {
+				operator, value, err := tools.ProcessRuleExpr(ruleExpr.Expr)
+				if err != nil {
+					logc.Errorf(ctx.Ctx, err.Error())
+					continue
+				}
+
+				firing = process.EvalCondition(models.EvalCondition{
+					Operator:      operator,
+					QueryValue:    v.Value,
+					ExpectedValue: value,
+				})
+				searchQL = fmt.Sprintf("%s %s %v", rule.PrometheusConfig.PromQL, operator, value)
 			}
 
 			fingerprintMetric := provider.Metrics{
@@ -102,6 +170,9 @@ func metrics(ctx *ctx.Context, datasourceId, datasourceType string, rule models.
 				newMetric["fingerprint"] = fingerprint
 				newMetric["severity"] = ruleExpr.Severity
 				newMetric["value"] = v.Value
+				for k, val := range anomalyLabels {
+					newMetric[k] = val
+				}
 				for ek, ev := range externalLabels {
 					newMetric[ek] = ev
 				}
@@ -122,17 +193,13 @@ func metrics(ctx *ctx.Context, datasourceId, datasourceType string, rule models.
 			event.DatasourceId = datasourceId
 			event.Fingerprint = fingerprint
 			event.Severity = ruleExpr.Severity
-			event.SearchQL = fmt.Sprintf("%s %s %v", rule.PrometheusConfig.PromQL, operator, value)
+			event.SearchQL = searchQL
 			event.ForDuration = rule.GetForDuration(ruleExpr.Severity)
 			event.Annotations = tools.ParserVariables(rule.PrometheusConfig.Annotations, tools.ConvertStructToMap(event))
 			event.Status = models.StatePreAlert
 
 			// 告警评估
-			if process.EvalCondition(models.EvalCondition{
-				Operator:      operator,
-				QueryValue:    v.Value,
-				ExpectedValue: value,
-			}) {
+			if firing {
 				if len(highestPriorityEvents) > 0 {
 					// 如果有高优先级告警，则抑制掉低级告警
 					event.LastSendTime = time.Now().Unix()
@@ -154,7 +221,7 @@ func metrics(ctx *ctx.Context, datasourceId, datasourceType string, rule models.
 		}
 	}
 
-	return curFingerprints
+	return curFingerprints, nil
 }
 
 // sortRulesByPriority 按优先级排序规则
@@ -188,7 +255,7 @@ func getPriorityValue(severity string) int {
 }
 
 // Logs 包含 AliSLS、Loki、ElasticSearch 数据源
-func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) []string {
+func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) ([]string, error) {
 	var (
 		// 日志信息
 		log provider.Logs
@@ -206,7 +273,7 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 	cli, err := pools.GetClient(datasourceId)
 	if err != nil {
 		logc.Errorf(ctx.Ctx, err.Error())
-		return []string{}
+		return nil, err
 	}
 
 	switch datasourceType {
@@ -222,14 +289,14 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 		log, count, err = cli.(provider.LokiProvider).Query(queryOptions)
 		if err != nil {
 			logc.Error(ctx.Ctx, err.Error())
-			return []string{}
+			return nil, err
 		}
 
 		externalLabels = cli.(provider.LokiProvider).GetExternalLabels()
 		operator, value, err := tools.ProcessRuleExpr(rule.LogEvalCondition)
 		if err != nil {
 			logc.Errorf(ctx.Ctx, err.Error())
-			return []string{}
+			return nil, err
 		}
 
 		evalOptions = models.EvalCondition{
@@ -251,14 +318,14 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 		log, count, err = cli.(provider.AliCloudSlsDsProvider).Query(queryOptions)
 		if err != nil {
 			logc.Error(ctx.Ctx, err.Error())
-			return []string{}
+			return nil, err
 		}
 
 		externalLabels = cli.(provider.AliCloudSlsDsProvider).GetExternalLabels()
 		operator, value, err := tools.ProcessRuleExpr(rule.LogEvalCondition)
 		if err != nil {
 			logc.Errorf(ctx.Ctx, err.Error())
-			return []string{}
+			return nil, err
 		}
 
 		evalOptions = models.EvalCondition{
@@ -280,14 +347,14 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 		log, count, err = cli.(provider.ElasticSearchDsProvider).Query(queryOptions)
 		if err != nil {
 			logc.Error(ctx.Ctx, err.Error())
-			return []string{}
+			return nil, err
 		}
 
 		externalLabels = cli.(provider.ElasticSearchDsProvider).GetExternalLabels()
 		operator, value, err := tools.ProcessRuleExpr(rule.LogEvalCondition)
 		if err != nil {
 			logc.Errorf(ctx.Ctx, err.Error())
-			return []string{}
+			return nil, err
 		}
 
 		evalOptions = models.EvalCondition{
@@ -308,14 +375,14 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 		log, count, err = cli.(provider.VictoriaLogsProvider).Query(queryOptions)
 		if err != nil {
 			logc.Error(ctx.Ctx, err.Error())
-			return []string{}
+			return nil, err
 		}
 
 		externalLabels = cli.(provider.VictoriaLogsProvider).GetExternalLabels()
 		operator, value, err := tools.ProcessRuleExpr(rule.LogEvalCondition)
 		if err != nil {
 			logc.Errorf(ctx.Ctx, err.Error())
-			return []string{}
+			return nil, err
 		}
 
 		evalOptions = models.EvalCondition{
@@ -339,7 +406,7 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 		log, count, err = cli.(provider.ClickHouseProvider).Query(queryOptions)
 		if err != nil {
 			logc.Error(ctx.Ctx, err.Error())
-			return []string{}
+			return nil, err
 		}
 
 		externalLabels = cli.(provider.ClickHouseProvider).GetExternalLabels()
@@ -347,7 +414,7 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 		operator, value, err := tools.ProcessRuleExpr(rule.LogEvalCondition)
 		if err != nil {
 			logc.Errorf(ctx.Ctx, err.Error())
-			return []string{}
+			return nil, err
 		}
 
 		evalOptions = models.EvalCondition{
@@ -357,8 +424,9 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 		}
 	}
 
+	// 日志数量为 0 表示没有匹配的日志，属于正常状态，不按无数据处理
 	if count <= 0 {
-		return []string{}
+		return nil, nil
 	}
 
 	// 唯一指纹基于 RuleId
@@ -413,26 +481,24 @@ func logs(ctx *ctx.Context, datasourceId, datasourceType string, rule models.Ale
 		process.PushEventToFaultCenter(ctx, event())
 	}
 
-	return curFingerprints
+	return curFingerprints, nil
 }
 
 // clickhouseAdvancedMode ClickHouse 高级告警模式
 // 从查询结果中提取指定字段的值进行告警判断，类似 Metrics 的处理方式
-func clickhouseAdvancedMode(ctx *ctx.Context, datasourceId string, rule models.AlertRule, cli interface{}) []string {
-	var curFingerprints []string
-
+func clickhouseAdvancedMode(ctx *ctx.Context, datasourceId string, rule models.AlertRule, cli interface{}) ([]string, error) {
 	// 获取数据源信息
 	datasourceObj, err := ctx.DB.Datasource().GetInstance(datasourceId)
 	if err != nil {
 		logc.Errorf(ctx.Ctx, "获取数据源失败: %v", err)
-		return curFingerprints
+		return nil, err
 	}
 
 	// 创建高级模式的 ClickHouse 客户端
 	advancedCli, err := provider.NewClickHouseAdvancedClient(ctx.Ctx, datasourceObj)
 	if err != nil {
 		logc.Errorf(ctx.Ctx, "创建 ClickHouse 高级模式客户端失败: %v", err)
-		return curFingerprints
+		return nil, err
 	}
 
 	// 使用高级模式的 Provider 查询数据
@@ -442,24 +508,59 @@ func clickhouseAdvancedMode(ctx *ctx.Context, datasourceId string, rule models.A
 		},
 	}
 
+	return advancedMode(ctx, datasourceId, rule, advancedCli, queryOptions)
+}
+
+// sqlQuery 包含 MySQL、PostgreSQL 数据源，规则复用 ClickHouse 高级模式配置，ValueField 必填
+func sqlQuery(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) ([]string, error) {
+	if rule.ClickHouseConfig.ValueField == "" {
+		logc.Errorf(ctx.Ctx, "SQL 数据源规则未配置 ValueField, ruleId: %s", rule.RuleId)
+		return nil, fmt.Errorf("sql rule valueField is empty")
+	}
+
+	cli, err := ctx.Redis.ProviderPools().GetClient(datasourceId)
+	if err != nil {
+		logc.Errorf(ctx.Ctx, err.Error())
+		return nil, err
+	}
+
+	advancedCli, ok := cli.(provider.LogsAdvancedFactoryProvider)
+	if !ok {
+		logc.Errorf(ctx.Ctx, "Invalid sql client, type: %s", datasourceType)
+		return nil, fmt.Errorf("invalid sql client, type: %s", datasourceType)
+	}
+
+	queryOptions := provider.LogQueryOptions{
+		SQL: provider.SQL{
+			Query: rule.ClickHouseConfig.LogQL,
+		},
+	}
+
+	return advancedMode(ctx, datasourceId, rule, advancedCli, queryOptions)
+}
+
+// advancedMode 从高级模式查询结果中提取 ValueField 字段的值进行告警判断，每条结果按 LabelFields 生成独立事件
+func advancedMode(ctx *ctx.Context, datasourceId string, rule models.AlertRule, advancedCli provider.LogsAdvancedFactoryProvider, queryOptions provider.LogQueryOptions) ([]string, error) {
+	var curFingerprints []string
+
 	log, count, err := advancedCli.QueryAdvanced(queryOptions)
 	if err != nil {
 		logc.Error(ctx.Ctx, err.Error())
-		return curFingerprints
+		return nil, err
 	}
 
 	externalLabels := advancedCli.GetExternalLabels()
 
 	// 如果没有查询结果，直接返回
 	if count == 0 || len(log.Message) == 0 {
-		return curFingerprints
+		return nil, errNoData
 	}
 
 	// 解析告警条件
 	operator, expectedValue, err := tools.ProcessRuleExpr(rule.LogEvalCondition)
 	if err != nil {
 		logc.Errorf(ctx.Ctx, "解析告警条件失败: %v", err)
-		return curFingerprints
+		return nil, err
 	}
 
 	// 遍历每条查询结果（类似 Metrics 的处理方式）
@@ -557,7 +658,7 @@ func clickhouseAdvancedMode(ctx *ctx.Context, datasourceId string, rule models.A
 		event.DatasourceId = datasourceId
 		event.Fingerprint = fingerprint
 		event.SearchQL = rule.ClickHouseConfig.LogQL
-		// ClickHouse 使用自定义的 Annotations 模板，如果没有则使用默认格式
+		// 使用自定义的 Annotations 模板，如果没有则使用默认格式
 		if rule.ClickHouseConfig.Annotations != "" {
 			event.Annotations = tools.ParserVariables(rule.ClickHouseConfig.Annotations, tools.ConvertStructToMap(event))
 		} else {
@@ -585,41 +686,48 @@ func clickhouseAdvancedMode(ctx *ctx.Context, datasourceId string, rule models.A
 		}
 	}
 
-	return curFingerprints
+	return curFingerprints, nil
 }
 
-// Traces 包含 Jaeger 数据源
-func traces(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) []string {
-	var (
-		queryRes       []provider.Traces
-		externalLabels map[string]interface{}
-	)
-
+// Traces 包含 Jaeger、Tempo、Zipkin 数据源
+func traces(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) ([]string, error) {
 	pools := ctx.Redis.ProviderPools()
-	switch datasourceType {
-	case provider.JaegerDsProviderName:
-		curAt := time.Now().UTC()
-		startsAt := tools.ParserDuration(curAt, rule.JaegerConfig.Scope, "m")
+	cli, err := pools.GetClient(datasourceId)
+	if err != nil {
+		logc.Errorf(ctx.Ctx, err.Error())
+		return nil, err
+	}
 
-		cli, err := pools.GetClient(datasourceId)
-		if err != nil {
-			logc.Errorf(ctx.Ctx, err.Error())
-			return []string{}
-		}
+	traceCli, ok := cli.(provider.TracesFactoryProvider)
+	if !ok {
+		logc.Errorf(ctx.Ctx, "Unsupported traces type, type: %s", datasourceType)
+		return nil, fmt.Errorf("unsupported traces type: %s", datasourceType)
+	}
 
-		queryOptions := provider.TraceQueryOptions{
-			Tags:    rule.JaegerConfig.Tags,
-			Service: rule.JaegerConfig.Service,
-			StartAt: startsAt.UnixMicro(),
-			EndAt:   curAt.UnixMicro(),
-		}
-		queryRes, err = cli.(provider.JaegerDsProvider).Query(queryOptions)
-		if err != nil {
-			logc.Error(ctx.Ctx, err.Error())
-			return []string{}
-		}
+	curAt := time.Now().UTC()
+	startsAt := tools.ParserDuration(curAt, rule.JaegerConfig.Scope, "m")
+	queryOptions := provider.TraceQueryOptions{
+		Tags:      rule.JaegerConfig.Tags,
+		Service:   rule.JaegerConfig.Service,
+		Operation: rule.JaegerConfig.Operation,
+		Query:     rule.JaegerConfig.Query,
+		StartAt:   startsAt.UnixMicro(),
+		EndAt:     curAt.UnixMicro(),
+	}
+	externalLabels := traceCli.GetExternalLabels()
+
+	if rule.JaegerConfig.GetConditionType() != models.TraceConditionCount {
+		return traceSpanConditions(ctx, datasourceId, rule, traceCli, queryOptions, externalLabels)
+	}
 
-		externalLabels = cli.(provider.JaegerDsProvider).GetExternalLabels()
+	queryRes, err := traceCli.Query(queryOptions)
+	if err != nil {
+		logc.Error(ctx.Ctx, err.Error())
+		return nil, err
+	}
+	// 没有异常链路属于正常状态，不按无数据处理
+	if len(queryRes) == 0 {
+		return nil, nil
 	}
 
 	var curFingerprints []string
@@ -649,16 +757,217 @@ func traces(ctx *ctx.Context, datasourceId, datasourceType string, rule models.A
 		process.PushEventToFaultCenter(ctx, &event)
 	}
 
-	return curFingerprints
+	return curFingerprints, nil
 }
 
-func cloudWatch(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) []string {
+// traceSpanConditions 按 Span 耗时分位数或错误率评估链路告警，GroupByOperation 时每个接口生成独立事件
+func traceSpanConditions(ctx *ctx.Context, datasourceId string, rule models.AlertRule, cli provider.TracesFactoryProvider, options provider.TraceQueryOptions, externalLabels map[string]interface{}) ([]string, error) {
+	var curFingerprints []string
+
+	operator, expectedValue, err := tools.ProcessRuleExpr(rule.JaegerConfig.Expr)
+	if err != nil {
+		logc.Errorf(ctx.Ctx, "解析告警条件失败: %v", err)
+		return nil, err
+	}
+
+	// 链路后端不提供分位数聚合，按最近的 SampleLimit 条链路采样计算
+	options.Limit = rule.JaegerConfig.GetSampleLimit()
+	spans, err := cli.QuerySpans(options)
+	if err != nil {
+		logc.Error(ctx.Ctx, err.Error())
+		return nil, err
+	}
+	if len(spans) == 0 {
+		return nil, errNoData
+	}
+	sample := traceSample{Traces: countTraces(spans), Limit: options.Limit}
+
+	// 按接口分组，未开启时所有 Span 合并计算
+	groups := make(map[string][]provider.Span)
+	for _, span := range spans {
+		operation := options.Operation
+		if rule.JaegerConfig.GroupByOperation {
+			operation = span.Operation
+		}
+		groups[operation] = append(groups[operation], span)
+	}
+
+	conditionType := rule.JaegerConfig.GetConditionType()
+	for operation, group := range groups {
+		value, traceIds := traceSpanValue(conditionType, group)
+
+		fingerprintLabels := map[string]interface{}{
+			"rule_id":   rule.RuleId,
+			"service":   options.Service,
+			"operation": operation,
+		}
+		fingerprint := provider.Metrics{Metric: fingerprintLabels}.GetFingerprint()
+
+		event := process.BuildEvent(rule, func() map[string]interface{} {
+			metric := map[string]interface{}{
+				"rule_name":   rule.RuleName,
+				"severity":    rule.Severity,
+				"fingerprint": fingerprint,
+				"service":     options.Service,
+				"value":       value,
+				"traceIds":    strings.Join(traceIds, ","),
+				"sample":      sample.String(),
+			}
+			if operation != "" {
+				metric["operation"] = operation
+			}
+			for ek, ev := range externalLabels {
+				metric[ek] = ev
+			}
+			for ek, ev := range rule.ExternalLabels {
+				metric[ek] = ev
+			}
+			return metric
+		})
+		event.DatasourceId = datasourceId
+		event.Fingerprint = fingerprint
+		event.SearchQL = fmt.Sprintf("%s %s %v", conditionType, operator, expectedValue)
+		event.Annotations = traceSpanAnnotations(cli, options.Service, operation, conditionType, operator, expectedValue, value, traceIds, sample)
+
+		if process.EvalCondition(models.EvalCondition{
+			Operator:      operator,
+			QueryValue:    value,
+			ExpectedValue: expectedValue,
+		}) {
+			event.Status = models.StatePreAlert
+			process.PushEventToFaultCenter(ctx, &event)
+			curFingerprints = append(curFingerprints, fingerprint)
+		} else {
+			// 更新恢复时最新值
+			cache, err := ctx.Redis.Alert().GetEventFromCache(event.TenantId, event.FaultCenterId, event.Fingerprint)
+			if err == nil {
+				if !cache.IsRecovered || cache.Status != models.StateRecovered {
+					process.PushEventToFaultCenter(ctx, &event)
+				}
+			}
+		}
+	}
+
+	return curFingerprints, nil
+}
+
+// traceSpanValue 计算 Span 耗时分位数（毫秒）或错误率（%），并返回最多 5 个相关的 TraceId
+func traceSpanValue(conditionType string, spans []provider.Span) (float64, []string) {
+	const maxTraceIds = 5
+	var (
+		value    float64
+		traceIds []string
+		seen     = make(map[string]struct{})
+	)
+	addTraceId := func(traceId string) {
+		if _, ok := seen[traceId]; ok || len(traceIds) >= maxTraceIds {
+			return
+		}
+		seen[traceId] = struct{}{}
+		traceIds = append(traceIds, traceId)
+	}
+
+	if len(spans) == 0 {
+		return 0, nil
+	}
+
+	switch conditionType {
+	case models.TraceConditionP95Latency, models.TraceConditionP99Latency:
+		sorted := make([]provider.Span, len(spans))
+		copy(sorted, spans)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].Duration < sorted[j].Duration
+		})
+
+		quantile := 0.95
+		if conditionType == models.TraceConditionP99Latency {
+			quantile = 0.99
+		}
+		idx := int(math.Ceil(quantile*float64(len(sorted)))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		value = float64(sorted[idx].Duration) / 1e3
+
+		// 耗时最长的链路
+		for i := len(sorted) - 1; i >= 0; i-- {
+			addTraceId(sorted[i].TraceId)
+		}
+	case models.TraceConditionErrorRatio:
+		var errCount int
+		for _, span := range spans {
+			if span.Error {
+				errCount++
+				addTraceId(span.TraceId)
+			}
+		}
+		value = float64(errCount) / float64(len(spans)) * 100
+	}
+
+	return math.Round(value*100) / 100, traceIds
+}
+
+// traceSample 计算分位数、错误率使用的链路样本
+type traceSample struct {
+	Traces int
+	Limit  int64
+}
+
+// Truncated 样本达到采样上限，时间范围内可能存在更多链路
+func (s traceSample) Truncated() bool {
+	return int64(s.Traces) >= s.Limit
+}
+
+func (s traceSample) String() string {
+	if s.Truncated() {
+		return fmt.Sprintf("%d/%d (truncated)", s.Traces, s.Limit)
+	}
+	return fmt.Sprintf("%d/%d", s.Traces, s.Limit)
+}
+
+func countTraces(spans []provider.Span) int {
+	traces := make(map[string]struct{})
+	for _, span := range spans {
+		traces[span.TraceId] = struct{}{}
+	}
+	return len(traces)
+}
+
+func traceSpanAnnotations(cli provider.TracesFactoryProvider, service, operation, conditionType, operator string, expectedValue, value float64, traceIds []string, sample traceSample) string {
+	name, unit := "错误率", "%"
+	switch conditionType {
+	case models.TraceConditionP95Latency:
+		name, unit = "P95 耗时", "ms"
+	case models.TraceConditionP99Latency:
+		name, unit = "P99 耗时", "ms"
+	}
+
+	if operation == "" {
+		operation = "全部接口"
+	}
+
+	content := fmt.Sprintf("服务: %s\n接口: %s\n%s: %v%s, 告警条件: %s %v%s", service, operation, name, value, unit, operator, expectedValue, unit)
+	content += fmt.Sprintf("\n采样: %d 条链路, 采样上限 %d", sample.Traces, sample.Limit)
+	if sample.Truncated() {
+		content += ", 已达上限, 结果为最近链路的近似值"
+	}
+	if len(traceIds) > 0 {
+		content += "\n相关链路:"
+		for _, traceId := range traceIds {
+			content += "\n" + cli.GetTraceURL(traceId)
+		}
+	}
+
+	return content
+}
+
+func cloudWatch(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) ([]string, error) {
 	var externalLabels map[string]interface{}
 	pools := ctx.Redis.ProviderPools()
 	cfg, err := pools.GetClient(datasourceId)
 	if err != nil {
 		logc.Errorf(ctx.Ctx, err.Error())
-		return []string{}
+		return nil, err
 	}
 
 	externalLabels = cfg.(provider.AwsConfig).GetExternalLabels()
@@ -667,7 +976,10 @@ func cloudWatch(ctx *ctx.Context, datasourceId, datasourceType string, rule mode
 	curAt := time.Now().UTC()
 	startsAt := tools.ParserDuration(curAt, rule.CloudWatchConfig.Period, "m")
 
-	var curFingerprints []string
+	var (
+		curFingerprints []string
+		hasData         bool
+	)
 	for _, endpoint := range rule.CloudWatchConfig.Endpoints {
 		query := types.CloudWatchQuery{
 			Endpoint:   endpoint,
@@ -681,8 +993,9 @@ func cloudWatch(ctx *ctx.Context, datasourceId, datasourceType string, rule mode
 		}
 		_, values := cloudwatch.MetricDataQuery(cli, query)
 		if len(values) == 0 {
-			return []string{}
+			continue
 		}
+		hasData = true
 
 		event := process.BuildEvent(rule, func() map[string]interface{} {
 			metric := query.GetMetrics()
@@ -712,34 +1025,38 @@ func cloudWatch(ctx *ctx.Context, datasourceId, datasourceType string, rule mode
 		}
 	}
 
-	return curFingerprints
+	if !hasData {
+		return nil, errNoData
+	}
+
+	return curFingerprints, nil
 }
 
-func kubernetesEvent(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) []string {
+func kubernetesEvent(ctx *ctx.Context, datasourceId, datasourceType string, rule models.AlertRule) ([]string, error) {
 	var externalLabels map[string]interface{}
 	datasourceObj, err := ctx.DB.Datasource().GetInstance(datasourceId)
 	if err != nil {
 		logc.Error(ctx.Ctx, err.Error())
-		return []string{}
+		return nil, err
 	}
 
 	pools := ctx.Redis.ProviderPools()
 	cli, err := pools.GetClient(datasourceId)
 	if err != nil {
 		logc.Errorf(ctx.Ctx, err.Error())
-		return []string{}
+		return nil, err
 	}
 
 	k8sEvent, err := cli.(provider.KubernetesClient).GetWarningEvent(rule.KubernetesConfig.Reason, rule.KubernetesConfig.Scope)
 	if err != nil {
 		logc.Error(ctx.Ctx, err.Error())
-		return []string{}
+		return nil, err
 	}
 
 	externalLabels = cli.(provider.KubernetesClient).GetExternalLabels()
 
 	if len(k8sEvent.Items) == 0 {
-		return []string{}
+		return nil, nil
 	}
 
 	// 分组：key = resourceName + eventReason
@@ -803,5 +1120,5 @@ func kubernetesEvent(ctx *ctx.Context, datasourceId, datasourceType string, rule
 		process.PushEventToFaultCenter(ctx, &event)
 	}
 
-	return curFingerprints
+	return curFingerprints, nil
 }
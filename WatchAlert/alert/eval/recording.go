@@ -0,0 +1,102 @@
+package eval
+
+import (
+	"fmt"
+	"time"
+	appMetrics "watchAlert/internal/metrics"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/provider"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// 记录规则失败阶段，用于失败指标
+const (
+	recordingStageQuery = "query"
+	recordingStageWrite = "write"
+)
+
+// record 执行记录规则，在每个数据源上计算表达式并写入目标数据源，不产生告警事件
+func (t *AlertRule) record(rule models.AlertRule) {
+	start := time.Now()
+
+	var samples int
+	for _, dsId := range rule.DatasourceIdList {
+		n, err := t.recordDatasource(dsId, rule, start)
+		if err != nil {
+			logc.Errorf(t.ctx.Ctx, "Recording rule %s(%s) on datasource %s failed: %s", rule.RuleName, rule.RuleId, dsId, err.Error())
+			continue
+		}
+		samples += n
+	}
+
+	appMetrics.RecordRecordingRuleEval(rule.TenantId, rule.RuleId, time.Since(start).Seconds(), samples)
+}
+
+// recordDatasource 在单个数据源上执行记录规则，返回写入的样本数
+func (t *AlertRule) recordDatasource(dsId string, rule models.AlertRule, now time.Time) (int, error) {
+	config := rule.RecordingConfig
+
+	cli, err := t.ctx.Redis.ProviderPools().GetClient(dsId)
+	if err != nil {
+		appMetrics.IncRecordingRuleEvalFailures(rule.TenantId, rule.RuleId, recordingStageQuery)
+		return 0, err
+	}
+
+	metricsCli, ok := cli.(provider.MetricsFactoryProvider)
+	if !ok {
+		appMetrics.IncRecordingRuleEvalFailures(rule.TenantId, rule.RuleId, recordingStageQuery)
+		return 0, fmt.Errorf("invalid metrics client, datasource: %s", dsId)
+	}
+
+	res, err := metricsCli.Query(config.Expr)
+	if err != nil {
+		appMetrics.IncRecordingRuleEvalFailures(rule.TenantId, rule.RuleId, recordingStageQuery)
+		return 0, err
+	}
+
+	writeDsId := config.WriteDatasourceId
+	if writeDsId == "" {
+		writeDsId = dsId
+	}
+	writeDs, err := t.ctx.DB.Datasource().GetInstance(writeDsId)
+	if err != nil {
+		appMetrics.IncRecordingRuleEvalFailures(rule.TenantId, rule.RuleId, recordingStageWrite)
+		return 0, err
+	}
+	// 仅允许写入同一租户的数据源
+	if writeDs.TenantId != rule.TenantId {
+		appMetrics.IncRecordingRuleEvalFailures(rule.TenantId, rule.RuleId, recordingStageWrite)
+		return 0, fmt.Errorf("write datasource %s does not belong to tenant %s", writeDsId, rule.TenantId)
+	}
+	writer, err := provider.NewRemoteWriteClient(writeDs)
+	if err != nil {
+		appMetrics.IncRecordingRuleEvalFailures(rule.TenantId, rule.RuleId, recordingStageWrite)
+		return 0, err
+	}
+
+	series := make([]provider.RemoteWriteSeries, 0, len(res))
+	for _, v := range res {
+		labels := make(map[string]string, len(v.Metric)+len(config.Labels)+1)
+		for k, val := range v.Metric {
+			labels[k] = fmt.Sprintf("%v", val)
+		}
+		for k, val := range config.Labels {
+			labels[k] = val
+		}
+		labels["__name__"] = config.Record
+
+		series = append(series, provider.RemoteWriteSeries{
+			Labels:    labels,
+			Value:     v.Value,
+			Timestamp: now.UnixMilli(),
+		})
+	}
+
+	if err := writer.Write(series); err != nil {
+		appMetrics.IncRecordingRuleEvalFailures(rule.TenantId, rule.RuleId, recordingStageWrite)
+		return 0, err
+	}
+
+	return len(series), nil
+}
@@ -0,0 +1,47 @@
+package mute
+
+import (
+	"fmt"
+	"watchAlert/internal/models"
+)
+
+// IsInhibited 判断事件是否被故障中心内其他告警中的事件抑制
+func IsInhibited(event *models.AlertCurEvent, events map[string]*models.AlertCurEvent, rules []models.InhibitRule) bool {
+	for _, rule := range rules {
+		if len(rule.SourceMatchers) == 0 || len(rule.TargetMatchers) == 0 {
+			continue
+		}
+
+		if !evalCondition(event.Labels, rule.TargetMatchers) {
+			continue
+		}
+
+		for _, source := range events {
+			// 只有告警中的事件才能作为抑制源，且不能抑制自身
+			if source.Fingerprint == event.Fingerprint || source.Status != models.StateAlerting {
+				continue
+			}
+
+			if !evalCondition(source.Labels, rule.SourceMatchers) {
+				continue
+			}
+
+			if equalLabels(event.Labels, source.Labels, rule.Equal) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// equalLabels 判断两个事件的指定标签值是否一致，标签均不存在时也视为一致
+func equalLabels(target, source map[string]interface{}, keys []string) bool {
+	for _, key := range keys {
+		if fmt.Sprintf("%v", target[key]) != fmt.Sprintf("%v", source[key]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -88,6 +88,11 @@ func IsSilence(mute MuteParams) bool {
 	return false
 }
 
+// MatchSilenceLabels 判断标签是否匹配静默规则的标签条件
+func MatchSilenceLabels(labels map[string]interface{}, muteLabels []models.SilenceLabel) bool {
+	return evalCondition(labels, muteLabels)
+}
+
 func evalCondition(metrics map[string]interface{}, muteLabels []models.SilenceLabel) bool {
 	for _, muteLabel := range muteLabels {
 		value, exists := metrics[muteLabel.Key]
@@ -0,0 +1,304 @@
+package probing
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"time"
+	"watchAlert/alert/mute"
+	"watchAlert/alert/process"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/provider"
+)
+
+// 可用性统计窗口
+const (
+	AvailabilityWindowDay   = "day"
+	AvailabilityWindowWeek  = "week"
+	AvailabilityWindowMonth = "month"
+)
+
+// 各拨测类型的耗时字段，按顺序取第一个存在的字段
+var probingLatencyFields = []string{"Latency", "ResponseTime", "AvgRtt", "ResolveTime", "ConnectTime"}
+
+// AvailabilityRange 获取截止到 now 的统计窗口时间范围
+func AvailabilityRange(window string, now time.Time) (int64, int64, error) {
+	switch window {
+	case AvailabilityWindowDay:
+		return now.AddDate(0, 0, -1).Unix(), now.Unix(), nil
+	case AvailabilityWindowWeek:
+		return now.AddDate(0, 0, -7).Unix(), now.Unix(), nil
+	case AvailabilityWindowMonth:
+		return now.AddDate(0, -1, 0).Unix(), now.Unix(), nil
+	default:
+		return 0, 0, fmt.Errorf("不支持的统计窗口 %s", window)
+	}
+}
+
+// CalcAvailability 统计拨测规则在 [start, end) 内的可用性，location 为空时统计全部位置
+func CalcAvailability(ctx *ctx.Context, rule models.ProbingRule, location string, start, end int64) (models.ProbingAvailability, error) {
+	availability := models.ProbingAvailability{
+		RuleId:   rule.RuleId,
+		RuleName: rule.RuleName,
+		RuleType: rule.RuleType,
+		Endpoint: rule.ProbingEndpointConfig.Endpoint,
+		Location: location,
+		StartAt:  start,
+		EndAt:    end,
+	}
+
+	records, err := ctx.DB.Probing().ListRecords(rule.RuleId, start, end)
+	if err != nil {
+		return availability, err
+	}
+	rollups, err := ctx.DB.Probing().ListRollups(rule.RuleId, start, end)
+	if err != nil {
+		return availability, err
+	}
+	silences, err := listProbingSilences(ctx, rule.TenantId, start, end)
+	if err != nil {
+		return availability, err
+	}
+
+	return aggregateAvailability(availability, rule, location, records, rollups, silences), nil
+}
+
+// aggregateAvailability 合并原始记录及小时聚合数据计算可用性，静默期间的拨测结果不参与统计
+func aggregateAvailability(availability models.ProbingAvailability, rule models.ProbingRule, location string, records []models.ProbingHistory, rollups []models.ProbingHistoryRollup, silences []models.AlertSilences) models.ProbingAvailability {
+	var (
+		samples  = make(map[string][]models.ProbingHistory)
+		silenced int64
+	)
+	for _, record := range records {
+		if location != "" && record.Location != location {
+			continue
+		}
+		if isProbingSilenced(rule, record.Location, record.Value, record.Timestamp, record.Timestamp, silences) {
+			silenced++
+			continue
+		}
+		samples[record.Location] = append(samples[record.Location], record)
+	}
+	for loc, list := range samples {
+		rollups = append(rollups, RollupRecords(rule, loc, list)...)
+	}
+
+	total := newProbingRollup()
+	for _, rollup := range rollups {
+		if location != "" && rollup.Location != location {
+			continue
+		}
+		// 聚合数据已无法区分单次拨测，仅排除整小时处于静默期间的数据
+		if isProbingSilenced(rule, rollup.Location, nil, rollup.Timestamp, rollup.Timestamp+3600, silences) {
+			silenced += rollup.Total
+			continue
+		}
+		mergeRollup(&total, rollup)
+	}
+
+	availability.Total = total.Total
+	availability.Failed = total.Failed
+	availability.Silenced = silenced
+	availability.Outages = total.Outages
+	availability.DowntimeSeconds = total.DowntimeSeconds
+	// 没有拨测记录时标记为无数据，而不是 100% 可用
+	availability.NoData = total.Total == 0
+	if total.Total > 0 {
+		availability.Uptime = float64(total.Total-total.Failed) / float64(total.Total) * 100
+	}
+	if total.Recoveries > 0 {
+		availability.MTTR = float64(total.RecoverySeconds) / float64(total.Recoveries)
+	}
+	availability.LatencyP50 = latencyQuantile(total.LatencyBuckets, 0.5)
+	availability.LatencyP90 = latencyQuantile(total.LatencyBuckets, 0.9)
+	availability.LatencyP99 = latencyQuantile(total.LatencyBuckets, 0.99)
+
+	return availability
+}
+
+// RollupRecords 将同一位置按时间排序的拨测记录聚合为小时数据；连续失败视为一次故障，持续到下一次成功的拨测，
+// 故障计入开始的小时，恢复计入恢复的小时
+func RollupRecords(rule models.ProbingRule, location string, records []models.ProbingHistory) []models.ProbingHistoryRollup {
+	var (
+		hours     = make(map[int64]*models.ProbingHistoryRollup)
+		order     []int64
+		down      bool
+		downSince int64
+		downHour  int64
+	)
+	getHour := func(ts int64) *models.ProbingHistoryRollup {
+		hour := ts - ts%3600
+		if r, ok := hours[hour]; ok {
+			return r
+		}
+		r := newProbingRollup()
+		r.Timestamp = hour
+		r.RuleId = rule.RuleId
+		r.Location = location
+		hours[hour] = &r
+		order = append(order, hour)
+		return &r
+	}
+
+	for _, record := range records {
+		r := getHour(record.Timestamp)
+		r.Total++
+		if latency, ok := probingLatency(record.Value); ok {
+			r.LatencyBuckets[latencyBucket(latency)]++
+		}
+
+		if isProbingFailed(rule, record.Value) {
+			r.Failed++
+			if !down {
+				down, downSince, downHour = true, record.Timestamp, r.Timestamp
+				r.Outages++
+			}
+			continue
+		}
+
+		if down {
+			duration := record.Timestamp - downSince
+			hours[downHour].DowntimeSeconds += duration
+			r.Recoveries++
+			r.RecoverySeconds += duration
+			down = false
+		}
+	}
+
+	// 尚未恢复的故障，持续时长计算到最后一次拨测
+	if down {
+		hours[downHour].DowntimeSeconds += records[len(records)-1].Timestamp - downSince
+	}
+
+	rollups := make([]models.ProbingHistoryRollup, 0, len(order))
+	for _, hour := range order {
+		rollups = append(rollups, *hours[hour])
+	}
+	return rollups
+}
+
+// isProbingFailed 按规则当前的评估条件判断拨测结果是否异常，无法评估的结果（如执行失败）视为异常
+func isProbingFailed(rule models.ProbingRule, value map[string]any) bool {
+	option, err := BuildEvalCondition(rule, provider.EndpointValue(value))
+	if err != nil {
+		return true
+	}
+	return process.EvalCondition(option)
+}
+
+func probingLatency(value map[string]any) (float64, bool) {
+	for _, field := range probingLatencyFields {
+		if v, ok := value[field].(float64); ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+func newProbingRollup() models.ProbingHistoryRollup {
+	return models.ProbingHistoryRollup{
+		LatencyBuckets: make([]int64, len(models.ProbingLatencyBuckets)+1),
+	}
+}
+
+func mergeRollup(dst *models.ProbingHistoryRollup, src models.ProbingHistoryRollup) {
+	dst.Total += src.Total
+	dst.Failed += src.Failed
+	dst.Outages += src.Outages
+	dst.DowntimeSeconds += src.DowntimeSeconds
+	dst.Recoveries += src.Recoveries
+	dst.RecoverySeconds += src.RecoverySeconds
+	for i, count := range src.LatencyBuckets {
+		if i < len(dst.LatencyBuckets) {
+			dst.LatencyBuckets[i] += count
+		}
+	}
+}
+
+// latencyBucket 延迟所在的分布桶，超过最大上限时为溢出桶
+func latencyBucket(latency float64) int {
+	return sort.SearchFloat64s(models.ProbingLatencyBuckets, latency)
+}
+
+// latencyQuantile 根据延迟分布估算分位数，在桶内线性插值，落在溢出桶时返回最大上限
+func latencyQuantile(buckets []int64, q float64) float64 {
+	var total int64
+	for _, count := range buckets {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+
+	var (
+		rank       = q * float64(total)
+		cumulative int64
+	)
+	for i, count := range buckets {
+		if count == 0 || float64(cumulative+count) < rank {
+			cumulative += count
+			continue
+		}
+		if i >= len(models.ProbingLatencyBuckets) {
+			return models.ProbingLatencyBuckets[len(models.ProbingLatencyBuckets)-1]
+		}
+
+		var lower float64
+		if i > 0 {
+			lower = models.ProbingLatencyBuckets[i-1]
+		}
+		upper := models.ProbingLatencyBuckets[i]
+		return lower + (upper-lower)*(rank-float64(cumulative))/float64(count)
+	}
+
+	return models.ProbingLatencyBuckets[len(models.ProbingLatencyBuckets)-1]
+}
+
+// listProbingSilences 分页获取租户下与 [start, end) 有交集的静默规则，已失效的静默作为历史维护窗口同样生效
+func listProbingSilences(ctx *ctx.Context, tenantId string, start, end int64) ([]models.AlertSilences, error) {
+	const pageSize = 1000
+
+	var silences []models.AlertSilences
+	for page := int64(1); ; page++ {
+		list, count, err := ctx.DB.Silence().List(tenantId, "", "", models.Page{Index: page, Size: pageSize})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, silence := range list {
+			// 没有标签条件的静默会匹配全部拨测，不作为维护窗口
+			if len(silence.Labels) == 0 || silence.EndsAt <= start || silence.StartsAt >= end {
+				continue
+			}
+			silences = append(silences, silence)
+		}
+
+		if len(list) < pageSize || page*pageSize >= count {
+			break
+		}
+	}
+	return silences, nil
+}
+
+// isProbingSilenced 判断 [from, to] 是否完全处于匹配的静默期间，value 为空时仅按规则及位置标签匹配
+func isProbingSilenced(rule models.ProbingRule, location string, value map[string]any, from, to int64, silences []models.AlertSilences) bool {
+	if len(silences) == 0 {
+		return false
+	}
+
+	labels := map[string]interface{}{
+		"rule_id":   rule.RuleId,
+		"rule_name": rule.RuleName,
+		"address":   rule.ProbingEndpointConfig.Endpoint,
+		"location":  location,
+	}
+	maps.Copy(labels, value)
+
+	for _, silence := range silences {
+		if from >= silence.StartsAt && to <= silence.EndsAt && mute.MatchSilenceLabels(labels, silence.Labels) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+package probing
+
+import (
+	"math"
+	"testing"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/provider"
+)
+
+var testTCPRule = models.ProbingRule{RuleId: "r1", RuleName: "tcp", RuleType: provider.TCPEndpointProvider}
+
+// probingRecord TCP 拨测记录，latency 为 0 时不记录耗时
+func probingRecord(ts int64, ok bool, latency float64) models.ProbingHistory {
+	value := map[string]any{"IsSuccessful": float64(0)}
+	if ok {
+		value["IsSuccessful"] = float64(1)
+	}
+	if latency > 0 {
+		value["Latency"] = latency
+	}
+	return models.ProbingHistory{Timestamp: ts, RuleId: "r1", Value: value}
+}
+
+func TestRollupRecords(t *testing.T) {
+	type hour struct {
+		Timestamp, Total, Failed, Outages, DowntimeSeconds, Recoveries, RecoverySeconds int64
+	}
+
+	tests := []struct {
+		name    string
+		records []models.ProbingHistory
+		want    []hour
+	}{
+		{name: "empty", records: nil},
+		{
+			name:    "all successful",
+			records: []models.ProbingHistory{probingRecord(0, true, 0), probingRecord(60, true, 0), probingRecord(120, true, 0)},
+			want:    []hour{{Timestamp: 0, Total: 3}},
+		},
+		{
+			name:    "outage counted in start hour and recovery in recovery hour",
+			records: []models.ProbingHistory{probingRecord(3400, true, 0), probingRecord(3500, false, 0), probingRecord(3590, false, 0), probingRecord(3700, true, 0)},
+			want: []hour{
+				{Timestamp: 0, Total: 3, Failed: 2, Outages: 1, DowntimeSeconds: 200},
+				{Timestamp: 3600, Total: 1, Recoveries: 1, RecoverySeconds: 200},
+			},
+		},
+		{
+			name:    "unrecovered outage lasts until last record",
+			records: []models.ProbingHistory{probingRecord(100, false, 0), probingRecord(200, false, 0), probingRecord(400, false, 0)},
+			want:    []hour{{Timestamp: 0, Total: 3, Failed: 3, Outages: 1, DowntimeSeconds: 300}},
+		},
+		{
+			name:    "separate outages",
+			records: []models.ProbingHistory{probingRecord(0, false, 0), probingRecord(60, true, 0), probingRecord(120, false, 0), probingRecord(180, true, 0)},
+			want:    []hour{{Timestamp: 0, Total: 4, Failed: 2, Outages: 2, DowntimeSeconds: 120, Recoveries: 2, RecoverySeconds: 120}},
+		},
+		{
+			name:    "execution error without result is failed",
+			records: []models.ProbingHistory{{Timestamp: 0, RuleId: "r1", Value: map[string]any{"ErrorMessage": "dial failed"}}},
+			want:    []hour{{Timestamp: 0, Total: 1, Failed: 1, Outages: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rollups := RollupRecords(testTCPRule, "", tt.records)
+			if len(rollups) != len(tt.want) {
+				t.Fatalf("got %d rollups, want %d: %+v", len(rollups), len(tt.want), rollups)
+			}
+			for i, r := range rollups {
+				got := hour{r.Timestamp, r.Total, r.Failed, r.Outages, r.DowntimeSeconds, r.Recoveries, r.RecoverySeconds}
+				if got != tt.want[i] {
+					t.Fatalf("rollup %d = %+v, want %+v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRollupRecordsLatencyBuckets(t *testing.T) {
+	rollups := RollupRecords(testTCPRule, "", []models.ProbingHistory{
+		probingRecord(0, true, 3), probingRecord(10, true, 5), probingRecord(20, true, 60000),
+	})
+	if len(rollups) != 1 {
+		t.Fatalf("got %d rollups, want 1", len(rollups))
+	}
+
+	buckets := rollups[0].LatencyBuckets
+	// 3ms、5ms 落在 (2, 5] 桶，60000ms 落在溢出桶
+	if buckets[2] != 2 || buckets[len(buckets)-1] != 1 {
+		t.Fatalf("unexpected latency buckets: %v", buckets)
+	}
+}
+
+func TestLatencyQuantile(t *testing.T) {
+	buckets := func(counts map[int]int64) []int64 {
+		b := make([]int64, len(models.ProbingLatencyBuckets)+1)
+		for i, count := range counts {
+			b[i] = count
+		}
+		return b
+	}
+
+	tests := []struct {
+		name    string
+		buckets []int64
+		q       float64
+		want    float64
+	}{
+		{name: "empty", buckets: buckets(nil), q: 0.5, want: 0},
+		{name: "interpolate within first bucket", buckets: buckets(map[int]int64{0: 10}), q: 0.5, want: 0.5},
+		{name: "interpolate across buckets", buckets: buckets(map[int]int64{2: 5, 3: 5}), q: 0.9, want: 9},
+		{name: "upper bound of bucket", buckets: buckets(map[int]int64{2: 5, 3: 5}), q: 0.5, want: 5},
+		{name: "overflow bucket", buckets: buckets(map[int]int64{len(models.ProbingLatencyBuckets): 1}), q: 0.99, want: 30000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := latencyQuantile(tt.buckets, tt.q); math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("latencyQuantile(%v) = %v, want %v", tt.q, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateAvailability(t *testing.T) {
+	silence := models.AlertSilences{
+		Labels:   []models.SilenceLabel{{Key: "rule_id", Operator: "==", Value: "r1"}},
+		StartsAt: 0,
+		EndsAt:   150,
+	}
+
+	tests := []struct {
+		name         string
+		location     string
+		records      []models.ProbingHistory
+		rollups      []models.ProbingHistoryRollup
+		silences     []models.AlertSilences
+		wantNoData   bool
+		wantTotal    int64
+		wantFailed   int64
+		wantSilenced int64
+		wantUptime   float64
+	}{
+		{name: "no samples reports no data", wantNoData: true},
+		{
+			name:       "records only",
+			records:    []models.ProbingHistory{probingRecord(0, true, 0), probingRecord(60, false, 0), probingRecord(120, true, 0), probingRecord(180, true, 0)},
+			wantTotal:  4,
+			wantFailed: 1,
+			wantUptime: 75,
+		},
+		{
+			name:       "records merged with rollups",
+			records:    []models.ProbingHistory{probingRecord(7200, true, 0), probingRecord(7260, true, 0)},
+			rollups:    []models.ProbingHistoryRollup{{Timestamp: 0, RuleId: "r1", Total: 8, Failed: 2}},
+			wantTotal:  10,
+			wantFailed: 2,
+			wantUptime: 80,
+		},
+		{
+			name:         "silenced records excluded",
+			records:      []models.ProbingHistory{probingRecord(100, false, 0), probingRecord(200, true, 0)},
+			silences:     []models.AlertSilences{silence},
+			wantTotal:    1,
+			wantSilenced: 1,
+			wantUptime:   100,
+		},
+		{
+			name:         "all samples silenced reports no data",
+			records:      []models.ProbingHistory{probingRecord(100, false, 0)},
+			silences:     []models.AlertSilences{silence},
+			wantNoData:   true,
+			wantSilenced: 1,
+		},
+		{
+			name:       "filter by location",
+			location:   "bj",
+			records:    []models.ProbingHistory{{Timestamp: 0, RuleId: "r1", Location: "sh", Value: map[string]any{"IsSuccessful": float64(0)}}},
+			wantNoData: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := aggregateAvailability(models.ProbingAvailability{}, testTCPRule, tt.location, tt.records, tt.rollups, tt.silences)
+			if got.NoData != tt.wantNoData || got.Total != tt.wantTotal || got.Failed != tt.wantFailed || got.Silenced != tt.wantSilenced {
+				t.Fatalf("unexpected availability: %+v", got)
+			}
+			if math.Abs(got.Uptime-tt.wantUptime) > 1e-9 {
+				t.Fatalf("uptime = %v, want %v", got.Uptime, tt.wantUptime)
+			}
+		})
+	}
+}
+
+func TestRollupBoundary(t *testing.T) {
+	const cutoff = 7200
+	tests := []struct {
+		name    string
+		records []models.ProbingHistory
+		want    int64
+	}{
+		{name: "last record successful", records: []models.ProbingHistory{probingRecord(3700, false, 0), probingRecord(7000, true, 0)}, want: cutoff},
+		{name: "outage open at cutoff", records: []models.ProbingHistory{probingRecord(3000, true, 0), probingRecord(4000, false, 0), probingRecord(7000, false, 0)}, want: 3600},
+		{name: "outage older than earliest", records: []models.ProbingHistory{probingRecord(100, false, 0), probingRecord(7000, false, 0)}, want: cutoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rollupBoundary(testTCPRule, tt.records, cutoff, 1000); got != tt.want {
+				t.Fatalf("rollupBoundary = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRollupAcrossBoundaryCountsOutageOnce(t *testing.T) {
+	records := []models.ProbingHistory{
+		probingRecord(3000, true, 0), probingRecord(4000, false, 0), probingRecord(7000, false, 0),
+		probingRecord(7300, false, 0), probingRecord(7400, true, 0),
+	}
+	before := rollupBoundary(testTCPRule, records[:3], 7200, 0)
+
+	var rolled, raw []models.ProbingHistory
+	for _, record := range records {
+		if record.Timestamp < before {
+			rolled = append(rolled, record)
+		} else {
+			raw = append(raw, record)
+		}
+	}
+	got := aggregateAvailability(models.ProbingAvailability{}, testTCPRule, "", raw, RollupRecords(testTCPRule, "", rolled), nil)
+	if got.Total != 5 || got.Outages != 1 || got.DowntimeSeconds != 3400 || got.MTTR != 3400 {
+		t.Fatalf("unexpected availability: %+v", got)
+	}
+}
@@ -128,6 +128,7 @@ func (m *ConsumeProbing) sendAlert(alert models.ProbingEvent) {
 		Email:       noticeData.Email,
 		Content:     m.getContent(alert, noticeData),
 		Sign:        noticeData.DefaultSign,
+		ChatId:      noticeData.ChatId,
 	})
 	if err != nil {
 		logc.Errorf(m.ctx.Ctx, err.Error())
@@ -0,0 +1,127 @@
+package probing
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"watchAlert/alert/process"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/global"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/provider"
+	"watchAlert/pkg/tools"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// SaveLocationResult 保存单个位置的拨测结果并记录历史，probeErr 不为空时视为该位置拨测失败
+func SaveLocationResult(ctx *ctx.Context, rule models.ProbingRule, location, agentId string, eValue provider.EndpointValue, probeErr error, timestamp int64) {
+	result := models.ProbingLocationResult{
+		Location:  location,
+		AgentId:   agentId,
+		Timestamp: timestamp,
+	}
+
+	if probeErr != nil {
+		eValue = failedProbingValue(rule, probeErr)
+		result.Failed = true
+		result.ErrorMessage = probeErr.Error()
+	}
+
+	if err := RecordHistory(ctx, rule.RuleId, location, eValue, timestamp); err != nil {
+		logc.Errorf(ctx.Ctx, err.Error())
+	}
+
+	if probeErr == nil {
+		option, err := BuildEvalCondition(rule, eValue)
+		if err != nil {
+			result.Failed = true
+			result.ErrorMessage = err.Error()
+		} else {
+			result.Failed = process.EvalCondition(option)
+			if msg, ok := eValue["ErrorMessage"].(string); ok {
+				result.ErrorMessage = msg
+			}
+		}
+	}
+	result.Value = eValue
+
+	ctx.Redis.ProbingAgent().SetLocationResult(rule.TenantId, rule.RuleId, result)
+}
+
+// distributedWorker 多位置拨测，服务端执行 local 位置，汇总各位置未过期的结果，失败位置数达到 Quorum 时告警
+func (t *ProductProbing) distributedWorker(rule models.ProbingRule) {
+	now := time.Now().Unix()
+	if slices.Contains(rule.Locations, models.ProbingLocalLocation) {
+		eValue, err := t.runProbing(rule)
+		SaveLocationResult(t.ctx, rule, models.ProbingLocalLocation, "", eValue, err, now)
+	}
+
+	var (
+		ttl     = global.Config.ProbingAgent.GetResultTTL()
+		results = t.ctx.Redis.ProbingAgent().ListLocationResults(rule.TenantId, rule.RuleId)
+		failed  []string
+		details []string
+		total   int
+	)
+	for _, location := range rule.Locations {
+		result, ok := results[location]
+		if !ok || now-result.Timestamp > ttl {
+			continue
+		}
+		total++
+
+		detail := "ok"
+		if result.Failed {
+			failed = append(failed, location)
+			detail = "fail"
+			if result.ErrorMessage != "" {
+				detail = "fail: " + result.ErrorMessage
+			}
+		}
+		if latency, ok := probingLatency(result.Value); ok {
+			detail = fmt.Sprintf("%s, %vms", detail, latency)
+		}
+		details = append(details, fmt.Sprintf("%s(%s)", location, detail))
+	}
+
+	// 所有位置均无有效结果时不评估，避免节点离线导致误恢复
+	if total == 0 {
+		logc.Errorf(t.ctx.Ctx, "拨测规则 %s 没有可用的位置结果", rule.RuleId)
+		return
+	}
+
+	event := t.buildEvent(rule)
+	event.Fingerprint = tools.Md5Hash([]byte(rule.ProbingEndpointConfig.Endpoint))
+	event.Labels = map[string]interface{}{
+		"address":          rule.ProbingEndpointConfig.Endpoint,
+		"FailedLocations":  strings.Join(failed, ","),
+		"FailedCount":      float64(len(failed)),
+		"ReportedCount":    float64(total),
+		"Quorum":           float64(rule.GetQuorum()),
+		"LocationsSummary": strings.Join(details, "; "),
+		"value":            float64(len(failed)),
+	}
+	event.Annotations = tools.ParserVariables(rule.Annotations, event.Labels)
+
+	err := SetProbingValueMap(models.BuildProbingValueCacheKey(event.TenantId, event.RuleId), event.Labels)
+	if err != nil {
+		return
+	}
+
+	t.Evaluation(event, models.EvalCondition{
+		Operator:      ">=",
+		QueryValue:    float64(len(failed)),
+		ExpectedValue: float64(rule.GetQuorum()),
+	})
+}
+
+// failedProbingValue 拨测执行失败时记录的结果，按规则评估时视为异常
+func failedProbingValue(rule models.ProbingRule, probeErr error) provider.EndpointValue {
+	return provider.EndpointValue{
+		"address":      rule.ProbingEndpointConfig.Endpoint,
+		"IsSuccessful": float64(0),
+		"ErrorMessage": probeErr.Error(),
+	}
+}
@@ -0,0 +1,101 @@
+package probing
+
+import (
+	"fmt"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/provider"
+)
+
+// RecordHistory 记录拨测历史，事务拨测的步骤明细单独记录，不作为事件标签
+func RecordHistory(ctx *ctx.Context, ruleId, location string, eValue provider.EndpointValue, timestamp int64) error {
+	steps := popTransactionSteps(eValue)
+
+	err := ctx.DB.Probing().AddRecord(models.ProbingHistory{
+		Timestamp: timestamp,
+		RuleId:    ruleId,
+		Location:  location,
+		Value:     eValue,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, step := range steps {
+		name, _ := step["step"].(string)
+		err = ctx.DB.Probing().AddRecord(models.ProbingHistory{
+			Timestamp: timestamp,
+			RuleId:    ruleId,
+			Step:      name,
+			Location:  location,
+			Value:     step,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// popTransactionSteps 取出事务拨测的步骤明细，节点上报的结果经 JSON 解码后为 []interface{}
+func popTransactionSteps(eValue provider.EndpointValue) []map[string]any {
+	var steps []map[string]any
+	switch v := eValue[provider.TransactionStepsKey].(type) {
+	case []provider.EndpointValue:
+		for _, step := range v {
+			steps = append(steps, step)
+		}
+	case []interface{}:
+		for _, step := range v {
+			if m, ok := step.(map[string]interface{}); ok {
+				steps = append(steps, m)
+			}
+		}
+	}
+	delete(eValue, provider.TransactionStepsKey)
+
+	return steps
+}
+
+// BuildEvalCondition 根据拨测结果构建评估条件，条件成立表示拨测异常，QueryValue 为事件标签中的 value
+func BuildEvalCondition(rule models.ProbingRule, eValue provider.EndpointValue) (models.EvalCondition, error) {
+	ruleConfig := rule.ProbingEndpointConfig
+	// 配置了断言的 HTTP 拨测由断言决定结果，未通过的断言记录在 FailedAssertions 标签中
+	assertMode := rule.RuleType == provider.HTTPEndpointProvider && len(ruleConfig.HTTP.Assertions) > 0
+
+	switch {
+	// 如果拨测类型是 TCP、HTTP 事务或 HTTP 断言，直接定义好计算条件 == 0 则表示异常，事务失败的步骤记录在 FailedStep 标签中
+	case rule.RuleType == provider.TCPEndpointProvider, rule.RuleType == provider.HTTPTransactionEndpointProvider:
+		var value float64
+		if isSuccessful(eValue["IsSuccessful"]) {
+			value = 1
+		}
+		return models.EvalCondition{Operator: "==", QueryValue: value, ExpectedValue: 0}, nil
+	case assertMode:
+		value, _ := eValue["AssertionsPassed"].(float64)
+		return models.EvalCondition{Operator: "==", QueryValue: value, ExpectedValue: 0}, nil
+	default:
+		value, ok := eValue[ruleConfig.Strategy.Field].(float64)
+		if !ok {
+			return models.EvalCondition{}, fmt.Errorf("拨测规则 %s 的评估字段 %s 无效", rule.RuleId, ruleConfig.Strategy.Field)
+		}
+		return models.EvalCondition{
+			Operator:      ruleConfig.Strategy.Operator,
+			QueryValue:    value,
+			ExpectedValue: ruleConfig.Strategy.ExpectedValue,
+		}, nil
+	}
+}
+
+// isSuccessful 拨测结果中的 IsSuccessful 统一为 1/0，兼容旧版本拨测节点上报的布尔值
+func isSuccessful(v any) bool {
+	switch value := v.(type) {
+	case bool:
+		return value
+	case float64:
+		return value == 1
+	default:
+		return false
+	}
+}
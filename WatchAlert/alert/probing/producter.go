@@ -2,7 +2,6 @@ package probing
 
 import (
 	"context"
-	"fmt"
 	"time"
 	"watchAlert/alert/process"
 	"watchAlert/internal/ctx"
@@ -67,22 +66,26 @@ func (t *ProductProbing) Eval(ctx context.Context, rule models.ProbingRule) {
 }
 
 func (t *ProductProbing) worker(rule models.ProbingRule) {
-	var (
-		eValue     provider.EndpointValue
-		err        error
-		ruleConfig = rule.ProbingEndpointConfig
-	)
+	if rule.IsDistributed() {
+		t.distributedWorker(rule)
+		return
+	}
 
-	eValue, err = t.runProbing(rule)
+	eValue, err := t.runProbing(rule)
 	if err != nil {
+		logc.Errorf(t.ctx.Ctx, err.Error())
+		// 执行失败同样记录为失败的拨测，避免可用率统计遗漏
+		if err := RecordHistory(t.ctx, rule.RuleId, "", failedProbingValue(rule, err), time.Now().Unix()); err != nil {
+			logc.Errorf(t.ctx.Ctx, err.Error())
+		}
+		return
+	}
+	if err := RecordHistory(t.ctx, rule.RuleId, "", eValue, time.Now().Unix()); err != nil {
 		logc.Errorf(t.ctx.Ctx, err.Error())
 		return
 	}
-	err = t.ctx.DB.Probing().AddRecord(models.ProbingHistory{
-		Timestamp: time.Now().Unix(),
-		RuleId:    rule.RuleId,
-		Value:     eValue,
-	})
+
+	option, err := BuildEvalCondition(rule, eValue)
 	if err != nil {
 		logc.Errorf(t.ctx.Ctx, err.Error())
 		return
@@ -91,34 +94,9 @@ func (t *ProductProbing) worker(rule models.ProbingRule) {
 	event := t.buildEvent(rule)
 	event.Fingerprint = eValue.GetFingerprint()
 	event.Labels = eValue.GetLabels()
-	var isValue float64
-	if rule.RuleType != provider.TCPEndpointProvider {
-		event.Labels["value"] = eValue[ruleConfig.Strategy.Field].(float64)
-	} else {
-		if eValue["IsSuccessful"] == true {
-			isValue = 1
-		}
-		event.Labels["value"] = isValue
-	}
+	event.Labels["value"] = option.QueryValue
 	event.Annotations = tools.ParserVariables(rule.Annotations, event.Labels)
 
-	var option models.EvalCondition
-	switch rule.RuleType {
-	// 如果拨测类型是 TCP ，直接定义好计算条件 == 0 则表示异常
-	case provider.TCPEndpointProvider:
-		option = models.EvalCondition{
-			Operator:      "==",
-			QueryValue:    isValue,
-			ExpectedValue: 0,
-		}
-	default:
-		option = models.EvalCondition{
-			Operator:      ruleConfig.Strategy.Operator,
-			QueryValue:    eValue[ruleConfig.Strategy.Field].(float64),
-			ExpectedValue: ruleConfig.Strategy.ExpectedValue,
-		}
-	}
-
 	err = SetProbingValueMap(models.BuildProbingValueCacheKey(event.TenantId, event.RuleId), eValue)
 	if err != nil {
 		return
@@ -128,39 +106,11 @@ func (t *ProductProbing) worker(rule models.ProbingRule) {
 }
 
 func (t *ProductProbing) runProbing(rule models.ProbingRule) (provider.EndpointValue, error) {
-	var ruleConfig = rule.ProbingEndpointConfig
-	switch rule.RuleType {
-	case provider.ICMPEndpointProvider:
-		return provider.NewEndpointPinger().Pilot(provider.EndpointOption{
-			Endpoint: ruleConfig.Endpoint,
-			Timeout:  ruleConfig.Strategy.Timeout,
-			ICMP: provider.Eicmp{
-				Interval: ruleConfig.ICMP.Interval,
-				Count:    ruleConfig.ICMP.Count,
-			},
-		})
-	case provider.HTTPEndpointProvider:
-		return provider.NewEndpointHTTPer().Pilot(provider.EndpointOption{
-			Endpoint: ruleConfig.Endpoint,
-			Timeout:  ruleConfig.Strategy.Timeout,
-			HTTP: provider.Ehttp{
-				Method: ruleConfig.HTTP.Method,
-				Header: ruleConfig.HTTP.Header,
-				Body:   ruleConfig.HTTP.Body,
-			},
-		})
-	case provider.TCPEndpointProvider:
-		return provider.NewEndpointTcper().Pilot(provider.EndpointOption{
-			Endpoint: ruleConfig.Endpoint,
-			Timeout:  ruleConfig.Strategy.Timeout,
-		})
-	case provider.SSLEndpointProvider:
-		return provider.NewEndpointSSLer().Pilot(provider.EndpointOption{
-			Endpoint: ruleConfig.Endpoint,
-			Timeout:  ruleConfig.Strategy.Timeout,
-		})
+	endpoint, err := provider.NewEndpointProvider(rule.RuleType)
+	if err != nil {
+		return provider.EndpointValue{}, err
 	}
-	return provider.EndpointValue{}, fmt.Errorf("unsupported rule type: %s", rule.RuleType)
+	return endpoint.Pilot(provider.NewEndpointOption(rule.ProbingEndpointConfig))
 }
 
 func (t *ProductProbing) Evaluation(event *models.ProbingEvent, option models.EvalCondition) {
@@ -0,0 +1,122 @@
+package probing
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+	"watchAlert/alert/process"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+var reportTmpl = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent":  func(v float64) string { return fmt.Sprintf("%.3f%%", v) },
+	"duration": formatReportDuration,
+	"mttr":     func(v float64) string { return formatReportDuration(int64(v)) },
+	"latency":  func(v float64) string { return fmt.Sprintf("%.0f", v) },
+}).Parse(`<h3>{{ .Title }}</h3>
+<p>统计周期: {{ .Period }}</p>
+<table border="1" cellspacing="0" cellpadding="6" style="border-collapse: collapse;">
+<tr><th>规则</th><th>类型</th><th>地址</th><th>可用率</th><th>拨测次数</th><th>故障次数</th><th>故障时长</th><th>MTTR</th><th>P50/P90/P99 (ms)</th></tr>
+{{- range .Items }}
+<tr><td>{{ .RuleName }}</td><td>{{ .RuleType }}</td><td>{{ .Endpoint }}</td><td>{{ if .NoData }}无数据{{ else }}{{ percent .Uptime }}{{ end }}</td><td>{{ .Total }}</td><td>{{ .Outages }}</td><td>{{ duration .DowntimeSeconds }}</td><td>{{ mttr .MTTR }}</td><td>{{ latency .LatencyP50 }}/{{ latency .LatencyP90 }}/{{ latency .LatencyP99 }}</td></tr>
+{{- end }}
+</table>
+<p>静默期间的拨测结果不参与统计。</p>
+`))
+
+// SendMonthlyReports 发送上个自然月的可用性报告，同一租户下通知对象相同的规则合并为一份报告
+func SendMonthlyReports(ctx *ctx.Context, now time.Time) {
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	start := end.AddDate(0, -1, 0)
+
+	rules, err := ctx.DB.Probing().ListEnabled()
+	if err != nil {
+		logc.Errorf(ctx.Ctx, "获取拨测规则失败, %s", err.Error())
+		return
+	}
+
+	groups := make(map[[2]string][]models.ProbingRule)
+	for _, rule := range rules {
+		if rule.ReportNoticeId == "" {
+			continue
+		}
+		key := [2]string{rule.TenantId, rule.ReportNoticeId}
+		groups[key] = append(groups[key], rule)
+	}
+
+	title := fmt.Sprintf("拨测可用性月报 %s", start.Format("2006-01"))
+	for key, list := range groups {
+		var items []models.ProbingAvailability
+		for _, rule := range list {
+			availability, err := CalcAvailability(ctx, rule, "", start.Unix(), end.Unix())
+			if err != nil {
+				logc.Errorf(ctx.Ctx, "统计拨测规则 %s 可用性失败, %s", rule.RuleId, err.Error())
+				continue
+			}
+			items = append(items, availability)
+		}
+
+		if err := SendAvailabilityReport(ctx, key[0], key[1], title, items); err != nil {
+			logc.Errorf(ctx.Ctx, "发送拨测可用性报告失败, %s", err.Error())
+		}
+	}
+}
+
+// SendAvailabilityReport 通过通知对象发送可用性报告，邮件为表格，其余通知类型为文本
+func SendAvailabilityReport(ctx *ctx.Context, tenantId, noticeId, title string, items []models.ProbingAvailability) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	noticeData, err := ctx.DB.Notice().Get(tenantId, noticeId)
+	if err != nil {
+		return err
+	}
+
+	period := fmt.Sprintf("%s ~ %s",
+		time.Unix(items[0].StartAt, 0).Format(time.DateTime), time.Unix(items[0].EndAt, 0).Format(time.DateTime))
+
+	var html bytes.Buffer
+	err = reportTmpl.Execute(&html, map[string]interface{}{
+		"Title":  title,
+		"Period": period,
+		"Items":  items,
+	})
+	if err != nil {
+		return err
+	}
+
+	lines := []string{fmt.Sprintf("统计周期: %s", period)}
+	for _, item := range items {
+		if item.NoData {
+			lines = append(lines, fmt.Sprintf("%s (%s): 无数据", item.RuleName, item.Endpoint))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s (%s): 可用率 %.3f%%, 故障 %d 次, 故障时长 %s, MTTR %s, P50/P90/P99 %.0f/%.0f/%.0fms",
+			item.RuleName, item.Endpoint, item.Uptime, item.Outages, formatReportDuration(item.DowntimeSeconds),
+			formatReportDuration(int64(item.MTTR)), item.LatencyP50, item.LatencyP90, item.LatencyP99))
+	}
+
+	now := time.Now().Unix()
+	process.DeliverReport(ctx, noticeData, &models.AlertCurEvent{
+		TenantId:         tenantId,
+		EventId:          "report-" + tools.RandId(),
+		RuleName:         title,
+		Labels:           map[string]interface{}{"report": title, "rule_count": len(items)},
+		Annotations:      strings.Join(lines, "\n"),
+		FirstTriggerTime: now,
+		LastEvalTime:     now,
+	}, html.String())
+
+	return nil
+}
+
+func formatReportDuration(seconds int64) string {
+	return (time.Duration(seconds) * time.Second).String()
+}
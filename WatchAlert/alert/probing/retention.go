@@ -0,0 +1,88 @@
+package probing
+
+import (
+	"time"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/global"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// DownsampleHistory 将超过保留天数的原始拨测记录聚合为小时数据后删除，并清理过期的聚合数据
+func DownsampleHistory(ctx *ctx.Context) error {
+	var (
+		now    = time.Now().Unix()
+		config = global.Config.ProbingHistory
+		cutoff = now - config.GetRawRetentionDays()*86400
+	)
+	// 按整点截断，避免同一小时被拆分为多条聚合数据
+	cutoff -= cutoff % 3600
+
+	ruleIds, err := ctx.DB.Probing().ListRecordRuleIds(cutoff)
+	if err != nil {
+		return err
+	}
+
+	for _, ruleId := range ruleIds {
+		rule, err := ctx.DB.Probing().Search("", ruleId)
+		if err != nil {
+			return err
+		}
+		// 规则已删除，记录直接清理
+		if rule.RuleId == "" {
+			if err := ctx.DB.Probing().DeleteRecord(ruleId, cutoff); err != nil {
+				return err
+			}
+			continue
+		}
+
+		records, err := ctx.DB.Probing().ListRecords(ruleId, 0, cutoff)
+		if err != nil {
+			return err
+		}
+
+		byLocation := make(map[string][]models.ProbingHistory)
+		for _, record := range records {
+			byLocation[record.Location] = append(byLocation[record.Location], record)
+		}
+
+		var (
+			rollups []models.ProbingHistoryRollup
+			bounds  = make(map[string]int64, len(byLocation))
+		)
+		for location, list := range byLocation {
+			before := rollupBoundary(rule, list, cutoff, cutoff-config.GetRawRetentionDays()*86400)
+			bounds[location] = before
+			for len(list) > 0 && list[len(list)-1].Timestamp >= before {
+				list = list[:len(list)-1]
+			}
+			rollups = append(rollups, RollupRecords(rule, location, list)...)
+		}
+		if err := ctx.DB.Probing().ReplaceRecordsWithRollups(ruleId, bounds, rollups); err != nil {
+			return err
+		}
+		logc.Infof(ctx.Ctx, "拨测规则 %s 聚合 %d 条历史记录为 %d 条小时数据", ruleId, len(records), len(rollups))
+	}
+
+	return ctx.DB.Probing().DeleteRollup(now - config.GetRollupRetentionDays()*86400)
+}
+
+// rollupBoundary 计算单个位置的聚合截止时间。截止时间前最后一段连续失败的故障可能在截止时间之后才恢复，
+// 该故障开始的小时及之后的记录暂不聚合，避免同一次故障被聚合数据和原始记录分别统计；
+// 故障开始时间早于 earliest 时不再推迟，防止长期故障的原始记录无法清理
+func rollupBoundary(rule models.ProbingRule, records []models.ProbingHistory, cutoff, earliest int64) int64 {
+	i := len(records)
+	for i > 0 && isProbingFailed(rule, records[i-1].Value) {
+		i--
+	}
+	if i == len(records) {
+		return cutoff
+	}
+
+	since := records[i].Timestamp
+	if since < earliest {
+		return cutoff
+	}
+	return since - since%3600
+}
@@ -0,0 +1,207 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"watchAlert/config"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/metrics"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/provider"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// 同时进行健康检查的数据源数量
+const datasourceHealthWorkers = 10
+
+// StartDatasourceHealthWorker 启动数据源健康检查任务，记录状态、耗时及错误，持续异常时在元故障中心产生事件，仅在 Leader 节点运行
+func StartDatasourceHealthWorker(stop context.Context, ctx *ctx.Context, conf config.DatasourceHealth) {
+	if conf.Disabled {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(conf.GetInterval()) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		checkDatasourcesHealth(ctx, conf)
+
+		select {
+		case <-ticker.C:
+		case <-stop.Done():
+			return
+		}
+	}
+}
+
+// checkDatasourcesHealth 检查所有启用的数据源，并清理已删除或已禁用数据源的状态
+func checkDatasourcesHealth(ctx *ctx.Context, conf config.DatasourceHealth) {
+	datasources, err := ctx.DB.Datasource().List("", "", "", "")
+	if err != nil {
+		logc.Errorf(ctx.Ctx, "获取数据源列表失败: %v", err)
+		return
+	}
+
+	var rules []models.AlertRule
+	if err := ctx.DB.DB().Where("enabled = ?", "1").Find(&rules).Error; err != nil {
+		logc.Errorf(ctx.Ctx, "获取规则列表失败: %v", err)
+		return
+	}
+
+	var (
+		wg        sync.WaitGroup
+		semaphore = make(chan struct{}, datasourceHealthWorkers)
+		checked   = make(map[string]map[string]struct{})
+	)
+	for _, ds := range datasources {
+		if checked[ds.TenantId] == nil {
+			checked[ds.TenantId] = make(map[string]struct{})
+		}
+		if ds.Enabled == nil || !*ds.Enabled {
+			continue
+		}
+		checked[ds.TenantId][ds.ID] = struct{}{}
+
+		wg.Add(1)
+		go func(ds models.AlertDataSource) {
+			semaphore <- struct{}{}
+			defer func() {
+				wg.Done()
+				<-semaphore
+			}()
+
+			checkDatasourceHealth(ctx, conf, ds, blindRules(ds, rules))
+		}(ds)
+	}
+	wg.Wait()
+
+	for tenantId, ids := range checked {
+		for _, health := range ctx.Redis.DatasourceHealth().List(tenantId) {
+			if _, ok := ids[health.DatasourceId]; ok {
+				continue
+			}
+			if health.Alerted {
+				RecoverEvent(ctx, buildDatasourceHealthEvent(ctx, conf, health))
+			}
+			ctx.Redis.DatasourceHealth().Delete(tenantId, health.DatasourceId)
+			metrics.DeleteDatasourceHealth(tenantId, health.DatasourceId, health.DatasourceType)
+		}
+	}
+}
+
+// blindRules 使用该数据源的启用规则
+func blindRules(ds models.AlertDataSource, rules []models.AlertRule) []models.BlindRule {
+	var result []models.BlindRule
+	for _, rule := range rules {
+		if rule.TenantId != ds.TenantId || !slices.Contains(rule.DatasourceIdList, ds.ID) {
+			continue
+		}
+		result = append(result, models.BlindRule{RuleId: rule.RuleId, RuleName: rule.RuleName})
+	}
+	return result
+}
+
+func checkDatasourceHealth(ctx *ctx.Context, conf config.DatasourceHealth, ds models.AlertDataSource, rules []models.BlindRule) {
+	health, _ := ctx.Redis.DatasourceHealth().Get(ds.TenantId, ds.ID)
+	health.TenantId = ds.TenantId
+	health.DatasourceId = ds.ID
+	health.DatasourceName = ds.Name
+	health.DatasourceType = ds.Type
+
+	start := time.Now()
+	ok, err := provider.CheckDatasourceHealth(ds)
+	health.Latency = time.Since(start).Milliseconds()
+	health.LastCheckAt = time.Now().Unix()
+
+	if ok {
+		if health.Alerted {
+			RecoverEvent(ctx, buildDatasourceHealthEvent(ctx, conf, health))
+			logc.Infof(ctx.Ctx, "数据源 %s 已恢复，异常持续 %ds", ds.Name, health.LastCheckAt-health.UnhealthySince)
+		}
+		health.Status = models.DatasourceHealthy
+		health.LastError = ""
+		health.Failures = 0
+		health.UnhealthySince = 0
+		health.LastHealthyAt = health.LastCheckAt
+		health.BlindRules = nil
+		health.Alerted = false
+	} else {
+		if health.Failures == 0 {
+			health.UnhealthySince = health.LastCheckAt
+		}
+		health.Status = models.DatasourceUnhealthy
+		health.Failures++
+		health.BlindRules = rules
+		if err != nil {
+			health.LastError = err.Error()
+		} else {
+			health.LastError = "health check failed"
+		}
+
+		if health.Failures >= conf.GetThreshold() {
+			if event := buildDatasourceHealthEvent(ctx, conf, health); event != nil {
+				PushEventToFaultCenter(ctx, event)
+				health.Alerted = true
+			}
+		}
+	}
+
+	ctx.Redis.DatasourceHealth().Set(health)
+	metrics.SetDatasourceHealth(ds.TenantId, ds.ID, ds.Type, ok, time.Since(start).Seconds(), len(health.BlindRules))
+}
+
+// buildDatasourceHealthEvent 构建数据源异常事件，租户未配置元故障中心时返回 nil
+func buildDatasourceHealthEvent(ctx *ctx.Context, conf config.DatasourceHealth, health models.DatasourceHealth) *models.AlertCurEvent {
+	faultCenterId, ok := conf.FaultCenters[health.TenantId]
+	if !ok || faultCenterId == "" {
+		return nil
+	}
+
+	faultCenter, err := ctx.DB.FaultCenter().Get(health.TenantId, faultCenterId, "")
+	if err != nil {
+		logc.Errorf(ctx.Ctx, "元故障中心 %s 不存在: %v", faultCenterId, err)
+		return nil
+	}
+
+	labels := map[string]interface{}{
+		"datasource_id":   health.DatasourceId,
+		"datasource_type": health.DatasourceType,
+		"alertstate":      models.AlertStateDatasourceUnhealthy,
+	}
+	fingerprint := provider.Metrics{Metric: labels}.GetFingerprint()
+	labels["datasource_name"] = health.DatasourceName
+	labels["blind_rules"] = len(health.BlindRules)
+	labels["fingerprint"] = fingerprint
+
+	return &models.AlertCurEvent{
+		TenantId:             health.TenantId,
+		DatasourceType:       health.DatasourceType,
+		DatasourceId:         health.DatasourceId,
+		RuleId:               "datasource-health-" + health.DatasourceId,
+		RuleName:             fmt.Sprintf("数据源 %s 不可用", health.DatasourceName),
+		Fingerprint:          fingerprint,
+		Severity:             "P1",
+		Labels:               labels,
+		Annotations:          formatDatasourceHealthAnnotations(health),
+		RepeatNoticeInterval: faultCenter.RepeatNoticeInterval,
+		FaultCenterId:        faultCenter.ID,
+	}
+}
+
+func formatDatasourceHealthAnnotations(health models.DatasourceHealth) string {
+	lines := []string{
+		fmt.Sprintf("数据源 %s(%s) 连续 %d 次健康检查失败，异常开始于 %s", health.DatasourceName, health.DatasourceType, health.Failures,
+			time.Unix(health.UnhealthySince, 0).Format(time.DateTime)),
+		fmt.Sprintf("最近错误: %s", health.LastError),
+		fmt.Sprintf("受影响的规则(%d):", len(health.BlindRules)),
+	}
+	for _, rule := range health.BlindRules {
+		lines = append(lines, fmt.Sprintf("- %s(%s)", rule.RuleName, rule.RuleId))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,117 @@
+package process
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// HandleEscalation 按升级级别发送通知，Duty、User 类型通过通知对象的渠道通知对应人员：
+// 邮件发送至用户邮箱，电话、短信呼叫用户手机号，IM 渠道按用户绑定的 IM 用户ID 提及
+func HandleEscalation(ctx *ctx.Context, level models.EscalationLevel, tenantId string, events []models.AlertCurEvent) error {
+	noticeData, err := getNoticeData(ctx, tenantId, level.NoticeId)
+	if err != nil {
+		logc.Error(ctx.Ctx, fmt.Sprintf("Failed to get notice data: %v", err))
+		return err
+	}
+
+	users := getEscalationUsers(ctx, level)
+	if level.GetTargetType() == models.EscalationTargetDuty {
+		noticeData.DutyId = &level.DutyId
+	}
+
+	var (
+		names    []string
+		mentions []string
+		contacts []string
+	)
+	for _, user := range users {
+		contact := models.EscalationContact(noticeData.NoticeType, user)
+		if contact == "" {
+			logc.Errorf(ctx.Ctx, "用户 %s 未配置 %s 通知所需的联系方式, 升级通知无法送达该用户", user.UserName, noticeData.NoticeType)
+			continue
+		}
+		names = append(names, "@"+user.UserName)
+		mentions = append(mentions, models.EscalationMention(noticeData.NoticeType, contact))
+		contacts = append(contacts, contact)
+	}
+
+	var phones []string
+	switch noticeData.NoticeType {
+	case "Email":
+		if len(contacts) > 0 {
+			noticeData.Routes = nil
+			noticeData.Email.To = contacts
+		}
+		mentions = names
+	case "PhoneCall", "SMS":
+		phones = contacts
+		mentions = names
+	}
+
+	for i := range events {
+		event := events[i]
+		if len(mentions) > 0 {
+			event.Annotations = fmt.Sprintf("%s\n升级通知: %s", event.Annotations, strings.Join(mentions, " "))
+			event.DutyUser = strings.Join(mentions, " ")
+		}
+		event.DutyUserPhoneNumber = phones
+
+		Hook, Sign := getNoticeHookUrlAndSign(noticeData, event.Severity)
+		sendEvent(ctx, level.NoticeId, noticeData, Hook, Sign, &event)
+	}
+
+	return nil
+}
+
+// getEscalationUsers 获取升级级别需要通知的人员
+func getEscalationUsers(ctx *ctx.Context, level models.EscalationLevel) []models.Member {
+	switch level.GetTargetType() {
+	case models.EscalationTargetDuty:
+		if level.DutyId == "" {
+			return nil
+		}
+		users, ok := ctx.DB.DutyCalendar().GetDutyUserInfo(level.DutyId, time.Now().Format("2006-01-02"))
+		if !ok {
+			logc.Errorf(ctx.Ctx, "获取值班用户失败或无值班用户, dutyId: %s", level.DutyId)
+			return nil
+		}
+		return users
+	case models.EscalationTargetUser:
+		var users []models.Member
+		for _, userId := range level.UserIds {
+			if userId == "" {
+				continue
+			}
+			user, ok, _ := ctx.DB.User().Get(userId, "", "")
+			if !ok {
+				continue
+			}
+			users = append(users, user)
+		}
+		return users
+	}
+
+	return nil
+}
+
+// RecordEventTimeline 记录告警事件时间线
+func RecordEventTimeline(ctx *ctx.Context, event models.AlertCurEvent, timelineType, content, operator string) {
+	err := ctx.DB.EventTimeline().Create(models.AlertEventTimeline{
+		TenantId:      event.TenantId,
+		FaultCenterId: event.FaultCenterId,
+		EventId:       event.EventId,
+		Fingerprint:   event.Fingerprint,
+		Type:          timelineType,
+		Content:       content,
+		Operator:      operator,
+		CreateAt:      time.Now().Unix(),
+	})
+	if err != nil {
+		logc.Errorf(ctx.Ctx, "记录事件时间线失败, fingerprint: %s, err: %s", event.Fingerprint, err.Error())
+	}
+}
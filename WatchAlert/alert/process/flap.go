@@ -0,0 +1,56 @@
+package process
+
+import (
+	"time"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// RecordFlapTransition 记录事件进入告警或恢复状态，窗口内切换次数达到阈值时标记为抖动
+func RecordFlapTransition(ctx *ctx.Context, event *models.AlertCurEvent) {
+	detection := event.FaultCenter.FlapDetection
+	if !detection.GetEnabled() {
+		return
+	}
+
+	count := ctx.Redis.Flap().AddTransition(event.TenantId, event.FaultCenterId, event.Fingerprint, detection.GetWindow())
+	now := time.Now().Unix()
+
+	state, flapping := ctx.Redis.Flap().GetFlapping(event.TenantId, event.FaultCenterId, event.Fingerprint)
+	if flapping {
+		state.Transitions++
+	} else {
+		if count < detection.GetThreshold() {
+			return
+		}
+		state = models.FlapState{
+			TenantId:      event.TenantId,
+			FaultCenterId: event.FaultCenterId,
+			Fingerprint:   event.Fingerprint,
+			RuleId:        event.RuleId,
+			Since:         now,
+			Transitions:   count,
+		}
+		logc.Infof(ctx.Ctx, "事件进入抖动状态，规则: %s，指纹: %s，%ds 内状态切换 %d 次", event.RuleName, event.Fingerprint, detection.GetWindow(), count)
+	}
+
+	state.RuleName = event.RuleName
+	state.Severity = event.Severity
+	state.Labels = event.Labels
+	state.LastTransition = now
+	state.Status = event.Status
+	ctx.Redis.Flap().SetFlapping(state)
+	event.Flapping = true
+}
+
+// markFlapping 根据抖动状态标记事件
+func markFlapping(ctx *ctx.Context, event *models.AlertCurEvent) {
+	if !event.FaultCenter.FlapDetection.GetEnabled() {
+		event.Flapping = false
+		return
+	}
+
+	_, event.Flapping = ctx.Redis.Flap().GetFlapping(event.TenantId, event.FaultCenterId, event.Fingerprint)
+}
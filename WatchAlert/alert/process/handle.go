@@ -55,37 +55,7 @@ func HandleAlert(ctx *ctx.Context, processType string, faultCenter models.FaultC
 					ctx.Redis.Alert().PushAlertEvent(event)
 				}
 
-				phoneNumber := func() []string {
-					if len(event.DutyUserPhoneNumber) > 0 {
-						return event.DutyUserPhoneNumber
-					}
-					if len(noticeData.PhoneNumber) > 0 {
-						return noticeData.PhoneNumber
-					}
-					return []string{}
-				}()
-
-				event.DutyUser = ""
-				event.DutyUserPhoneNumber = []string{}
-				content := generateAlertContent(ctx, event, noticeData)
-				err := sender.Sender(ctx, sender.SendParams{
-					TenantId:    event.TenantId,
-					EventId:     event.EventId,
-					RuleName:    event.RuleName,
-					Severity:    event.Severity,
-					NoticeType:  noticeData.NoticeType,
-					NoticeId:    noticeId,
-					NoticeName:  noticeData.Name,
-					IsRecovered: event.IsRecovered,
-					Hook:        Hook,
-					Email:       getNoticeEmail(noticeData, severity),
-					Content:     content,
-					PhoneNumber: phoneNumber,
-					Sign:        Sign,
-				})
-				if err != nil {
-					logc.Error(ctx.Ctx, fmt.Sprintf("Failed to send alert: %v", err))
-				}
+				sendEvent(ctx, noticeId, noticeData, Hook, Sign, event)
 			}
 			return nil
 		})
@@ -94,6 +64,150 @@ func HandleAlert(ctx *ctx.Context, processType string, faultCenter models.FaultC
 	return g.Wait()
 }
 
+// HandleGroupAlert 处理标签分组告警，同组事件合并为一条通知发送
+func HandleGroupAlert(ctx *ctx.Context, faultCenter models.FaultCenter, noticeId string, events []*models.AlertCurEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	noticeData, err := getNoticeData(ctx, faultCenter.TenantId, noticeId)
+	if err != nil {
+		logc.Error(ctx.Ctx, fmt.Sprintf("Failed to get notice data: %v", err))
+		return err
+	}
+
+	curTime := time.Now().Unix()
+	var firing *models.AlertCurEvent
+	groupedEvents := make([]models.AlertCurEvent, 0, len(events))
+	for _, event := range events {
+		if !event.IsRecovered {
+			event.LastSendTime = curTime
+			ctx.Redis.Alert().PushAlertEvent(event)
+			// 等级越高字符串越小，P0 > P1 > P2
+			if firing == nil || event.Severity < firing.Severity {
+				firing = event
+			}
+		}
+		groupedEvents = append(groupedEvents, *event)
+	}
+
+	// 以最高等级的告警事件作为通知主体，全部恢复时使用首个恢复事件
+	representative := *events[0]
+	if firing != nil {
+		representative = *firing
+	}
+	representative.IsRecovered = firing == nil
+	representative.GroupedEvents = groupedEvents
+
+	Hook, Sign := getNoticeHookUrlAndSign(noticeData, representative.Severity)
+	sendEvent(ctx, noticeId, noticeData, Hook, Sign, &representative)
+
+	return nil
+}
+
+// sendEvent 生成通知内容并发送，超出通知限流的消息计入风暴摘要
+func sendEvent(ctx *ctx.Context, noticeId string, noticeData models.AlertNotice, hook, sign string, event *models.AlertCurEvent) {
+	if isNoticeSuppressed(ctx, noticeData, event) {
+		return
+	}
+
+	deliverEvent(ctx, noticeId, noticeData, hook, sign, event)
+}
+
+// deliverEvent 发送通知
+func deliverEvent(ctx *ctx.Context, noticeId string, noticeData models.AlertNotice, hook, sign string, event *models.AlertCurEvent) {
+	phoneNumber := func() []string {
+		if len(event.DutyUserPhoneNumber) > 0 {
+			return event.DutyUserPhoneNumber
+		}
+		// 开启值班呼叫后，电话、短信通知优先发送给当前值班用户
+		if noticeData.GetCallDutyUsers() && (noticeData.NoticeType == "PhoneCall" || noticeData.NoticeType == "SMS") {
+			if dutyPhoneNumbers := GetDutyUserPhoneNumbers(ctx, noticeData); len(dutyPhoneNumbers) > 0 {
+				return dutyPhoneNumbers
+			}
+		}
+		if len(noticeData.PhoneNumber) > 0 {
+			return noticeData.PhoneNumber
+		}
+		return []string{}
+	}()
+
+	mentions := event.DutyUser
+	event.DutyUser = ""
+	event.DutyUserPhoneNumber = []string{}
+	// 升级通知已解析出需要提及的用户；Discord、Mattermost 消息正文提及当前值班用户，生成内容后清空，避免写入事件数据
+	switch {
+	case mentions != "":
+		event.DutyUser = mentions
+	case noticeData.NoticeType == "Discord" || noticeData.NoticeType == "Mattermost":
+		event.DutyUser = formatDutyUserMentions(ctx, noticeData.NoticeType, getDutyMembers(ctx, noticeData))
+	}
+	content := generateAlertContent(ctx, event, noticeData)
+	event.DutyUser = ""
+	err := sender.Sender(ctx, sender.SendParams{
+		TenantId:      event.TenantId,
+		EventId:       event.EventId,
+		FaultCenterId: event.FaultCenterId,
+		Fingerprint:   event.Fingerprint,
+		RuleName:      event.RuleName,
+		Severity:      event.Severity,
+		NoticeType:    noticeData.NoticeType,
+		NoticeId:      noticeId,
+		NoticeName:    noticeData.Name,
+		IsRecovered:   event.IsRecovered,
+		Hook:          hook,
+		Email:         getNoticeEmail(noticeData, event.Severity),
+		Content:       content,
+		PhoneNumber:   phoneNumber,
+		Sign:          sign,
+		ChatId:        noticeData.ChatId,
+	})
+	if err != nil {
+		logc.Error(ctx.Ctx, fmt.Sprintf("Failed to send alert: %v", err))
+	}
+}
+
+// formatDutyUserMentions 按通知渠道的提及语法生成值班用户提及文本，如 Discord 的 <@id>、Mattermost 的 @username
+func formatDutyUserMentions(ctx *ctx.Context, noticeType string, users []models.Member) string {
+	mentions := make([]string, 0, len(users))
+	for _, user := range users {
+		contact := models.EscalationContact(noticeType, user)
+		if contact == "" {
+			logc.Errorf(ctx.Ctx, "值班用户 %s 未绑定 %s 用户ID, 无法提及该用户", user.UserName, noticeType)
+			continue
+		}
+		mentions = append(mentions, models.EscalationMention(noticeType, contact))
+	}
+	return strings.Join(mentions, " ")
+}
+
+// DeliverReport 发送报告类消息，邮件直接发送 html 内容，其余通知类型按通知模版渲染 event
+func DeliverReport(ctx *ctx.Context, noticeData models.AlertNotice, event *models.AlertCurEvent, html string) {
+	hook, sign := getNoticeHookUrlAndSign(noticeData, event.Severity)
+	if noticeData.NoticeType != "Email" {
+		deliverEvent(ctx, noticeData.Uuid, noticeData, hook, sign, event)
+		return
+	}
+
+	email := getNoticeEmail(noticeData, event.Severity)
+	email.Subject = event.RuleName
+	err := sender.Sender(ctx, sender.SendParams{
+		TenantId:   event.TenantId,
+		EventId:    event.EventId,
+		RuleName:   event.RuleName,
+		Severity:   event.Severity,
+		NoticeType: noticeData.NoticeType,
+		NoticeId:   noticeData.Uuid,
+		NoticeName: noticeData.Name,
+		Email:      email,
+		Content:    html,
+		IsReport:   true,
+	})
+	if err != nil {
+		logc.Error(ctx.Ctx, fmt.Sprintf("Failed to send report: %v", err))
+	}
+}
+
 // alarmAggregation 告警聚合
 func alarmAggregation(ctx *ctx.Context, processType string, faultCenter models.FaultCenter, alertGroups map[string][]*models.AlertCurEvent) map[string][]*models.AlertCurEvent {
 	// 仅当 processType 为 "alarm" 时执行聚合
@@ -104,7 +218,7 @@ func alarmAggregation(ctx *ctx.Context, processType string, faultCenter models.F
 	curTime := time.Now().Unix()
 	newAlertGroups := alertGroups
 	switch faultCenter.GetAlarmAggregationType() {
-	case "Rule":
+	case models.AggregationTypeRule:
 		for severity, events := range alertGroups {
 			newAlertGroups[severity] = withRuleGroupByAlerts(ctx, curTime, events)
 		}
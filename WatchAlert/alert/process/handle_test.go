@@ -0,0 +1,30 @@
+package process
+
+import (
+	"context"
+	"testing"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+)
+
+func TestFormatDutyUserMentions(t *testing.T) {
+	c := &ctx.Context{Ctx: context.Background()}
+	users := []models.Member{
+		{UserName: "alice", DutyUserId: "111"},
+		{UserName: "bob"},
+	}
+
+	tests := []struct {
+		noticeType string
+		want       string
+	}{
+		// 未绑定 Discord 用户ID 的值班用户无法提及
+		{noticeType: "Discord", want: "<@111>"},
+		{noticeType: "Mattermost", want: "@111 @bob"},
+	}
+	for _, tt := range tests {
+		if got := formatDutyUserMentions(c, tt.noticeType, users); got != tt.want {
+			t.Fatalf("formatDutyUserMentions(%s) = %q, want %q", tt.noticeType, got, tt.want)
+		}
+	}
+}
@@ -44,10 +44,10 @@ func PushEventToFaultCenter(ctx *ctx.Context, event *models.AlertCurEvent) {
 	cacheEvent, _ := cache.Alert().GetEventFromCache(event.TenantId, event.FaultCenterId, event.Fingerprint)
 
 	// 获取基础信息
-	event.FirstTriggerTime = cacheEvent.GetFirstTime()
-	event.LastEvalTime = cacheEvent.GetLastEvalTime()
+	event.InheritEvalTime(cacheEvent)
 	event.LastSendTime = cacheEvent.GetLastSendTime()
 	event.ConfirmState = cacheEvent.GetLastConfirmState()
+	event.EscalationState = cacheEvent.EscalationState
 	event.EventId = cacheEvent.GetEventId()
 	event.FaultCenter = cache.FaultCenter().GetFaultCenterInfo(models.BuildFaultCenterInfoCacheKey(event.TenantId, event.FaultCenterId))
 
@@ -64,6 +64,9 @@ func PushEventToFaultCenter(ctx *ctx.Context, event *models.AlertCurEvent) {
 	// 检查是否处于静默状态
 	isSilenced := IsSilencedEvent(event)
 
+	// 标记抖动状态
+	markFlapping(ctx, event)
+
 	// 根据不同情况处理状态转换
 	switch event.Status {
 	case models.StatePreAlert:
@@ -74,7 +77,7 @@ func PushEventToFaultCenter(ctx *ctx.Context, event *models.AlertCurEvent) {
 			// 如果达到持续时间，转为告警状态
 			event.TransitionStatus(models.StateAlerting)
 		}
-	case models.StateAlerting:
+	case models.StateAlerting, models.StateInhibited:
 		// 如果需要静默
 		if isSilenced {
 			event.TransitionStatus(models.StateSilenced)
@@ -103,27 +106,80 @@ func PushEventToFaultCenter(ctx *ctx.Context, event *models.AlertCurEvent) {
 	cache.Alert().PushAlertEvent(event)
 }
 
-// GetDutyUsers 获取值班用户列表
-func GetDutyUsers(ctx *ctx.Context, noticeData models.AlertNotice) []string {
+// RecoverEvent 外部推送的恢复事件按 pending_recovery → recovered 的路径恢复，无需等待 Recover 巡检
+func RecoverEvent(ctx *ctx.Context, event *models.AlertCurEvent) {
+	if event == nil {
+		return
+	}
+
+	cacheEvent, err := ctx.Redis.Alert().GetEventFromCache(event.TenantId, event.FaultCenterId, event.Fingerprint)
+	if err != nil || cacheEvent.Fingerprint == "" {
+		return
+	}
+
+	switch cacheEvent.Status {
+	case models.StatePreAlert:
+		// 未达到告警状态的事件直接移除
+		ctx.Redis.Alert().RemoveAlertEvent(cacheEvent.TenantId, cacheEvent.FaultCenterId, cacheEvent.Fingerprint)
+		return
+	case models.StateRecovered:
+		return
+	}
+
+	newEvent := cacheEvent
+	if err := newEvent.TransitionStatus(models.StatePendingRecovery); err != nil {
+		logc.Errorf(ctx.Ctx, "Failed to transition to「pending_recovery」state for fingerprint %s: %v", newEvent.Fingerprint, err)
+		return
+	}
+	ctx.Redis.Alert().PushAlertEvent(&newEvent)
+
+	if err := newEvent.TransitionStatus(models.StateRecovered); err != nil {
+		logc.Errorf(ctx.Ctx, "Failed to transition to recovered state for fingerprint %s: %v", newEvent.Fingerprint, err)
+		return
+	}
+	newEvent.LastEvalTime = time.Now().Unix()
+	RecordFlapTransition(ctx, &newEvent)
+	ctx.Redis.Alert().PushAlertEvent(&newEvent)
+	PushEventToFaultCenter(ctx, &newEvent)
+}
+
+// getDutyMembers 获取通知对象关联值班表的当前值班用户
+func getDutyMembers(ctx *ctx.Context, noticeData models.AlertNotice) []models.Member {
 	if noticeData.DutyId == nil || *noticeData.DutyId == "" {
-		return []string{}
+		return nil
 	}
 
-	// 获取当前值班用户
 	users, ok := ctx.DB.DutyCalendar().GetDutyUserInfo(*noticeData.DutyId, time.Now().Format("2006-01-02"))
 	if !ok || len(users) == 0 {
 		logc.Errorf(ctx.Ctx, "获取值班用户失败或无值班用户")
-		return []string{}
+		return nil
 	}
 
-	var usernames []string
-	for _, user := range users {
+	return users
+}
+
+// GetDutyUsers 获取值班用户列表
+func GetDutyUsers(ctx *ctx.Context, noticeData models.AlertNotice) []string {
+	usernames := []string{}
+	for _, user := range getDutyMembers(ctx, noticeData) {
 		usernames = append(usernames, user.UserName)
 	}
 
 	return usernames
 }
 
+// GetDutyUserPhoneNumbers 获取值班用户手机号，用于电话、短信通知
+func GetDutyUserPhoneNumbers(ctx *ctx.Context, noticeData models.AlertNotice) []string {
+	phoneNumbers := []string{}
+	for _, user := range getDutyMembers(ctx, noticeData) {
+		if user.Phone != "" {
+			phoneNumbers = append(phoneNumbers, user.Phone)
+		}
+	}
+
+	return phoneNumbers
+}
+
 // RecordAlertHisEvent 记录告警历史事件
 func RecordAlertHisEvent(ctx *ctx.Context, alert models.AlertCurEvent) error {
 	hisEvent := models.AlertHisEvent{
@@ -157,15 +213,19 @@ func RecordAlertHisEvent(ctx *ctx.Context, alert models.AlertCurEvent) error {
 
 // handleAlertStatusChange 处理告警状态变化
 func handleAlertStatusChange(ctx *ctx.Context, oldEvent *models.AlertCurEvent, newEvent *models.AlertCurEvent, oldStatus, newStatus models.AlertStatus) {
-	// 当告警状态变为alerting时，尝试创建工单
-	if newStatus == models.StateAlerting {
+	if newStatus == models.StateAlerting || newStatus == models.StateRecovered {
+		RecordFlapTransition(ctx, newEvent)
+	}
+
+	// 当告警状态变为alerting时，尝试创建工单；抖动期间暂停通知时不再重复创建工单，稳定后由摘要统一通知
+	if newStatus == models.StateAlerting && !(newEvent.Flapping && newEvent.FaultCenter.FlapDetection.GetHoldNotice()) {
 		// 发布告警转工单事件
 		publishAlertTicketEvent(ctx, newEvent, "create_ticket")
 		logc.Infof(ctx.Ctx, "发布告警转工单事件，事件ID: %s，规则: %s，租户: %s，故障中心: %s，严重程度: %s",
 			newEvent.EventId, newEvent.RuleName, newEvent.TenantId, newEvent.FaultCenterId, newEvent.Severity)
 	}
 
-	// 当告警恢复时，记录相关信息
+	// 当告警恢复时，记录相关信息；抖动期间同样发布，保证已创建的工单能够关闭
 	if newStatus == models.StateRecovered {
 		// 发布告警恢复事件
 		publishAlertTicketEvent(ctx, newEvent, "alert_recovered")
@@ -0,0 +1,125 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/metrics"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// 风暴摘要检查间隔
+const stormDigestCheckInterval = 10 * time.Second
+
+// isNoticeSuppressed 通知限流检查，超出限制时进入风暴模式并记录被抑制的消息
+func isNoticeSuppressed(ctx *ctx.Context, noticeData models.AlertNotice, event *models.AlertCurEvent) bool {
+	rateLimit := noticeData.RateLimit
+	if !rateLimit.GetEnabled() || rateLimit.IsBypass(event.Severity) {
+		return false
+	}
+
+	if ctx.Redis.NoticeLimit().Allow(noticeData.TenantId, noticeData.Uuid, rateLimit.PerMinute, rateLimit.GetBurst()) {
+		return false
+	}
+
+	ctx.Redis.NoticeLimit().AddSuppressed(noticeData.TenantId, noticeData.Uuid, event.Severity, event.RuleName)
+	metrics.IncNoticeSuppressed(noticeData.TenantId, noticeData.Uuid, event.Severity)
+	logc.Infof(ctx.Ctx, "通知对象 %s 触发限流，消息已计入风暴摘要，规则: %s，等级: %s", noticeData.Name, event.RuleName, event.Severity)
+	return true
+}
+
+// StartStormDigestWorker 启动风暴摘要任务，按通知对象的摘要间隔合并发送被抑制的消息，仅在 Leader 节点运行
+func StartStormDigestWorker(stop context.Context, ctx *ctx.Context) {
+	ticker := time.NewTicker(stormDigestCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			flushStormDigests(ctx)
+		case <-stop.Done():
+			return
+		}
+	}
+}
+
+// flushStormDigests 发送已到达摘要间隔的风暴摘要
+func flushStormDigests(ctx *ctx.Context) {
+	now := time.Now().Unix()
+	for _, storm := range ctx.Redis.NoticeLimit().ListStorms() {
+		noticeData, err := getNoticeData(ctx, storm.TenantId, storm.NoticeId)
+		if err != nil {
+			// 通知对象已删除
+			ctx.Redis.NoticeLimit().PopSuppressed(storm.TenantId, storm.NoticeId)
+			continue
+		}
+
+		if now < storm.Since+noticeData.RateLimit.GetDigestInterval() {
+			continue
+		}
+
+		suppressed := ctx.Redis.NoticeLimit().PopSuppressed(storm.TenantId, storm.NoticeId)
+		if len(suppressed) == 0 {
+			continue
+		}
+
+		event := buildStormDigestEvent(noticeData, storm.Since, now, suppressed)
+		Hook, Sign := getNoticeHookUrlAndSign(noticeData, event.Severity)
+		deliverEvent(ctx, noticeData.Uuid, noticeData, Hook, Sign, event)
+	}
+}
+
+// buildStormDigestEvent 构建风暴摘要事件，按规则与等级列出被抑制的消息数量
+func buildStormDigestEvent(noticeData models.AlertNotice, since, now int64, suppressed map[string]int64) *models.AlertCurEvent {
+	type item struct {
+		Severity string
+		RuleName string
+		Count    int64
+	}
+
+	var (
+		items    []item
+		total    int64
+		severity string
+	)
+	for key, count := range suppressed {
+		sev, ruleName, _ := strings.Cut(key, "|")
+		items = append(items, item{Severity: sev, RuleName: ruleName, Count: count})
+		total += count
+		// 等级越高字符串越小，P0 > P1 > P2
+		if severity == "" || sev < severity {
+			severity = sev
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Severity != items[j].Severity {
+			return items[i].Severity < items[j].Severity
+		}
+		return items[i].Count > items[j].Count
+	})
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("通知对象「%s」触发限流（%d 条/分钟），%s 至 %s 期间共抑制 %d 条消息：",
+		noticeData.Name, noticeData.RateLimit.PerMinute, time.Unix(since, 0).Format("15:04:05"), time.Unix(now, 0).Format("15:04:05"), total))
+	for _, i := range items {
+		lines = append(lines, fmt.Sprintf("[%s] %s: %d 条", i.Severity, i.RuleName, i.Count))
+	}
+
+	return &models.AlertCurEvent{
+		TenantId:         noticeData.TenantId,
+		EventId:          "storm-" + tools.RandId(),
+		RuleName:         "告警风暴摘要",
+		Severity:         severity,
+		Labels:           map[string]interface{}{"notice_name": noticeData.Name, "suppressed_total": total},
+		Annotations:      strings.Join(lines, "\n"),
+		FirstTriggerTime: since,
+		LastEvalTime:     now,
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"strings"
+	"watchAlert/internal/services"
+	"watchAlert/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+type alertmanagerController struct{}
+
+var AlertmanagerController = new(alertmanagerController)
+
+/*
+Alertmanager 事件接入 API
+/api/w8t/alertmanager/:tenantId/:faultCenterId
+Alertmanager webhook_configs 指向 .../webhook
+Prometheus alertmanagers path_prefix 指向 /api/w8t/alertmanager/:tenantId/:faultCenterId
+Token 可通过 token 参数、X-Alertmanager-Token 请求头或 Authorization: Bearer 传递
+*/
+func (alertmanagerController alertmanagerController) API(gin *gin.RouterGroup) {
+	a := gin.Group("alertmanager/:tenantId/:faultCenterId")
+	{
+		a.POST("webhook", alertmanagerController.Webhook)
+		a.POST("api/v2/alerts", alertmanagerController.PushAlerts)
+	}
+}
+
+func (alertmanagerController alertmanagerController) Webhook(ctx *gin.Context) {
+	r := new(types.RequestAlertmanagerWebhook)
+	if err := BindJson(ctx, r); err != nil {
+		return
+	}
+
+	r.TenantId = ctx.Param("tenantId")
+	r.FaultCenterId = ctx.Param("faultCenterId")
+	r.Token = getAlertmanagerToken(ctx)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.AlertmanagerService.Webhook(r)
+	})
+}
+
+func (alertmanagerController alertmanagerController) PushAlerts(ctx *gin.Context) {
+	r := new(types.RequestAlertmanagerPush)
+	if err := BindJson(ctx, &r.Alerts); err != nil {
+		return
+	}
+
+	r.TenantId = ctx.Param("tenantId")
+	r.FaultCenterId = ctx.Param("faultCenterId")
+	r.Token = getAlertmanagerToken(ctx)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.AlertmanagerService.PushAlerts(r)
+	})
+}
+
+// getAlertmanagerToken 获取推送 Token，兼容 Alertmanager http_config.authorization 与 Prometheus alertmanagers.authorization
+func getAlertmanagerToken(ctx *gin.Context) string {
+	if token := ctx.Query("token"); token != "" {
+		return token
+	}
+	if token := ctx.GetHeader("X-Alertmanager-Token"); token != "" {
+		return token
+	}
+	return strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+}
@@ -50,6 +50,7 @@ func (datasourceController datasourceController) API(gin *gin.RouterGroup) {
 	{
 		b.GET("dataSourceList", datasourceController.List)
 		b.GET("dataSourceGet", datasourceController.Get)
+		b.GET("dataSourceHealth", datasourceController.Health)
 	}
 
 	c := gin.Group("datasource")
@@ -105,6 +106,18 @@ func (datasourceController datasourceController) Get(ctx *gin.Context) {
 	})
 }
 
+func (datasourceController datasourceController) Health(ctx *gin.Context) {
+	r := new(types.RequestDatasourceQuery)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.DatasourceService.Health(r)
+	})
+}
+
 func (datasourceController datasourceController) Update(ctx *gin.Context) {
 	r := new(types.RequestDatasourceUpdate)
 	BindJson(ctx, r)
@@ -227,6 +240,8 @@ func (datasourceController datasourceController) Ping(ctx *gin.Context) {
 			DsAliCloudConfig: r.DsAliCloudConfig,
 			AWSCloudWatch:    r.AWSCloudWatch,
 			ClickHouseConfig: r.ClickHouseConfig,
+			InfluxDBConfig:   r.InfluxDBConfig,
+			SQLConfig:        r.SQLConfig,
 			Description:      r.Description,
 			KubeConfig:       r.KubeConfig,
 			Enabled:          r.Enabled,
@@ -300,6 +315,19 @@ func (datasourceController datasourceController) SearchViewLogsContent(ctx *gin.
 					Query: QueryStr,
 				},
 			}
+		case provider.MySQLDsProviderName, provider.PostgreSQLDsProviderName:
+			sqlCli, err := provider.NewSQLClient(datasource)
+			if err != nil {
+				return nil, err
+			}
+			defer sqlCli.Close()
+
+			client = sqlCli
+			options = provider.LogQueryOptions{
+				SQL: provider.SQL{
+					Query: QueryStr,
+				},
+			}
 		}
 
 		query, _, err := client.Query(options)
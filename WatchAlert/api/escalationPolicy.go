@@ -0,0 +1,108 @@
+package api
+
+import (
+	"watchAlert/internal/middleware"
+	"watchAlert/internal/services"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/tools"
+
+	"github.com/gin-gonic/gin"
+)
+
+type escalationPolicyController struct{}
+
+var EscalationPolicyController = new(escalationPolicyController)
+
+/*
+告警升级策略 API
+/api/w8t/escalationPolicy
+*/
+func (escalationPolicyController escalationPolicyController) API(gin *gin.RouterGroup) {
+	a := gin.Group("escalationPolicy")
+	a.Use(
+		middleware.Auth(),
+		middleware.Permission(),
+		middleware.ParseTenant(),
+		middleware.AuditingLog(),
+	)
+	{
+		a.POST("escalationPolicyCreate", escalationPolicyController.Create)
+		a.POST("escalationPolicyUpdate", escalationPolicyController.Update)
+		a.POST("escalationPolicyDelete", escalationPolicyController.Delete)
+	}
+
+	b := gin.Group("escalationPolicy")
+	b.Use(
+		middleware.Auth(),
+		middleware.Permission(),
+		middleware.ParseTenant(),
+	)
+	{
+		b.GET("escalationPolicyList", escalationPolicyController.List)
+		b.GET("escalationPolicyGet", escalationPolicyController.Get)
+	}
+}
+
+func (escalationPolicyController escalationPolicyController) Create(ctx *gin.Context) {
+	r := new(types.RequestEscalationPolicyCreate)
+	BindJson(ctx, r)
+
+	r.UpdateBy = tools.GetUser(ctx.Request.Header.Get("Authorization"))
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.EscalationPolicyService.Create(r)
+	})
+}
+
+func (escalationPolicyController escalationPolicyController) Update(ctx *gin.Context) {
+	r := new(types.RequestEscalationPolicyUpdate)
+	BindJson(ctx, r)
+
+	r.UpdateBy = tools.GetUser(ctx.Request.Header.Get("Authorization"))
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.EscalationPolicyService.Update(r)
+	})
+}
+
+func (escalationPolicyController escalationPolicyController) Delete(ctx *gin.Context) {
+	r := new(types.RequestEscalationPolicyQuery)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.EscalationPolicyService.Delete(r)
+	})
+}
+
+func (escalationPolicyController escalationPolicyController) List(ctx *gin.Context) {
+	r := new(types.RequestEscalationPolicyQuery)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.EscalationPolicyService.List(r)
+	})
+}
+
+func (escalationPolicyController escalationPolicyController) Get(ctx *gin.Context) {
+	r := new(types.RequestEscalationPolicyQuery)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.EscalationPolicyService.Get(r)
+	})
+}
@@ -41,6 +41,7 @@ func (alertEventController alertEventController) API(gin *gin.RouterGroup) {
 	{
 		b.GET("curEvent", alertEventController.ListCurrentEvent)
 		b.GET("hisEvent", alertEventController.ListHistoryEvent)
+		b.GET("timeline", alertEventController.ListTimeline)
 	}
 }
 
@@ -113,6 +114,18 @@ func (alertEventController alertEventController) ListComment(ctx *gin.Context) {
 	})
 }
 
+func (alertEventController alertEventController) ListTimeline(ctx *gin.Context) {
+	r := new(types.RequestListEventTimeline)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.EventService.ListTimeline(r)
+	})
+}
+
 func (alertEventController alertEventController) AddComment(ctx *gin.Context) {
 	r := new(types.RequestAddEventComment)
 	BindJson(ctx, r)
@@ -25,6 +25,7 @@ func (faultCenterController faultCenterController) API(gin *gin.RouterGroup) {
 		faultCenterA.POST("faultCenterUpdate", faultCenterController.Update)
 		faultCenterA.POST("faultCenterDelete", faultCenterController.Delete)
 		faultCenterA.POST("faultCenterReset", faultCenterController.Reset)
+		faultCenterA.POST("faultCenterResetAlertmanagerToken", faultCenterController.ResetAlertmanagerToken)
 	}
 
 	faultCenterB := gin.Group("faultCenter")
@@ -84,6 +85,18 @@ func (faultCenterController faultCenterController) Delete(ctx *gin.Context) {
 	})
 }
 
+func (faultCenterController faultCenterController) ResetAlertmanagerToken(ctx *gin.Context) {
+	r := new(types.RequestFaultCenterQuery)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.FaultCenterService.ResetAlertmanagerToken(r)
+	})
+}
+
 func (faultCenterController faultCenterController) List(ctx *gin.Context) {
 	r := new(types.RequestFaultCenterQuery)
 	BindQuery(ctx, r)
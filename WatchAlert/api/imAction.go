@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"watchAlert/internal/services"
+	"watchAlert/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+type imActionController struct{}
+
+var ImActionController = new(imActionController)
+
+/*
+IM 卡片交互回调 API
+/api/w8t/im
+由 IM 平台直接回调，不经过登录鉴权，通过平台签名及按钮签名校验
+*/
+func (imActionController imActionController) API(gin *gin.RouterGroup) {
+	a := gin.Group("im")
+	{
+		a.POST("feishu/callback", imActionController.FeiShuCallback)
+		a.POST("slack/callback", imActionController.SlackCallback)
+		a.POST("dingding/callback", imActionController.DingDingCallback)
+	}
+}
+
+func (imActionController imActionController) FeiShuCallback(ctx *gin.Context) {
+	body, _ := ctx.GetRawData()
+	r := &types.RequestImFeiShuCallback{
+		Timestamp: ctx.GetHeader("X-Lark-Request-Timestamp"),
+		Nonce:     ctx.GetHeader("X-Lark-Request-Nonce"),
+		Signature: ctx.GetHeader("X-Lark-Signature"),
+		Body:      body,
+	}
+
+	data, err := services.ImActionService.FeiShuCallback(r)
+	if err != nil {
+		// 飞书要求回调返回 200，错误以 toast 形式提示
+		ctx.JSON(http.StatusOK, gin.H{
+			"toast": gin.H{"type": "error", "content": fmt.Sprint(err)},
+		})
+		return
+	}
+
+	if card, ok := data.(string); ok {
+		ctx.Data(http.StatusOK, "application/json; charset=utf-8", []byte(card))
+		return
+	}
+	ctx.JSON(http.StatusOK, data)
+}
+
+func (imActionController imActionController) SlackCallback(ctx *gin.Context) {
+	body, _ := ctx.GetRawData()
+	r := &types.RequestImSlackCallback{
+		Timestamp: ctx.GetHeader("X-Slack-Request-Timestamp"),
+		Signature: ctx.GetHeader("X-Slack-Signature"),
+		Body:      body,
+	}
+
+	_, err := services.ImActionService.SlackCallback(r)
+	if err != nil {
+		ctx.JSON(http.StatusOK, gin.H{
+			"response_type":    "ephemeral",
+			"replace_original": false,
+			"text":             fmt.Sprint(err),
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func (imActionController imActionController) DingDingCallback(ctx *gin.Context) {
+	body, _ := ctx.GetRawData()
+	r := &types.RequestImDingDingCallback{
+		Timestamp: ctx.GetHeader("timestamp"),
+		Sign:      ctx.GetHeader("sign"),
+		Body:      body,
+	}
+
+	// 返回内容由机器人回复到会话中
+	data, err := services.ImActionService.DingDingCallback(r)
+	content := fmt.Sprint(data)
+	if err != nil {
+		content = fmt.Sprintf("操作失败: %v", err)
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"msgtype": "text",
+		"text":    gin.H{"content": content},
+	})
+}
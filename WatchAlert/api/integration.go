@@ -0,0 +1,153 @@
+package api
+
+import (
+	"watchAlert/internal/middleware"
+	"watchAlert/internal/services"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/tools"
+
+	"github.com/gin-gonic/gin"
+)
+
+type integrationController struct{}
+
+var IntegrationController = new(integrationController)
+
+/*
+事件集成 API
+/api/w8t/integration
+外部系统推送地址: /api/w8t/integration/push/:id?token=xxx
+*/
+func (integrationController integrationController) API(gin *gin.RouterGroup) {
+	a := gin.Group("integration")
+	a.Use(
+		middleware.Auth(),
+		middleware.Permission(),
+		middleware.ParseTenant(),
+		middleware.AuditingLog(),
+	)
+	{
+		a.POST("integrationCreate", integrationController.Create)
+		a.POST("integrationUpdate", integrationController.Update)
+		a.POST("integrationDelete", integrationController.Delete)
+		a.POST("integrationResetToken", integrationController.ResetToken)
+	}
+
+	b := gin.Group("integration")
+	b.Use(
+		middleware.Auth(),
+		middleware.Permission(),
+		middleware.ParseTenant(),
+	)
+	{
+		b.GET("integrationList", integrationController.List)
+		b.GET("integrationGet", integrationController.Get)
+	}
+
+	c := gin.Group("integration")
+	{
+		c.POST("push/:id", integrationController.Push)
+	}
+}
+
+func (integrationController integrationController) Create(ctx *gin.Context) {
+	r := new(types.RequestIntegrationCreate)
+	if err := BindJson(ctx, r); err != nil {
+		return
+	}
+
+	r.UpdateBy = tools.GetUser(ctx.Request.Header.Get("Authorization"))
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.IntegrationService.Create(r)
+	})
+}
+
+func (integrationController integrationController) Update(ctx *gin.Context) {
+	r := new(types.RequestIntegrationUpdate)
+	if err := BindJson(ctx, r); err != nil {
+		return
+	}
+
+	r.UpdateBy = tools.GetUser(ctx.Request.Header.Get("Authorization"))
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.IntegrationService.Update(r)
+	})
+}
+
+func (integrationController integrationController) Delete(ctx *gin.Context) {
+	r := new(types.RequestIntegrationQuery)
+	if err := BindJson(ctx, r); err != nil {
+		return
+	}
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.IntegrationService.Delete(r)
+	})
+}
+
+func (integrationController integrationController) ResetToken(ctx *gin.Context) {
+	r := new(types.RequestIntegrationQuery)
+	if err := BindJson(ctx, r); err != nil {
+		return
+	}
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.IntegrationService.ResetToken(r)
+	})
+}
+
+func (integrationController integrationController) List(ctx *gin.Context) {
+	r := new(types.RequestIntegrationQuery)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.IntegrationService.List(r)
+	})
+}
+
+func (integrationController integrationController) Get(ctx *gin.Context) {
+	r := new(types.RequestIntegrationQuery)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.IntegrationService.Get(r)
+	})
+}
+
+// Push 外部系统推送事件，Token 可通过 token 参数或 X-Integration-Token 请求头传递
+func (integrationController integrationController) Push(ctx *gin.Context) {
+	r := new(types.RequestIntegrationPush)
+	if err := BindJson(ctx, &r.Payload); err != nil {
+		return
+	}
+
+	r.ID = ctx.Param("id")
+	r.Token = ctx.Query("token")
+	if r.Token == "" {
+		r.Token = ctx.GetHeader("X-Integration-Token")
+	}
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.IntegrationService.Push(r)
+	})
+}
@@ -30,6 +30,8 @@ func (noticeController noticeController) API(gin *gin.RouterGroup) {
 		a.POST("noticeCreate", noticeController.Create)
 		a.POST("noticeUpdate", noticeController.Update)
 		a.POST("noticeDelete", noticeController.Delete)
+		a.POST("noticeDeadLetterReplay", noticeController.ReplayDeadLetter)
+		a.POST("noticeDeadLetterDiscard", noticeController.DiscardDeadLetter)
 	}
 
 	b := gin.Group("notice")
@@ -41,6 +43,7 @@ func (noticeController noticeController) API(gin *gin.RouterGroup) {
 	{
 		b.GET("noticeList", noticeController.List)
 		b.GET("noticeRecordList", noticeController.ListRecord)
+		b.GET("noticeDeadLetterList", noticeController.ListDeadLetter)
 	}
 
 	c := gin.Group("notice")
@@ -172,3 +175,39 @@ func (noticeController noticeController) DeleteRecord(ctx *gin.Context) {
 		return services.NoticeService.DeleteRecord(r)
 	})
 }
+
+func (noticeController noticeController) ListDeadLetter(ctx *gin.Context) {
+	r := new(types.RequestNoticeRetryQuery)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.NoticeService.ListDeadLetter(r)
+	})
+}
+
+func (noticeController noticeController) ReplayDeadLetter(ctx *gin.Context) {
+	r := new(types.RequestNoticeRetryQuery)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.NoticeService.ReplayDeadLetter(r)
+	})
+}
+
+func (noticeController noticeController) DiscardDeadLetter(ctx *gin.Context) {
+	r := new(types.RequestNoticeRetryQuery)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.NoticeService.DiscardDeadLetter(r)
+	})
+}
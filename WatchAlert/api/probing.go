@@ -38,6 +38,8 @@ func (probingController probingController) API(gin *gin.RouterGroup) {
 		b.GET("listProbing", probingController.List)
 		b.GET("searchProbing", probingController.Search)
 		b.GET("getProbingHistory", probingController.GetHistory)
+		b.GET("getProbingStepHistory", probingController.GetStepHistory)
+		b.GET("getProbingAvailability", probingController.GetAvailability)
 	}
 
 	c := gin.Group("probing")
@@ -141,6 +143,27 @@ func (probingController probingController) GetHistory(ctx *gin.Context) {
 	})
 }
 
+func (probingController probingController) GetStepHistory(ctx *gin.Context) {
+	r := new(types.RequestProbingStepHistoryRecord)
+	BindQuery(ctx, r)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.ProbingService.GetStepHistory(r)
+	})
+}
+
+func (probingController probingController) GetAvailability(ctx *gin.Context) {
+	r := new(types.RequestProbingAvailability)
+	BindQuery(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.ProbingService.GetAvailability(r)
+	})
+}
+
 func (probingController probingController) ChangeState(ctx *gin.Context) {
 	r := new(types.RequestProbeChangeState)
 	BindJson(ctx, r)
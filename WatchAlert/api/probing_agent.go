@@ -0,0 +1,77 @@
+package api
+
+import (
+	"watchAlert/internal/middleware"
+	"watchAlert/internal/services"
+	"watchAlert/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+type probingAgentController struct{}
+
+var ProbingAgentController = new(probingAgentController)
+
+/*
+分布式拨测节点 API
+/api/w8t/probing/agent
+节点接口通过 X-Agent-Token 请求头认证
+*/
+func (probingAgentController probingAgentController) API(gin *gin.RouterGroup) {
+	a := gin.Group("probing/agent")
+	a.Use(
+		middleware.Auth(),
+		middleware.Permission(),
+		middleware.ParseTenant(),
+	)
+	{
+		a.GET("listProbingAgents", probingAgentController.List)
+	}
+
+	b := gin.Group("probing/agent")
+	{
+		b.POST("register", probingAgentController.Register)
+		b.GET("rules", probingAgentController.Rules)
+		b.POST("results", probingAgentController.PushResults)
+	}
+}
+
+func (probingAgentController probingAgentController) List(ctx *gin.Context) {
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.ProbingAgentService.List(nil)
+	})
+}
+
+func (probingAgentController probingAgentController) Register(ctx *gin.Context) {
+	r := new(types.RequestProbingAgentRegister)
+	BindJson(ctx, r)
+
+	r.Token = ctx.GetHeader("X-Agent-Token")
+	r.Address = ctx.ClientIP()
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.ProbingAgentService.Register(r)
+	})
+}
+
+func (probingAgentController probingAgentController) Rules(ctx *gin.Context) {
+	r := new(types.RequestProbingAgentRules)
+	BindQuery(ctx, r)
+
+	r.Token = ctx.GetHeader("X-Agent-Token")
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.ProbingAgentService.Rules(r)
+	})
+}
+
+func (probingAgentController probingAgentController) PushResults(ctx *gin.Context) {
+	r := new(types.RequestProbingAgentResults)
+	BindJson(ctx, r)
+
+	r.Token = ctx.GetHeader("X-Agent-Token")
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.ProbingAgentService.PushResults(r)
+	})
+}
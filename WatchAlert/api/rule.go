@@ -40,6 +40,7 @@ func (ruleController ruleController) API(gin *gin.RouterGroup) {
 	{
 		b.GET("ruleList", ruleController.List)
 		b.GET("ruleSearch", ruleController.Search)
+		b.POST("ruleBacktest", ruleController.Backtest)
 	}
 	c := gin.Group("rule")
 	c.Use(
@@ -147,3 +148,15 @@ func (ruleController ruleController) Import(ctx *gin.Context) {
 		return services.RuleService.Import(r)
 	})
 }
+
+func (ruleController ruleController) Backtest(ctx *gin.Context) {
+	r := new(types.RequestRuleBacktest)
+	BindJson(ctx, r)
+
+	tid, _ := ctx.Get("TenantID")
+	r.TenantId = tid.(string)
+
+	Service(ctx, func() (interface{}, interface{}) {
+		return services.RuleService.Backtest(r)
+	})
+}
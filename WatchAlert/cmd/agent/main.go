@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/provider"
+)
+
+/*
+分布式拨测节点
+部署在不同的位置，向服务端注册后定期拉取所在位置的拨测规则，执行后上报结果
+	agent -server http://w8t:9001 -token xxx -location beijing
+*/
+
+var Version string
+
+const (
+	agentAPIPrefix    = "/api/w8t/probing/agent"
+	heartbeatInterval = 30 * time.Second
+	syncRulesInterval = 60 * time.Second
+)
+
+type agent struct {
+	server   string
+	token    string
+	id       string
+	location string
+	client   *http.Client
+
+	mux   sync.Mutex
+	tasks map[string]agentTask
+}
+
+type agentTask struct {
+	// 规则变更后需要重启任务
+	updateAt int64
+	cancel   context.CancelFunc
+}
+
+func main() {
+	hostname, _ := os.Hostname()
+
+	a := &agent{
+		client: &http.Client{Timeout: 30 * time.Second},
+		tasks:  make(map[string]agentTask),
+	}
+	flag.StringVar(&a.server, "server", os.Getenv("W8T_SERVER"), "服务端地址，如 http://127.0.0.1:9001")
+	flag.StringVar(&a.token, "token", os.Getenv("W8T_AGENT_TOKEN"), "节点认证 Token")
+	flag.StringVar(&a.location, "location", os.Getenv("W8T_AGENT_LOCATION"), "节点位置标签")
+	flag.StringVar(&a.id, "id", os.Getenv("W8T_AGENT_ID"), "节点ID，默认为 location-hostname")
+	flag.Parse()
+
+	if a.server == "" || a.token == "" || a.location == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+	a.server = strings.TrimRight(a.server, "/")
+	if a.id == "" {
+		a.id = fmt.Sprintf("%s-%s", a.location, hostname)
+	}
+
+	a.run(hostname)
+}
+
+func (a *agent) run(hostname string) {
+	register := func() {
+		err := a.call(http.MethodPost, "/register", types.RequestProbingAgentRegister{
+			AgentId:  a.id,
+			Location: a.location,
+			Hostname: hostname,
+			Version:  Version,
+		}, nil)
+		if err != nil {
+			log.Printf("节点注册失败: %s", err.Error())
+		}
+	}
+	register()
+	a.syncRules()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	syncTicker := time.NewTicker(syncRulesInterval)
+	defer heartbeat.Stop()
+	defer syncTicker.Stop()
+
+	for {
+		select {
+		case <-heartbeat.C:
+			register()
+		case <-syncTicker.C:
+			a.syncRules()
+		}
+	}
+}
+
+// syncRules 拉取所在位置的规则，启动新增及变更的规则任务，停止已移除的规则任务
+func (a *agent) syncRules() {
+	var rules []models.ProbingRule
+	query := url.Values{}
+	query.Set("agentId", a.id)
+	query.Set("location", a.location)
+	path := "/rules?" + query.Encode()
+	if err := a.call(http.MethodGet, path, nil, &rules); err != nil {
+		log.Printf("拉取拨测规则失败: %s", err.Error())
+		return
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	current := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		current[rule.RuleId] = struct{}{}
+		if task, ok := a.tasks[rule.RuleId]; ok {
+			if task.updateAt == rule.UpdateAt {
+				continue
+			}
+			task.cancel()
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		a.tasks[rule.RuleId] = agentTask{updateAt: rule.UpdateAt, cancel: cancel}
+		go a.probe(ctx, rule)
+	}
+
+	for ruleId, task := range a.tasks {
+		if _, ok := current[ruleId]; !ok {
+			task.cancel()
+			delete(a.tasks, ruleId)
+		}
+	}
+}
+
+// probe 按规则的执行频率拨测并上报结果
+func (a *agent) probe(ctx context.Context, rule models.ProbingRule) {
+	interval := rule.ProbingEndpointConfig.Strategy.EvalInterval
+	if interval <= 0 {
+		interval = 10
+	}
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		result := types.ProbingAgentResult{
+			TenantId:  rule.TenantId,
+			RuleId:    rule.RuleId,
+			Timestamp: time.Now().Unix(),
+		}
+		endpoint, err := provider.NewEndpointProvider(rule.RuleType)
+		if err == nil {
+			result.Value, err = endpoint.Pilot(provider.NewEndpointOption(rule.ProbingEndpointConfig))
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+
+		err = a.call(http.MethodPost, "/results", types.RequestProbingAgentResults{
+			AgentId:  a.id,
+			Location: a.location,
+			Results:  []types.ProbingAgentResult{result},
+		}, nil)
+		if err != nil {
+			log.Printf("上报拨测结果失败, rule: %s, err: %s", rule.RuleId, err.Error())
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// call 请求服务端接口，out 不为空时解析响应中的 data
+func (a *agent) call(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, a.server+agentAPIPrefix+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Agent-Token", a.token)
+
+	res, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var resp struct {
+		Code int             `json:"code"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return fmt.Errorf("status %d: %s", res.StatusCode, err.Error())
+	}
+	if resp.Code != http.StatusOK {
+		return fmt.Errorf("status %d: %s", resp.Code, string(resp.Data))
+	}
+	if out != nil {
+		return json.Unmarshal(resp.Data, out)
+	}
+	return nil
+}
@@ -13,6 +13,13 @@ type App struct {
 	Redis  Redis  `json:"Redis"`
 	Jwt    Jwt    `json:"Jwt"`
 	Jaeger Jaeger `json:"Jaeger"`
+	Notice Notice `json:"Notice"`
+	// 数据源健康检查
+	DatasourceHealth DatasourceHealth `json:"DatasourceHealth"`
+	// 分布式拨测节点
+	ProbingAgent ProbingAgent `json:"ProbingAgent"`
+	// 拨测历史保留策略
+	ProbingHistory ProbingHistory `json:"ProbingHistory"`
 }
 
 type Server struct {
@@ -46,6 +53,129 @@ type Jaeger struct {
 	URL string `json:"url"`
 }
 
+type Notice struct {
+	Retry NoticeRetry `json:"retry"`
+}
+
+// NoticeRetry 通知发送失败重试配置
+type NoticeRetry struct {
+	// 关闭重试，失败的通知不再进入重试队列
+	Disabled bool `json:"disabled"`
+	// 默认重试策略
+	Default RetryPolicy `json:"default"`
+	// 按通知类型覆盖重试策略，key 为 NoticeType，如 FeiShu、Email
+	Channels map[string]RetryPolicy `json:"channels"`
+}
+
+// RetryPolicy 指数退避重试策略
+type RetryPolicy struct {
+	// 最大发送次数（包含首次发送）
+	MaxAttempts int `json:"maxAttempts"`
+	// 首次重试间隔，单位（秒）
+	InitialInterval int64 `json:"initialInterval"`
+	// 最大重试间隔，单位（秒）
+	MaxInterval int64 `json:"maxInterval"`
+	// 退避倍数
+	Multiplier float64 `json:"multiplier"`
+}
+
+// GetRetryPolicy 获取通知类型对应的重试策略，未配置的字段使用默认值
+func (n NoticeRetry) GetRetryPolicy(noticeType string) RetryPolicy {
+	policy := n.Default
+	if p, ok := n.Channels[noticeType]; ok {
+		if p.MaxAttempts > 0 {
+			policy.MaxAttempts = p.MaxAttempts
+		}
+		if p.InitialInterval > 0 {
+			policy.InitialInterval = p.InitialInterval
+		}
+		if p.MaxInterval > 0 {
+			policy.MaxInterval = p.MaxInterval
+		}
+		if p.Multiplier > 0 {
+			policy.Multiplier = p.Multiplier
+		}
+	}
+
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 5
+	}
+	if policy.InitialInterval <= 0 {
+		policy.InitialInterval = 10
+	}
+	if policy.MaxInterval <= 0 {
+		policy.MaxInterval = 600
+	}
+	if policy.Multiplier < 1 {
+		policy.Multiplier = 2
+	}
+
+	return policy
+}
+
+// DatasourceHealth 数据源健康检查配置，持续异常的数据源会在元故障中心产生事件
+type DatasourceHealth struct {
+	// 关闭后台健康检查
+	Disabled bool `json:"disabled"`
+	// 检查间隔，单位（秒），默认 30
+	Interval int64 `json:"interval"`
+	// 连续失败多少次后产生事件，默认 3
+	Threshold int64 `json:"threshold"`
+	// 元故障中心，key 为租户ID，value 为故障中心ID；未配置的租户只记录状态不产生事件
+	FaultCenters map[string]string `json:"faultCenters"`
+}
+
+func (d DatasourceHealth) GetInterval() int64 {
+	if d.Interval <= 0 {
+		return 30
+	}
+	return d.Interval
+}
+
+func (d DatasourceHealth) GetThreshold() int64 {
+	if d.Threshold <= 0 {
+		return 3
+	}
+	return d.Threshold
+}
+
+// ProbingAgent 分布式拨测节点配置，节点通过 Token 注册、拉取规则及上报结果
+type ProbingAgent struct {
+	// 节点认证 Token，为空时不允许节点接入
+	Token string `json:"token"`
+	// 节点结果过期时间，单位（秒），超过后不参与评估，默认 300
+	ResultTTL int64 `json:"resultTTL"`
+}
+
+func (p ProbingAgent) GetResultTTL() int64 {
+	if p.ResultTTL <= 0 {
+		return 300
+	}
+	return p.ResultTTL
+}
+
+// ProbingHistory 拨测历史保留策略，原始记录过期后按小时聚合，聚合数据用于长周期可用性统计
+type ProbingHistory struct {
+	// 原始记录保留天数，默认 7
+	RawRetentionDays int64 `json:"rawRetentionDays"`
+	// 小时聚合数据保留天数，默认 400
+	RollupRetentionDays int64 `json:"rollupRetentionDays"`
+}
+
+func (p ProbingHistory) GetRawRetentionDays() int64 {
+	if p.RawRetentionDays <= 0 {
+		return 7
+	}
+	return p.RawRetentionDays
+}
+
+func (p ProbingHistory) GetRollupRetentionDays() int64 {
+	if p.RollupRetentionDays <= 0 {
+		return 400
+	}
+	return p.RollupRetentionDays
+}
+
 var (
 	configFile = "config/config.yaml"
 )
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 	"watchAlert/alert"
+	"watchAlert/alert/probing"
 	"watchAlert/config"
 	"watchAlert/internal/cache"
 	"watchAlert/internal/ctx"
@@ -51,6 +53,9 @@ func InitBasic() {
 	// 定时任务，清理历史通知记录和历史拨测数据
 	go gcHistoryData(ctx)
 
+	// 定时任务，每月 1 日发送拨测可用性月报
+	go sendProbingReports(ctx)
+
 	// 加载静默规则
 	go pushMuteRuleToRedis()
 
@@ -104,14 +109,17 @@ func importClientPools(ctx *ctx.Context) {
 func gcHistoryData(ctx *ctx.Context) {
 	// gc probe history data and notice history record
 	tools.NewCronjob("00 00 */1 * *", func() {
-		err := ctx.DB.Probing().DeleteRecord()
-		if err != nil {
-			logc.Errorf(ctx.Ctx, "fail to delete probe history data, %s", err.Error())
-		} else {
-			logc.Info(ctx.Ctx, "success delete probe history data")
+		// 拨测历史聚合仅由 Leader 执行，避免重复生成聚合数据
+		if alert.IsLeader() {
+			err := probing.DownsampleHistory(ctx)
+			if err != nil {
+				logc.Errorf(ctx.Ctx, "fail to downsample probe history data, %s", err.Error())
+			} else {
+				logc.Info(ctx.Ctx, "success downsample probe history data")
+			}
 		}
 
-		err = ctx.DB.Notice().DeleteRecord()
+		err := ctx.DB.Notice().DeleteRecord()
 		if err != nil {
 			logc.Errorf(ctx.Ctx, "fail to delete notice history record, %s", err.Error())
 		} else {
@@ -121,6 +129,15 @@ func gcHistoryData(ctx *ctx.Context) {
 
 }
 
+func sendProbingReports(ctx *ctx.Context) {
+	tools.NewCronjob("00 09 1 * *", func() {
+		if !alert.IsLeader() {
+			return
+		}
+		probing.SendMonthlyReports(ctx, time.Now())
+	})
+}
+
 func pushMuteRuleToRedis() {
 	list, _, err := ctx.DB.Silence().List("", "", "", models.Page{
 		Index: 0,
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"github.com/bytedance/sonic"
+	"github.com/go-redis/redis"
+	"sync"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+type (
+	// AlertGroupCache 用于管理标签分组的通知状态
+	AlertGroupCache struct {
+		rc    *redis.Client
+		mutex sync.RWMutex
+	}
+
+	// AlertGroupCacheInterface 定义了分组通知状态缓存的操作接口
+	AlertGroupCacheInterface interface {
+		Set(tenantId, faultCenterId, groupKey string, state AlertGroupState)
+		Get(tenantId, faultCenterId, groupKey string) (AlertGroupState, bool)
+		Delete(tenantId, faultCenterId, groupKey string)
+		List(tenantId, faultCenterId string) map[string]AlertGroupState
+	}
+
+	// AlertGroupState 分组通知状态
+	AlertGroupState struct {
+		FirstTime    int64    `json:"firstTime"`    // 分组创建时间
+		LastSendTime int64    `json:"lastSendTime"` // 最近一次通知时间
+		Fingerprints []string `json:"fingerprints"` // 最近一次通知时的事件指纹
+	}
+)
+
+// newAlertGroupCacheInterface 创建一个新的 AlertGroupCache 实例
+func newAlertGroupCacheInterface(r *redis.Client) AlertGroupCacheInterface {
+	return &AlertGroupCache{
+		rc: r,
+	}
+}
+
+func (a *AlertGroupCache) Set(tenantId, faultCenterId, groupKey string, state AlertGroupState) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.rc.HSet(string(models.BuildAlertGroupCacheKey(tenantId, faultCenterId)), groupKey, tools.JsonMarshalToString(state))
+}
+
+func (a *AlertGroupCache) Get(tenantId, faultCenterId, groupKey string) (AlertGroupState, bool) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	var state AlertGroupState
+	result, err := a.rc.HGet(string(models.BuildAlertGroupCacheKey(tenantId, faultCenterId)), groupKey).Result()
+	if err != nil {
+		return state, false
+	}
+
+	if err := sonic.Unmarshal([]byte(result), &state); err != nil {
+		return state, false
+	}
+
+	return state, true
+}
+
+func (a *AlertGroupCache) Delete(tenantId, faultCenterId, groupKey string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.rc.HDel(string(models.BuildAlertGroupCacheKey(tenantId, faultCenterId)), groupKey)
+}
+
+func (a *AlertGroupCache) List(tenantId, faultCenterId string) map[string]AlertGroupState {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	result, err := a.rc.HGetAll(string(models.BuildAlertGroupCacheKey(tenantId, faultCenterId))).Result()
+	if err != nil {
+		return map[string]AlertGroupState{}
+	}
+
+	var newMap = make(map[string]AlertGroupState)
+	for k, v := range result {
+		var state AlertGroupState
+		if err := sonic.Unmarshal([]byte(v), &state); err != nil {
+			continue
+		}
+		newMap[k] = state
+	}
+
+	return newMap
+}
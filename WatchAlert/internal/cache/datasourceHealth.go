@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"fmt"
+	"github.com/bytedance/sonic"
+	"github.com/go-redis/redis"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+type (
+	// DatasourceHealthCache 用于管理数据源健康状态，按租户存储在 Hash 中，field 为数据源ID
+	DatasourceHealthCache struct {
+		rc *redis.Client
+	}
+
+	// DatasourceHealthCacheInterface 定义了数据源健康状态的操作接口
+	DatasourceHealthCacheInterface interface {
+		Set(health models.DatasourceHealth)
+		Get(tenantId, datasourceId string) (models.DatasourceHealth, bool)
+		List(tenantId string) []models.DatasourceHealth
+		Delete(tenantId, datasourceId string)
+	}
+)
+
+// newDatasourceHealthCacheInterface 创建一个新的 DatasourceHealthCache 实例
+func newDatasourceHealthCacheInterface(r *redis.Client) DatasourceHealthCacheInterface {
+	return &DatasourceHealthCache{
+		rc: r,
+	}
+}
+
+func buildDatasourceHealthKey(tenantId string) string {
+	return fmt.Sprintf("w8t:%s:datasourceHealth", tenantId)
+}
+
+func (d *DatasourceHealthCache) Set(health models.DatasourceHealth) {
+	d.rc.HSet(buildDatasourceHealthKey(health.TenantId), health.DatasourceId, tools.JsonMarshalToString(health))
+}
+
+func (d *DatasourceHealthCache) Get(tenantId, datasourceId string) (models.DatasourceHealth, bool) {
+	var health models.DatasourceHealth
+	data, err := d.rc.HGet(buildDatasourceHealthKey(tenantId), datasourceId).Result()
+	if err != nil {
+		return health, false
+	}
+
+	if err := sonic.Unmarshal([]byte(data), &health); err != nil {
+		return health, false
+	}
+
+	return health, true
+}
+
+func (d *DatasourceHealthCache) List(tenantId string) []models.DatasourceHealth {
+	result, err := d.rc.HGetAll(buildDatasourceHealthKey(tenantId)).Result()
+	if err != nil {
+		return nil
+	}
+
+	healths := make([]models.DatasourceHealth, 0, len(result))
+	for _, data := range result {
+		var health models.DatasourceHealth
+		if err := sonic.Unmarshal([]byte(data), &health); err != nil {
+			continue
+		}
+		healths = append(healths, health)
+	}
+
+	return healths
+}
+
+func (d *DatasourceHealthCache) Delete(tenantId, datasourceId string) {
+	d.rc.HDel(buildDatasourceHealthKey(tenantId), datasourceId)
+}
@@ -19,6 +19,12 @@ type (
 		ProviderPools() *ProviderPoolStore
 		FaultCenter() FaultCenterCacheInterface
 		PendingRecover() PendingRecoverCacheInterface
+		AlertGroup() AlertGroupCacheInterface
+		NoticeRetry() NoticeRetryCacheInterface
+		NoticeLimit() NoticeLimitCacheInterface
+		DatasourceHealth() DatasourceHealthCacheInterface
+		Flap() FlapCacheInterface
+		ProbingAgent() ProbingAgentCacheInterface
 	}
 )
 
@@ -43,3 +49,21 @@ func (e entryCache) FaultCenter() FaultCenterCacheInterface {
 func (e entryCache) PendingRecover() PendingRecoverCacheInterface {
 	return newPendingRecoverCacheInterface(e.redis)
 }
+func (e entryCache) AlertGroup() AlertGroupCacheInterface {
+	return newAlertGroupCacheInterface(e.redis)
+}
+func (e entryCache) NoticeRetry() NoticeRetryCacheInterface {
+	return newNoticeRetryCacheInterface(e.redis)
+}
+func (e entryCache) NoticeLimit() NoticeLimitCacheInterface {
+	return newNoticeLimitCacheInterface(e.redis)
+}
+func (e entryCache) DatasourceHealth() DatasourceHealthCacheInterface {
+	return newDatasourceHealthCacheInterface(e.redis)
+}
+func (e entryCache) Flap() FlapCacheInterface {
+	return newFlapCacheInterface(e.redis)
+}
+func (e entryCache) ProbingAgent() ProbingAgentCacheInterface {
+	return newProbingAgentCacheInterface(e.redis)
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"fmt"
+	"github.com/bytedance/sonic"
+	"github.com/go-redis/redis"
+	"strconv"
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+type (
+	// FlapCache 用于记录事件的状态切换及抖动状态
+	FlapCache struct {
+		rc *redis.Client
+	}
+
+	// FlapCacheInterface 定义了抖动检测缓存的操作接口
+	FlapCacheInterface interface {
+		AddTransition(tenantId, faultCenterId, fingerprint string, window int64) int64
+		GetFlapping(tenantId, faultCenterId, fingerprint string) (models.FlapState, bool)
+		SetFlapping(state models.FlapState)
+		ListFlapping(tenantId, faultCenterId string) []models.FlapState
+		DeleteFlapping(tenantId, faultCenterId, fingerprint string)
+	}
+)
+
+// newFlapCacheInterface 创建一个新的 FlapCache 实例
+func newFlapCacheInterface(r *redis.Client) FlapCacheInterface {
+	return &FlapCache{
+		rc: r,
+	}
+}
+
+func buildFlapTransitionKey(tenantId, faultCenterId, fingerprint string) string {
+	return fmt.Sprintf("w8t:%s:%s:%s.flap:%s", tenantId, models.FaultCenterPrefix, faultCenterId, fingerprint)
+}
+
+func buildFlappingKey(tenantId, faultCenterId string) string {
+	return fmt.Sprintf("w8t:%s:%s:%s.flapping", tenantId, models.FaultCenterPrefix, faultCenterId)
+}
+
+// AddTransition 记录一次状态切换，返回窗口内的切换次数
+func (f *FlapCache) AddTransition(tenantId, faultCenterId, fingerprint string, window int64) int64 {
+	key := buildFlapTransitionKey(tenantId, faultCenterId, fingerprint)
+	now := time.Now()
+
+	pipe := f.rc.TxPipeline()
+	pipe.ZAdd(key, redis.Z{Score: float64(now.Unix()), Member: now.UnixNano()})
+	pipe.ZRemRangeByScore(key, "-inf", strconv.FormatInt(now.Unix()-window, 10))
+	count := pipe.ZCard(key)
+	pipe.Expire(key, time.Duration(window)*time.Second)
+	if _, err := pipe.Exec(); err != nil {
+		return 0
+	}
+
+	return count.Val()
+}
+
+func (f *FlapCache) GetFlapping(tenantId, faultCenterId, fingerprint string) (models.FlapState, bool) {
+	var state models.FlapState
+	data, err := f.rc.HGet(buildFlappingKey(tenantId, faultCenterId), fingerprint).Result()
+	if err != nil {
+		return state, false
+	}
+
+	if err := sonic.Unmarshal([]byte(data), &state); err != nil {
+		return state, false
+	}
+
+	return state, true
+}
+
+func (f *FlapCache) SetFlapping(state models.FlapState) {
+	f.rc.HSet(buildFlappingKey(state.TenantId, state.FaultCenterId), state.Fingerprint, tools.JsonMarshalToString(state))
+}
+
+func (f *FlapCache) ListFlapping(tenantId, faultCenterId string) []models.FlapState {
+	result, err := f.rc.HGetAll(buildFlappingKey(tenantId, faultCenterId)).Result()
+	if err != nil {
+		return nil
+	}
+
+	states := make([]models.FlapState, 0, len(result))
+	for _, data := range result {
+		var state models.FlapState
+		if err := sonic.Unmarshal([]byte(data), &state); err != nil {
+			continue
+		}
+		states = append(states, state)
+	}
+
+	return states
+}
+
+func (f *FlapCache) DeleteFlapping(tenantId, faultCenterId, fingerprint string) {
+	f.rc.HDel(buildFlappingKey(tenantId, faultCenterId), fingerprint)
+}
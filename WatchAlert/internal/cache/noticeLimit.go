@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"fmt"
+	"github.com/go-redis/redis"
+	"strings"
+	"time"
+	"watchAlert/pkg/tools"
+)
+
+// tokenBucketScript 令牌桶限流，多节点共享同一个桶
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1]) or burst
+local ts = tonumber(data[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HMSET', KEYS[1], 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 60000)
+return allowed
+`)
+
+type (
+	// NoticeLimitCache 用于管理通知限流及风暴模式下被抑制的消息统计
+	NoticeLimitCache struct {
+		rc *redis.Client
+	}
+
+	// NoticeLimitCacheInterface 定义了通知限流缓存的操作接口
+	NoticeLimitCacheInterface interface {
+		Allow(tenantId, noticeId string, perMinute, burst int64) bool
+		AddSuppressed(tenantId, noticeId, severity, ruleName string)
+		ListStorms() []NoticeStorm
+		PopSuppressed(tenantId, noticeId string) map[string]int64
+	}
+
+	// NoticeStorm 处于风暴模式的通知对象
+	NoticeStorm struct {
+		TenantId string
+		NoticeId string
+		Since    int64 // 风暴开始时间
+	}
+)
+
+const noticeStormIndexKey = "w8t:noticeStorm:index"
+
+// newNoticeLimitCacheInterface 创建一个新的 NoticeLimitCache 实例
+func newNoticeLimitCacheInterface(r *redis.Client) NoticeLimitCacheInterface {
+	return &NoticeLimitCache{
+		rc: r,
+	}
+}
+
+// Allow 获取发送令牌
+func (n *NoticeLimitCache) Allow(tenantId, noticeId string, perMinute, burst int64) bool {
+	key := fmt.Sprintf("w8t:%s:noticeLimit:%s", tenantId, noticeId)
+	rate := float64(perMinute) / 60
+	allowed, err := tokenBucketScript.Run(n.rc, []string{key}, rate, burst, time.Now().UnixMilli()).Int64()
+	if err != nil {
+		// Redis 异常时不影响告警发送
+		return true
+	}
+
+	return allowed == 1
+}
+
+// AddSuppressed 记录被抑制的消息，field 为 等级|规则名称
+func (n *NoticeLimitCache) AddSuppressed(tenantId, noticeId, severity, ruleName string) {
+	n.rc.HIncrBy(buildNoticeStormKey(tenantId, noticeId), severity+"|"+ruleName, 1)
+	n.rc.ZAddNX(noticeStormIndexKey, redis.Z{Score: float64(time.Now().Unix()), Member: tenantId + "/" + noticeId})
+}
+
+// ListStorms 获取全部处于风暴模式的通知对象
+func (n *NoticeLimitCache) ListStorms() []NoticeStorm {
+	result, err := n.rc.ZRangeWithScores(noticeStormIndexKey, 0, -1).Result()
+	if err != nil {
+		return nil
+	}
+
+	var storms []NoticeStorm
+	for _, z := range result {
+		member, _ := z.Member.(string)
+		tenantId, noticeId, ok := strings.Cut(member, "/")
+		if !ok {
+			continue
+		}
+		storms = append(storms, NoticeStorm{TenantId: tenantId, NoticeId: noticeId, Since: int64(z.Score)})
+	}
+
+	return storms
+}
+
+// PopSuppressed 取出并清空被抑制的消息统计，结束本轮风暴
+func (n *NoticeLimitCache) PopSuppressed(tenantId, noticeId string) map[string]int64 {
+	key := buildNoticeStormKey(tenantId, noticeId)
+	pipe := n.rc.TxPipeline()
+	getCmd := pipe.HGetAll(key)
+	pipe.Del(key)
+	pipe.ZRem(noticeStormIndexKey, tenantId+"/"+noticeId)
+	if _, err := pipe.Exec(); err != nil {
+		return map[string]int64{}
+	}
+
+	var newMap = make(map[string]int64)
+	for k, v := range getCmd.Val() {
+		newMap[k] = tools.ConvertStringToInt64(v)
+	}
+
+	return newMap
+}
+
+func buildNoticeStormKey(tenantId, noticeId string) string {
+	return fmt.Sprintf("w8t:%s:noticeStorm:%s.suppressed", tenantId, noticeId)
+}
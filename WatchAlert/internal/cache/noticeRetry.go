@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"github.com/bytedance/sonic"
+	"github.com/go-redis/redis"
+	"sort"
+	"strconv"
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+type (
+	// NoticeRetryCache 用于管理通知重试队列与死信列表
+	NoticeRetryCache struct {
+		rc *redis.Client
+	}
+
+	// NoticeRetryCacheInterface 定义了通知重试缓存的操作接口
+	NoticeRetryCacheInterface interface {
+		Enqueue(task models.NoticeRetryTask) bool
+		Reschedule(task models.NoticeRetryTask)
+		GetTask(id string) (models.NoticeRetryTask, bool)
+		RemoveTask(id string)
+		DueTasks(now int64, limit int64) []string
+		Lock(id string, ttl time.Duration) bool
+		Unlock(id string)
+		PushDeadLetter(task models.NoticeRetryTask)
+		GetDeadLetter(tenantId, id string) (models.NoticeRetryTask, bool)
+		ListDeadLetters(tenantId string) []models.NoticeRetryTask
+		RemoveDeadLetter(tenantId, id string)
+	}
+)
+
+// newNoticeRetryCacheInterface 创建一个新的 NoticeRetryCache 实例
+func newNoticeRetryCacheInterface(r *redis.Client) NoticeRetryCacheInterface {
+	return &NoticeRetryCache{
+		rc: r,
+	}
+}
+
+// Enqueue 添加重试任务，同一任务已存在时不重复添加
+func (n *NoticeRetryCache) Enqueue(task models.NoticeRetryTask) bool {
+	ok, err := n.rc.HSetNX(string(models.NoticeRetryTasksKey), task.ID, tools.JsonMarshalToString(task)).Result()
+	if err != nil || !ok {
+		return false
+	}
+
+	n.rc.ZAdd(string(models.NoticeRetryQueueKey), redis.Z{Score: float64(task.NextRetryAt), Member: task.ID})
+	return true
+}
+
+// Reschedule 更新任务并重新加入队列
+func (n *NoticeRetryCache) Reschedule(task models.NoticeRetryTask) {
+	n.rc.HSet(string(models.NoticeRetryTasksKey), task.ID, tools.JsonMarshalToString(task))
+	n.rc.ZAdd(string(models.NoticeRetryQueueKey), redis.Z{Score: float64(task.NextRetryAt), Member: task.ID})
+}
+
+func (n *NoticeRetryCache) GetTask(id string) (models.NoticeRetryTask, bool) {
+	return unmarshalNoticeRetryTask(n.rc.HGet(string(models.NoticeRetryTasksKey), id).Result())
+}
+
+func (n *NoticeRetryCache) RemoveTask(id string) {
+	n.rc.ZRem(string(models.NoticeRetryQueueKey), id)
+	n.rc.HDel(string(models.NoticeRetryTasksKey), id)
+}
+
+// DueTasks 获取已到达重试时间的任务 ID
+func (n *NoticeRetryCache) DueTasks(now int64, limit int64) []string {
+	ids, err := n.rc.ZRangeByScore(string(models.NoticeRetryQueueKey), redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now, 10),
+		Count: limit,
+	}).Result()
+	if err != nil {
+		return nil
+	}
+
+	return ids
+}
+
+// Lock 抢占任务，避免多个节点同时发送同一通知
+func (n *NoticeRetryCache) Lock(id string, ttl time.Duration) bool {
+	ok, err := n.rc.SetNX(string(models.BuildNoticeRetryLockKey(id)), time.Now().Unix(), ttl).Result()
+	return err == nil && ok
+}
+
+func (n *NoticeRetryCache) Unlock(id string) {
+	n.rc.Del(string(models.BuildNoticeRetryLockKey(id)))
+}
+
+func (n *NoticeRetryCache) PushDeadLetter(task models.NoticeRetryTask) {
+	n.rc.HSet(string(models.BuildNoticeDeadLetterKey(task.TenantId)), task.ID, tools.JsonMarshalToString(task))
+}
+
+func (n *NoticeRetryCache) GetDeadLetter(tenantId, id string) (models.NoticeRetryTask, bool) {
+	return unmarshalNoticeRetryTask(n.rc.HGet(string(models.BuildNoticeDeadLetterKey(tenantId)), id).Result())
+}
+
+func (n *NoticeRetryCache) ListDeadLetters(tenantId string) []models.NoticeRetryTask {
+	result, err := n.rc.HGetAll(string(models.BuildNoticeDeadLetterKey(tenantId))).Result()
+	if err != nil {
+		return []models.NoticeRetryTask{}
+	}
+
+	list := make([]models.NoticeRetryTask, 0, len(result))
+	for _, v := range result {
+		task, ok := unmarshalNoticeRetryTask(v, nil)
+		if !ok {
+			continue
+		}
+		list = append(list, task)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].UpdateAt > list[j].UpdateAt
+	})
+
+	return list
+}
+
+func (n *NoticeRetryCache) RemoveDeadLetter(tenantId, id string) {
+	n.rc.HDel(string(models.BuildNoticeDeadLetterKey(tenantId)), id)
+}
+
+func unmarshalNoticeRetryTask(result string, err error) (models.NoticeRetryTask, bool) {
+	var task models.NoticeRetryTask
+	if err != nil {
+		return task, false
+	}
+
+	if err := sonic.Unmarshal([]byte(result), &task); err != nil {
+		return task, false
+	}
+
+	return task, true
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"fmt"
+	"github.com/bytedance/sonic"
+	"github.com/go-redis/redis"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+type (
+	// ProbingAgentCache 用于管理分布式拨测节点及各位置的最新拨测结果
+	ProbingAgentCache struct {
+		rc *redis.Client
+	}
+
+	// ProbingAgentCacheInterface 定义了拨测节点的操作接口
+	ProbingAgentCacheInterface interface {
+		SetAgent(agent models.ProbingAgent)
+		GetAgent(agentId string) (models.ProbingAgent, bool)
+		ListAgents() []models.ProbingAgent
+		SetLocationResult(tenantId, ruleId string, result models.ProbingLocationResult)
+		ListLocationResults(tenantId, ruleId string) map[string]models.ProbingLocationResult
+		DeleteLocationResults(tenantId, ruleId string)
+	}
+)
+
+// newProbingAgentCacheInterface 创建一个新的 ProbingAgentCache 实例
+func newProbingAgentCacheInterface(r *redis.Client) ProbingAgentCacheInterface {
+	return &ProbingAgentCache{
+		rc: r,
+	}
+}
+
+// 拨测节点不区分租户，field 为节点ID
+const probingAgentsKey = "w8t:probing:agents"
+
+// buildProbingLocationKey 规则各位置的最新结果，field 为位置标签
+func buildProbingLocationKey(tenantId, ruleId string) string {
+	return fmt.Sprintf("w8t:%s:probing:%s.locations", tenantId, ruleId)
+}
+
+func (p *ProbingAgentCache) SetAgent(agent models.ProbingAgent) {
+	p.rc.HSet(probingAgentsKey, agent.AgentId, tools.JsonMarshalToString(agent))
+}
+
+func (p *ProbingAgentCache) GetAgent(agentId string) (models.ProbingAgent, bool) {
+	var agent models.ProbingAgent
+	data, err := p.rc.HGet(probingAgentsKey, agentId).Result()
+	if err != nil {
+		return agent, false
+	}
+
+	if err := sonic.Unmarshal([]byte(data), &agent); err != nil {
+		return agent, false
+	}
+
+	return agent, true
+}
+
+func (p *ProbingAgentCache) ListAgents() []models.ProbingAgent {
+	result, err := p.rc.HGetAll(probingAgentsKey).Result()
+	if err != nil {
+		return nil
+	}
+
+	agents := make([]models.ProbingAgent, 0, len(result))
+	for _, data := range result {
+		var agent models.ProbingAgent
+		if err := sonic.Unmarshal([]byte(data), &agent); err != nil {
+			continue
+		}
+		agents = append(agents, agent)
+	}
+
+	return agents
+}
+
+func (p *ProbingAgentCache) SetLocationResult(tenantId, ruleId string, result models.ProbingLocationResult) {
+	p.rc.HSet(buildProbingLocationKey(tenantId, ruleId), result.Location, tools.JsonMarshalToString(result))
+}
+
+func (p *ProbingAgentCache) ListLocationResults(tenantId, ruleId string) map[string]models.ProbingLocationResult {
+	result, err := p.rc.HGetAll(buildProbingLocationKey(tenantId, ruleId)).Result()
+	if err != nil {
+		return nil
+	}
+
+	results := make(map[string]models.ProbingLocationResult, len(result))
+	for location, data := range result {
+		var r models.ProbingLocationResult
+		if err := sonic.Unmarshal([]byte(data), &r); err != nil {
+			continue
+		}
+		results[location] = r
+	}
+
+	return results
+}
+
+func (p *ProbingAgentCache) DeleteLocationResults(tenantId, ruleId string) {
+	p.rc.Del(buildProbingLocationKey(tenantId, ruleId))
+}
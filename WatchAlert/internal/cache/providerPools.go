@@ -2,6 +2,7 @@ package cache
 
 import (
 	"fmt"
+	"io"
 	"sync"
 )
 
@@ -18,12 +19,16 @@ func NewClientPoolStore() *ProviderPoolStore {
 	}
 }
 
-// SetClient 设置通用客户端
+// SetClient 设置通用客户端，替换的旧客户端持有连接池时一并关闭
 func (p *ProviderPoolStore) SetClient(key string, client interface{}) {
 	p.mux.Lock()
-	defer p.mux.Unlock()
-
+	old, exists := p.clients[key]
 	p.clients[key] = client
+	p.mux.Unlock()
+
+	if exists {
+		closeClient(old)
+	}
 }
 
 // GetClient 获取通用客户端
@@ -41,7 +46,18 @@ func (p *ProviderPoolStore) GetClient(key string) (interface{}, error) {
 // RemoveClient 移除通用客户端
 func (p *ProviderPoolStore) RemoveClient(key string) {
 	p.mux.Lock()
-	defer p.mux.Unlock()
-
+	old, exists := p.clients[key]
 	delete(p.clients, key)
+	p.mux.Unlock()
+
+	if exists {
+		closeClient(old)
+	}
+}
+
+// closeClient 关闭持有连接的客户端，如 SQL 数据源的 *sql.DB，进行中的查询完成后才会真正关闭
+func closeClient(client interface{}) {
+	if closer, ok := client.(io.Closer); ok {
+		_ = closer.Close()
+	}
 }
@@ -64,6 +64,14 @@ var (
 		[]string{"tenant_id"},
 	)
 
+	NoticeSuppressedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "watchalert_notice_suppressed_total",
+			Help: "Total number of notifications suppressed by notice rate limit",
+		},
+		[]string{"tenant_id", "notice_id", "severity"},
+	)
+
 	SLAComplianceRate = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "watchalert_sla_compliance_rate",
@@ -72,6 +80,57 @@ var (
 		[]string{"tenant_id", "priority"},
 	)
 
+	// 数据源健康指标
+	DatasourceUp = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "watchalert_datasource_up",
+			Help: "Whether the datasource health check succeeded (1) or failed (0)",
+		},
+		[]string{"tenant_id", "datasource_id", "datasource_type"},
+	)
+
+	DatasourceCheckDuration = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "watchalert_datasource_check_duration_seconds",
+			Help: "Duration of the last datasource health check in seconds",
+		},
+		[]string{"tenant_id", "datasource_id", "datasource_type"},
+	)
+
+	DatasourceBlindRules = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "watchalert_datasource_blind_rules",
+			Help: "Number of enabled rules that cannot be evaluated because the datasource is unhealthy",
+		},
+		[]string{"tenant_id", "datasource_id", "datasource_type"},
+	)
+
+	// 记录规则指标
+	RecordingRuleEvalDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "watchalert_recording_rule_eval_duration_seconds",
+			Help:    "Recording rule evaluation latency in seconds, including remote write",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"tenant_id", "rule_id"},
+	)
+
+	RecordingRuleEvalFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "watchalert_recording_rule_eval_failures_total",
+			Help: "Total number of recording rule evaluation failures by stage",
+		},
+		[]string{"tenant_id", "rule_id", "stage"},
+	)
+
+	RecordingRuleSamples = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "watchalert_recording_rule_samples",
+			Help: "Number of samples written by the last recording rule evaluation",
+		},
+		[]string{"tenant_id", "rule_id"},
+	)
+
 	// 性能指标
 	HTTPRequestTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -141,11 +200,43 @@ func SetAlertsActive(tenantId string, count int) {
 	AlertsActiveGauge.WithLabelValues(tenantId).Set(float64(count))
 }
 
+// 通知限流相关指标
+func IncNoticeSuppressed(tenantId, noticeId, severity string) {
+	NoticeSuppressedTotal.WithLabelValues(tenantId, noticeId, severity).Inc()
+}
+
 // SLA相关指标
 func SetSLAComplianceRate(tenantId, priority string, rate float64) {
 	SLAComplianceRate.WithLabelValues(tenantId, priority).Set(rate)
 }
 
+// 数据源健康相关指标
+func SetDatasourceHealth(tenantId, datasourceId, datasourceType string, up bool, duration float64, blindRules int) {
+	var v float64
+	if up {
+		v = 1
+	}
+	DatasourceUp.WithLabelValues(tenantId, datasourceId, datasourceType).Set(v)
+	DatasourceCheckDuration.WithLabelValues(tenantId, datasourceId, datasourceType).Set(duration)
+	DatasourceBlindRules.WithLabelValues(tenantId, datasourceId, datasourceType).Set(float64(blindRules))
+}
+
+func DeleteDatasourceHealth(tenantId, datasourceId, datasourceType string) {
+	DatasourceUp.DeleteLabelValues(tenantId, datasourceId, datasourceType)
+	DatasourceCheckDuration.DeleteLabelValues(tenantId, datasourceId, datasourceType)
+	DatasourceBlindRules.DeleteLabelValues(tenantId, datasourceId, datasourceType)
+}
+
+// 记录规则相关指标
+func RecordRecordingRuleEval(tenantId, ruleId string, duration float64, samples int) {
+	RecordingRuleEvalDuration.WithLabelValues(tenantId, ruleId).Observe(duration)
+	RecordingRuleSamples.WithLabelValues(tenantId, ruleId).Set(float64(samples))
+}
+
+func IncRecordingRuleEvalFailures(tenantId, ruleId, stage string) {
+	RecordingRuleEvalFailures.WithLabelValues(tenantId, ruleId, stage).Inc()
+}
+
 // HTTP请求相关指标
 func RecordHTTPRequest(method, path, status string, duration float64) {
 	HTTPRequestTotal.WithLabelValues(method, path, status).Inc()
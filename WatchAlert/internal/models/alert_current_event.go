@@ -16,6 +16,7 @@ const (
 	StatePendingRecovery AlertStatus = "pending_recovery" // 待恢复
 	StateRecovered       AlertStatus = "recovered"        // 已恢复
 	StateSilenced        AlertStatus = "silenced"         // 静默中
+	StateInhibited       AlertStatus = "inhibited"        // 抑制中
 )
 
 type AlertCurEvent struct {
@@ -47,7 +48,10 @@ type AlertCurEvent struct {
 	FaultCenterId          string                 `json:"faultCenterId"`
 	FaultCenter            FaultCenter            `json:"faultCenter" gorm:"-"`
 	ConfirmState           ConfirmState           `json:"confirmState" gorm:"-"`
-	Status                 AlertStatus            `json:"status" gorm:"-"` // 事件状态
+	Status                 AlertStatus            `json:"status" gorm:"-"`                  // 事件状态
+	GroupedEvents          []AlertCurEvent        `json:"groupedEvents,omitempty" gorm:"-"` // 标签分组聚合时同组的全部事件，仅用于通知模版
+	EscalationState        []EscalationLevelState `json:"escalationState" gorm:"-"`         // 各升级级别的通知状态
+	Flapping               bool                   `json:"flapping" gorm:"-"`                // 是否处于抖动状态
 }
 
 type ConfirmState struct {
@@ -91,10 +95,11 @@ func (alert *AlertCurEvent) validateTransition(newState AlertStatus) error {
 	// 定义允许的状态转换规则
 	allowedTransitions := map[AlertStatus][]AlertStatus{
 		StatePreAlert:        {StateAlerting, StateSilenced},
-		StateAlerting:        {StatePendingRecovery, StateSilenced},
+		StateAlerting:        {StatePendingRecovery, StateSilenced, StateInhibited},
 		StatePendingRecovery: {StateAlerting, StateRecovered},
 		StateRecovered:       {StatePreAlert},
 		StateSilenced:        {StatePreAlert, StateAlerting, StatePendingRecovery, StateRecovered},
+		StateInhibited:       {StateAlerting, StateSilenced, StatePendingRecovery, StateRecovered},
 	}
 
 	// 检查转换是否允许
@@ -135,6 +140,7 @@ func (alert *AlertCurEvent) handleStateTransition(newState AlertStatus) error {
 		alert.RecoverTime = now
 		alert.IsRecovered = true
 	case StateSilenced:
+	case StateInhibited:
 	}
 
 	return nil
@@ -156,6 +162,14 @@ func (alert *AlertCurEvent) IsArriveForDuration() bool {
 	return alert.LastEvalTime-alert.FirstTriggerTime > alert.ForDuration
 }
 
+// InheritEvalTime 继承缓存事件的首次触发时间并更新评估时间，外部推送的新事件保留上游的首次触发时间
+func (alert *AlertCurEvent) InheritEvalTime(cacheEvent AlertCurEvent) {
+	if cacheEvent.FirstTriggerTime != 0 || alert.FirstTriggerTime == 0 {
+		alert.FirstTriggerTime = cacheEvent.GetFirstTime()
+	}
+	alert.LastEvalTime = cacheEvent.GetLastEvalTime()
+}
+
 // GetLastSendTime 获取故障中心事件的最后发送时间
 func (alert *AlertCurEvent) GetLastSendTime() int64 {
 	return alert.LastSendTime
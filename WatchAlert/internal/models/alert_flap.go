@@ -0,0 +1,16 @@
+package models
+
+// FlapState 抖动中的事件，事件恢复后从故障中心移除，抖动状态单独存储直到稳定
+type FlapState struct {
+	TenantId       string                 `json:"tenantId"`
+	FaultCenterId  string                 `json:"faultCenterId"`
+	Fingerprint    string                 `json:"fingerprint"`
+	RuleId         string                 `json:"ruleId"`
+	RuleName       string                 `json:"ruleName"`
+	Severity       string                 `json:"severity"`
+	Labels         map[string]interface{} `json:"labels"`
+	Since          int64                  `json:"since"`          // 开始抖动时间
+	LastTransition int64                  `json:"lastTransition"` // 最近一次状态切换时间
+	Transitions    int64                  `json:"transitions"`    // 抖动期间的状态切换次数
+	Status         AlertStatus            `json:"status"`         // 最近一次切换后的状态
+}
@@ -11,6 +11,8 @@ type AlertDataSource struct {
 	DsAliCloudConfig DsAliCloudConfig       `json:"dsAliCloudConfig" gorm:"dsAliCloudConfig;serializer:json"`
 	AWSCloudWatch    AWSCloudWatch          `json:"awsCloudwatch" gorm:"awsCloudwatch;serializer:json"`
 	ClickHouseConfig DsClickHouseConfig     `json:"clickhouseConfig" gorm:"clickhouseConfig;serializer:json"`
+	InfluxDBConfig   DsInfluxDBConfig       `json:"influxdbConfig" gorm:"influxdbConfig;serializer:json"`
+	SQLConfig        DsSQLConfig            `json:"sqlConfig" gorm:"sqlConfig;serializer:json"`
 	Description      string                 `json:"description"`
 	KubeConfig       string                 `json:"kubeConfig"`
 	UpdateBy         string                 `json:"updateBy"`
@@ -33,6 +35,24 @@ type DsClickHouseConfig struct {
 	Timeout int64
 }
 
+// DsInfluxDBConfig InfluxDB 连接配置，1.x 使用 InfluxQL，2.x 使用 Flux
+type DsInfluxDBConfig struct {
+	Version         string `json:"version"`         // 1、2，默认 1
+	Database        string `json:"database"`        // 1.x 数据库
+	RetentionPolicy string `json:"retentionPolicy"` // 1.x 保留策略
+	Org             string `json:"org"`             // 2.x 组织
+	Token           string `json:"token"`           // 2.x API Token
+}
+
+// DsSQLConfig MySQL、PostgreSQL 连接配置
+type DsSQLConfig struct {
+	Addr     string `json:"addr"`     // host:port
+	Database string `json:"database"` // 数据库名称
+	Params   string `json:"params"`   // 额外连接参数，如 charset=utf8mb4&sslmode=disable
+	Timeout  int64  `json:"timeout"`  // 查询超时时间，单位（秒），默认 10
+	MaxRows  int64  `json:"maxRows"`  // 单次查询返回的最大行数，默认 1000
+}
+
 type DsAliCloudConfig struct {
 	AliCloudEndpoint string `json:"alicloudEndpoint"`
 	AliCloudAk       string `json:"alicloudAk"`
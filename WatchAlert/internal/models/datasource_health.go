@@ -0,0 +1,36 @@
+package models
+
+const (
+	DatasourceHealthy   = "healthy"
+	DatasourceUnhealthy = "unhealthy"
+
+	// AlertStateDatasourceUnhealthy 数据源持续异常事件的 alertstate 标签
+	AlertStateDatasourceUnhealthy = "DatasourceUnhealthy"
+)
+
+// DatasourceHealth 数据源健康状态，由后台健康检查任务定期更新
+type DatasourceHealth struct {
+	TenantId       string `json:"tenantId"`
+	DatasourceId   string `json:"datasourceId"`
+	DatasourceName string `json:"datasourceName"`
+	DatasourceType string `json:"datasourceType"`
+	Status         string `json:"status"`
+	// 最近一次检查耗时，单位（毫秒）
+	Latency   int64  `json:"latency"`
+	LastError string `json:"lastError"`
+	// 连续失败次数，检查成功后清零
+	Failures      int64 `json:"failures"`
+	LastCheckAt   int64 `json:"lastCheckAt"`
+	LastHealthyAt int64 `json:"lastHealthyAt"`
+	// 本次异常的开始时间
+	UnhealthySince int64 `json:"unhealthySince"`
+	// 因数据源异常而无法评估的规则
+	BlindRules []BlindRule `json:"blindRules"`
+	// 是否已在元故障中心产生事件
+	Alerted bool `json:"alerted"`
+}
+
+type BlindRule struct {
+	RuleId   string `json:"ruleId"`
+	RuleName string `json:"ruleName"`
+}
@@ -0,0 +1,132 @@
+package models
+
+import "fmt"
+
+const (
+	// EscalationTriggerNotAcknowledged 超时未认领
+	EscalationTriggerNotAcknowledged = "NotAcknowledged"
+	// EscalationTriggerNotResolved 超时未恢复
+	EscalationTriggerNotResolved = "NotResolved"
+
+	EscalationTargetNotice = "Notice" // 通知对象
+	EscalationTargetDuty   = "Duty"   // 值班表，通过通知对象的渠道通知当前值班人员
+	EscalationTargetUser   = "User"   // 指定用户，通过通知对象的渠道通知指定用户
+)
+
+// EscalationPolicy 告警升级策略，可被多个故障中心复用
+type EscalationPolicy struct {
+	TenantId    string            `json:"tenantId"`
+	ID          string            `json:"id" gorm:"primaryKey"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Levels      []EscalationLevel `json:"levels" gorm:"levels;serializer:json"`
+	UpdateAt    int64             `json:"updateAt"`
+	UpdateBy    string            `json:"updateBy"`
+}
+
+// EscalationLevel 升级级别，按顺序依次触发
+type EscalationLevel struct {
+	Delay          int64    `json:"delay"`          // 告警首次触发后的等待时间，单位（分钟）
+	Trigger        string   `json:"trigger"`        // 触发条件 NotAcknowledged / NotResolved
+	TargetType     string   `json:"targetType"`     // 通知目标类型 Notice / Duty / User
+	NoticeId       string   `json:"noticeId"`       // 通知对象ID，Duty、User 类型时作为发送渠道
+	DutyId         string   `json:"dutyId"`         // 值班表ID
+	UserIds        []string `json:"userIds"`        // 用户ID
+	Repeat         int64    `json:"repeat"`         // 重复通知次数，0 表示仅通知一次，-1 表示持续通知直到条件解除
+	RepeatInterval int64    `json:"repeatInterval"` // 重复通知间隔，单位（分钟）
+}
+
+// EscalationLevelState 事件在各升级级别的通知状态
+type EscalationLevelState struct {
+	Level        int   `json:"level"`
+	Times        int64 `json:"times"`        // 已通知次数
+	LastSendTime int64 `json:"lastSendTime"` // 上次通知时间
+}
+
+func (e *EscalationPolicy) TableName() string {
+	return "w8t_escalation_policy"
+}
+
+// GetTrigger 获取触发条件，默认超时未认领
+func (l EscalationLevel) GetTrigger() string {
+	if l.Trigger == "" {
+		return EscalationTriggerNotAcknowledged
+	}
+	return l.Trigger
+}
+
+// GetTargetType 获取通知目标类型，默认通知对象
+func (l EscalationLevel) GetTargetType() string {
+	if l.TargetType == "" {
+		return EscalationTargetNotice
+	}
+	return l.TargetType
+}
+
+// GetMaxTimes 获取最大通知次数，小于 0 时不限制
+func (l EscalationLevel) GetMaxTimes() int64 {
+	if l.Repeat < 0 {
+		return -1
+	}
+	return l.Repeat + 1
+}
+
+// EscalationContact 用户在通知渠道中的联系方式，邮件、电话、短信使用邮箱及手机号，
+// 飞书、钉钉、Slack、Discord、企业微信、Mattermost 使用用户绑定的 IM 用户ID，其余渠道按用户名提及
+func EscalationContact(noticeType string, user Member) string {
+	switch noticeType {
+	case "Email":
+		return user.Email
+	case "PhoneCall", "SMS":
+		return user.Phone
+	case "DingDing":
+		// 未绑定钉钉用户ID 时按手机号 @
+		if user.DutyUserId == "" {
+			return user.Phone
+		}
+		return user.DutyUserId
+	case "FeiShu", "Slack", "Discord", "WeChatWork":
+		return user.DutyUserId
+	case "Mattermost":
+		// 绑定的 IM 用户ID 为 Mattermost 账号用户名，未绑定时使用用户名
+		if user.DutyUserId == "" {
+			return user.UserName
+		}
+		return user.DutyUserId
+	default:
+		return user.UserName
+	}
+}
+
+// EscalationMention 按通知渠道的提及语法生成提及文本
+func EscalationMention(noticeType, contact string) string {
+	switch noticeType {
+	case "FeiShu":
+		return fmt.Sprintf("<at id=%s></at>", contact)
+	case "Slack", "Discord", "WeChatWork":
+		return fmt.Sprintf("<@%s>", contact)
+	default:
+		return "@" + contact
+	}
+}
+
+// GetEscalationState 获取事件在指定级别的通知状态
+func (alert *AlertCurEvent) GetEscalationState(level int) EscalationLevelState {
+	for _, state := range alert.EscalationState {
+		if state.Level == level {
+			return state
+		}
+	}
+	return EscalationLevelState{Level: level}
+}
+
+// SetEscalationState 更新事件在指定级别的通知状态
+func (alert *AlertCurEvent) SetEscalationState(state EscalationLevelState) {
+	for i := range alert.EscalationState {
+		if alert.EscalationState[i].Level == state.Level {
+			alert.EscalationState[i] = state
+			return
+		}
+	}
+	alert.EscalationState = append(alert.EscalationState, state)
+}
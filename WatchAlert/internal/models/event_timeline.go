@@ -0,0 +1,23 @@
+package models
+
+const (
+	// EventTimelineEscalation 告警升级
+	EventTimelineEscalation = "escalation"
+)
+
+// AlertEventTimeline 告警事件时间线
+type AlertEventTimeline struct {
+	ID            uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	TenantId      string `json:"tenantId" gorm:"index"`
+	FaultCenterId string `json:"faultCenterId"`
+	EventId       string `json:"eventId" gorm:"index"`
+	Fingerprint   string `json:"fingerprint" gorm:"index"`
+	Type          string `json:"type"`
+	Content       string `json:"content" gorm:"type:text"`
+	Operator      string `json:"operator"`
+	CreateAt      int64  `json:"createAt"`
+}
+
+func (t *AlertEventTimeline) TableName() string {
+	return "w8t_alert_event_timeline"
+}
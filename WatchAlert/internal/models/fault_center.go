@@ -9,6 +9,11 @@ import (
 const (
 	FaultCenterPrefix = "faultCenter"
 	ConfirmStatus     = 1
+
+	// AggregationTypeRule 按规则聚合
+	AggregationTypeRule = "Rule"
+	// AggregationTypeLabel 按标签分组聚合，参考 Alertmanager group_by
+	AggregationTypeLabel = "Label"
 )
 
 type FaultCenter struct {
@@ -27,11 +32,18 @@ type FaultCenter struct {
 	CurrentAlertNumber    int64           `json:"currentAlertNumber" gorm:"-"`
 	CurrentMuteNumber     int64           `json:"currentMuteNumber" gorm:"-"`
 	CurrentRecoverNumber  int64           `json:"currentRecoverNumber" gorm:"-"`
+	CurrentInhibitNumber  int64           `json:"currentInhibitNumber" gorm:"-"`
 	IsUpgradeEnabled      *bool           `json:"isUpgradeEnabled" gorm:"column:isUpgradeEnabled"`
 	UpgradableSeverity    []string        `json:"upgradableSeverity" gorm:"column:upgradableSeverity;serializer:json"`
 	UpgradeStrategy       UpgradeStrategy `json:"upgradeStrategy" gorm:"column:upgradeStrategy;serializer:json"`
+	EscalationPolicyId    string          `json:"escalationPolicyId" gorm:"column:escalationPolicyId"` // 升级策略ID，为空时使用 UpgradeStrategy
+	InhibitRules          []InhibitRule   `json:"inhibitRules" gorm:"column:inhibitRules;serializer:json"`
+	GroupStrategy         GroupStrategy   `json:"groupStrategy" gorm:"column:groupStrategy;serializer:json"`
+	FlapDetection         FlapDetection   `json:"flapDetection" gorm:"column:flapDetection;serializer:json"`
+	AlertmanagerToken     string          `json:"alertmanagerToken" gorm:"column:alertmanagerToken"` // Alertmanager 接入 Token，为空时拒绝推送
 }
 
+// UpgradeStrategy 单级升级策略，未配置 EscalationPolicyId 时兼容使用
 type UpgradeStrategy struct {
 	Enabled        *bool  `json:"enabled"`        // 是否启用告警升级
 	Timeout        int64  `json:"timeout"`        // 超时时间
@@ -45,6 +57,59 @@ type NoticeRoute struct {
 	NoticeIds []string `json:"noticeIds" gorm:"column:noticeIds;serializer:json"`
 }
 
+// GroupStrategy 标签分组策略，聚合类型为 Label 时生效
+type GroupStrategy struct {
+	GroupBy        []string `json:"groupBy"`        // 分组标签
+	GroupWait      int64    `json:"groupWait"`      // 新分组首次通知前的等待时间，单位（秒）
+	GroupInterval  int64    `json:"groupInterval"`  // 分组内事件变化后再次通知的间隔，单位（秒）
+	RepeatInterval int64    `json:"repeatInterval"` // 分组内容未变化时的重复通知间隔，单位（分钟），为 0 时使用故障中心重复通知间隔
+}
+
+// FlapDetection 抖动检测，窗口内状态切换次数达到阈值时标记为抖动
+type FlapDetection struct {
+	Enabled    *bool `json:"enabled"`
+	Threshold  int64 `json:"threshold"`  // 窗口内状态切换次数阈值，默认 6
+	Window     int64 `json:"window"`     // 统计窗口，单位（秒），默认 3600
+	StableTime int64 `json:"stableTime"` // 无状态切换持续多久视为稳定，单位（秒），默认 900
+	HoldNotice *bool `json:"holdNotice"` // 抖动期间暂停通知，稳定后发送一条摘要
+}
+
+func (f FlapDetection) GetEnabled() bool {
+	return f.Enabled != nil && *f.Enabled
+}
+
+func (f FlapDetection) GetThreshold() int64 {
+	if f.Threshold <= 0 {
+		return 6
+	}
+	return f.Threshold
+}
+
+func (f FlapDetection) GetWindow() int64 {
+	if f.Window <= 0 {
+		return 3600
+	}
+	return f.Window
+}
+
+func (f FlapDetection) GetStableTime() int64 {
+	if f.StableTime <= 0 {
+		return 900
+	}
+	return f.StableTime
+}
+
+func (f FlapDetection) GetHoldNotice() bool {
+	return f.GetEnabled() && f.HoldNotice != nil && *f.HoldNotice
+}
+
+// InhibitRule 抑制规则, 当存在匹配 SourceMatchers 的告警时, 抑制 Equal 标签值相同且匹配 TargetMatchers 的告警
+type InhibitRule struct {
+	SourceMatchers []SilenceLabel `json:"sourceMatchers"`
+	TargetMatchers []SilenceLabel `json:"targetMatchers"`
+	Equal          []string       `json:"equal"`
+}
+
 func (u *UpgradeStrategy) GetEnabled() bool {
 	if u.Enabled == nil {
 		return false
@@ -52,6 +117,22 @@ func (u *UpgradeStrategy) GetEnabled() bool {
 	return *u.Enabled
 }
 
+// ToEscalationPolicy 将单级升级策略转换为升级策略，超时未认领时按重复间隔持续通知
+func (u *UpgradeStrategy) ToEscalationPolicy() EscalationPolicy {
+	return EscalationPolicy{
+		Levels: []EscalationLevel{
+			{
+				Delay:          u.Timeout,
+				Trigger:        EscalationTriggerNotAcknowledged,
+				TargetType:     EscalationTargetNotice,
+				NoticeId:       u.NoticeId,
+				Repeat:         -1,
+				RepeatInterval: u.RepeatInterval,
+			},
+		},
+	}
+}
+
 // GetSeverityAssessmentResult 获取等级评估结果，不满足条件时不进行升级
 func (f *FaultCenter) GetSeverityAssessmentResult(severity string) bool {
 	return slices.Contains(f.UpgradableSeverity, severity)
@@ -92,8 +173,22 @@ func (f *FaultCenter) GetAlarmAggregationType() string {
 	return f.AggregationType
 }
 
+// GetGroupRepeatInterval 获取分组重复通知间隔，单位（秒）
+func (f *FaultCenter) GetGroupRepeatInterval() int64 {
+	if f.GroupStrategy.RepeatInterval > 0 {
+		return f.GroupStrategy.RepeatInterval * 60
+	}
+	return f.RepeatNoticeInterval * 60
+}
+
 type AlertEventCacheKey string
 
+type AlertGroupCacheKey string
+
+func BuildAlertGroupCacheKey(tenantId, faultCenterId string) AlertGroupCacheKey {
+	return AlertGroupCacheKey(fmt.Sprintf("w8t:%s:%s:%s.groups", tenantId, FaultCenterPrefix, faultCenterId))
+}
+
 func BuildAlertEventCacheKey(tenantId, faultCenterId string) AlertEventCacheKey {
 	return AlertEventCacheKey(fmt.Sprintf("w8t:%s:%s:%s.events", tenantId, FaultCenterPrefix, faultCenterId))
 }
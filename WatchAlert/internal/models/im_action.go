@@ -0,0 +1,100 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	ImActionClaim   = "claim"   // 认领
+	ImActionSilence = "silence" // 静默 1h
+	ImActionTicket  = "ticket"  // 创建工单
+	ImActionResolve = "resolve" // 标记恢复
+
+	// ImActionExpire 卡片按钮有效期
+	ImActionExpire = 7 * 24 * time.Hour
+)
+
+// ImActionValue IM 卡片按钮回传值，通过签名防止伪造
+type ImActionValue struct {
+	Action        string `json:"action"`
+	TenantId      string `json:"tenantId"`
+	FaultCenterId string `json:"faultCenterId"`
+	Fingerprint   string `json:"fingerprint"`
+	NoticeId      string `json:"noticeId"`
+	ExpireAt      int64  `json:"expireAt"`
+	Sign          string `json:"sign"`
+}
+
+// ImActionButton 卡片按钮
+type ImActionButton struct {
+	Text  string
+	Style string // primary、danger、default
+	Value ImActionValue
+}
+
+// ImActionButtons 告警事件可执行的卡片按钮
+func ImActionButtons(alert AlertCurEvent, noticeId string, key []byte) []ImActionButton {
+	buttons := []ImActionButton{
+		{Text: "认领", Style: "primary", Value: ImActionValue{Action: ImActionClaim}},
+		{Text: "静默 1h", Style: "default", Value: ImActionValue{Action: ImActionSilence}},
+		{Text: "创建工单", Style: "default", Value: ImActionValue{Action: ImActionTicket}},
+		{Text: "标记恢复", Style: "danger", Value: ImActionValue{Action: ImActionResolve}},
+	}
+
+	expireAt := time.Now().Add(ImActionExpire).Unix()
+	for i := range buttons {
+		v := &buttons[i].Value
+		v.TenantId = alert.TenantId
+		v.FaultCenterId = alert.FaultCenterId
+		v.Fingerprint = alert.Fingerprint
+		v.NoticeId = noticeId
+		v.ExpireAt = expireAt
+		v.Sign = v.Signature(key)
+	}
+
+	return buttons
+}
+
+// Signature 计算回传值签名
+func (v ImActionValue) Signature(key []byte) string {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(fmt.Sprintf("%s|%s|%s|%s|%s|%d", v.Action, v.TenantId, v.FaultCenterId, v.Fingerprint, v.NoticeId, v.ExpireAt)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify 校验签名及有效期
+func (v ImActionValue) Verify(key []byte) error {
+	if !hmac.Equal([]byte(v.Signature(key)), []byte(v.Sign)) {
+		return fmt.Errorf("操作签名校验失败")
+	}
+	if time.Now().Unix() > v.ExpireAt {
+		return fmt.Errorf("操作已过期")
+	}
+
+	return nil
+}
+
+// Encode 回传值编码为文本指令，用于钉钉等仅能回传消息内容的平台
+func (v ImActionValue) Encode() string {
+	data, _ := json.Marshal(v)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeImActionValue 解析文本指令中的回传值
+func DecodeImActionValue(s string) (ImActionValue, error) {
+	var v ImActionValue
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return v, fmt.Errorf("操作指令解析失败")
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, fmt.Errorf("操作指令解析失败")
+	}
+	return v, nil
+}
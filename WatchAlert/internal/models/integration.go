@@ -0,0 +1,40 @@
+package models
+
+// Integration 事件集成，外部系统通过唯一地址与 Token 推送任意 JSON 事件到故障中心
+type Integration struct {
+	TenantId      string             `json:"tenantId"`
+	ID            string             `json:"id" gorm:"primaryKey"`
+	Name          string             `json:"name"`
+	Description   string             `json:"description"`
+	FaultCenterId string             `json:"faultCenterId"`
+	Token         string             `json:"token"`
+	Mapping       IntegrationMapping `json:"mapping" gorm:"mapping;serializer:json"`
+	Enabled       *bool              `json:"enabled"`
+	UpdateAt      int64              `json:"updateAt"`
+	UpdateBy      string             `json:"updateBy"`
+}
+
+// IntegrationMapping 请求体到告警事件字段的映射
+// 取值表达式以 $ 开头时按 JSONPath 解析（如 $.alert.host），否则作为模板渲染，模板中的 ${path} 会替换为对应字段值
+type IntegrationMapping struct {
+	EventsPath      string            `json:"eventsPath"`      // 事件列表路径，为空时整个请求体视为一条事件
+	RuleName        string            `json:"ruleName"`        // 告警名称
+	Labels          map[string]string `json:"labels"`          // 标签名 -> 取值表达式
+	Severity        string            `json:"severity"`        // 告警等级取值表达式
+	SeverityMapping map[string]string `json:"severityMapping"` // 原始等级 -> P0/P1/P2
+	FingerprintKeys []string          `json:"fingerprintKeys"` // 参与指纹计算的标签，为空时使用全部标签
+	Annotations     string            `json:"annotations"`     // 告警详情模板
+	ResolvePath     string            `json:"resolvePath"`     // 恢复条件字段
+	ResolveValues   []string          `json:"resolveValues"`   // 字段值命中其中之一时视为恢复
+}
+
+func (i *Integration) TableName() string {
+	return "w8t_integration"
+}
+
+func (i *Integration) GetEnabled() bool {
+	if i.Enabled == nil {
+		return false
+	}
+	return *i.Enabled
+}
@@ -1,19 +1,27 @@
 package models
 
 type AlertNotice struct {
-	TenantId     string   `json:"tenantId"`
-	Uuid         string   `json:"uuid"`
-	Name         string   `json:"name"`
-	DutyId       *string  `json:"dutyId"`
-	NoticeType   string   `json:"noticeType"`
-	NoticeTmplId string   `json:"noticeTmplId"`
-	DefaultHook  string   `json:"hook" gorm:"column:hook"`
-	DefaultSign  string   `json:"sign" gorm:"column:sign"`
-	Routes       []Route  `json:"routes" gorm:"column:routes;serializer:json"`
-	Email        Email    `json:"email" gorm:"email;serializer:json"`
-	PhoneNumber  []string `json:"phoneNumber" gorm:"phoneNumber;serializer:json"`
-	UpdateAt     int64    `json:"updateAt"`
-	UpdateBy     string   `json:"updateBy"`
+	TenantId     string    `json:"tenantId"`
+	Uuid         string    `json:"uuid"`
+	Name         string    `json:"name"`
+	DutyId       *string   `json:"dutyId"`
+	NoticeType   string    `json:"noticeType"`
+	NoticeTmplId string    `json:"noticeTmplId"`
+	DefaultHook  string    `json:"hook" gorm:"column:hook"`
+	DefaultSign  string    `json:"sign" gorm:"column:sign"`
+	ChatId       string    `json:"chatId" gorm:"column:chatId"` // Telegram chat_id
+	Routes       []Route   `json:"routes" gorm:"column:routes;serializer:json"`
+	Email        Email     `json:"email" gorm:"email;serializer:json"`
+	PhoneNumber  []string  `json:"phoneNumber" gorm:"phoneNumber;serializer:json"`
+	RateLimit    RateLimit `json:"rateLimit" gorm:"column:rateLimit;serializer:json"`
+	// 电话、短信通知是否呼叫当前值班用户，默认发送给配置的手机号
+	CallDutyUsers *bool  `json:"callDutyUsers" gorm:"column:callDutyUsers"`
+	UpdateAt      int64  `json:"updateAt"`
+	UpdateBy      string `json:"updateBy"`
+}
+
+func (alertNotice AlertNotice) GetCallDutyUsers() bool {
+	return alertNotice.CallDutyUsers != nil && *alertNotice.CallDutyUsers
 }
 
 func (alertNotice *AlertNotice) GetDutyId() *string {
@@ -23,6 +31,48 @@ func (alertNotice *AlertNotice) GetDutyId() *string {
 	return alertNotice.DutyId
 }
 
+// RateLimit 通知限流，超出限制的消息进入风暴模式，按周期合并为一条摘要消息发送
+type RateLimit struct {
+	Enabled        *bool  `json:"enabled"`
+	PerMinute      int64  `json:"perMinute"`      // 每分钟允许发送的消息数
+	Burst          int64  `json:"burst"`          // 突发容量，为 0 时等于 PerMinute
+	DigestInterval int64  `json:"digestInterval"` // 风暴摘要发送间隔，单位（秒），默认 60
+	BypassSeverity string `json:"bypassSeverity"` // 不受限流影响的最低告警等级，默认 P0
+}
+
+func (r RateLimit) GetEnabled() bool {
+	if r.Enabled == nil {
+		return false
+	}
+	return *r.Enabled && r.PerMinute > 0
+}
+
+func (r RateLimit) GetBurst() int64 {
+	if r.Burst <= 0 {
+		return r.PerMinute
+	}
+	return r.Burst
+}
+
+func (r RateLimit) GetBypassSeverity() string {
+	if r.BypassSeverity == "" {
+		return "P0"
+	}
+	return r.BypassSeverity
+}
+
+// IsBypass 告警等级达到 BypassSeverity 时直接发送，等级越高字符串越小，P0 > P1 > P2
+func (r RateLimit) IsBypass(severity string) bool {
+	return severity != "" && severity <= r.GetBypassSeverity()
+}
+
+func (r RateLimit) GetDigestInterval() int64 {
+	if r.DigestInterval <= 0 {
+		return 60
+	}
+	return r.DigestInterval
+}
+
 type Route struct {
 	// 告警等级
 	Severity string `json:"severity"`
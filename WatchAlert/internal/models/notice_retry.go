@@ -0,0 +1,47 @@
+package models
+
+// NoticeRetryTask 通知重试任务
+type NoticeRetryTask struct {
+	ID       string `json:"id"` // 事件ID + 通知对象 + 恢复状态计算得出，保证同一通知只存在一个重试任务
+	TenantId string `json:"tenantId"`
+	EventId  string `json:"eventId"`
+	// 告警事件所在故障中心及指纹，用于重试前确认告警是否已恢复
+	FaultCenterId string   `json:"faultCenterId"`
+	Fingerprint   string   `json:"fingerprint"`
+	RuleName      string   `json:"ruleName"`
+	Severity      string   `json:"severity"`
+	NoticeType    string   `json:"noticeType"`
+	NoticeId      string   `json:"noticeId"`
+	NoticeName    string   `json:"noticeName"`
+	IsRecovered   bool     `json:"isRecovered"`
+	Hook          string   `json:"hook"`
+	Email         Email    `json:"email"`
+	Content       string   `json:"content"`
+	PhoneNumber   []string `json:"phoneNumber"`
+	Sign          string   `json:"sign"`
+	ChatId        string   `json:"chatId"`
+	IsReport      bool     `json:"isReport"`
+	Attempts      int      `json:"attempts"`    // 已发送次数
+	MaxAttempts   int      `json:"maxAttempts"` // 最大发送次数
+	NextRetryAt   int64    `json:"nextRetryAt"` // 下次重试时间
+	LastError     string   `json:"lastError"`   // 最近一次失败原因
+	CreateAt      int64    `json:"createAt"`
+	UpdateAt      int64    `json:"updateAt"`
+}
+
+type NoticeRetryCacheKey string
+
+const (
+	// NoticeRetryTasksKey 全部待重试任务详情
+	NoticeRetryTasksKey NoticeRetryCacheKey = "w8t:noticeRetry:tasks"
+	// NoticeRetryQueueKey 待重试任务队列，score 为下次重试时间
+	NoticeRetryQueueKey NoticeRetryCacheKey = "w8t:noticeRetry:queue"
+)
+
+func BuildNoticeRetryLockKey(id string) NoticeRetryCacheKey {
+	return NoticeRetryCacheKey("w8t:noticeRetry:lock:" + id)
+}
+
+func BuildNoticeDeadLetterKey(tenantId string) NoticeRetryCacheKey {
+	return NoticeRetryCacheKey("w8t:" + tenantId + ":noticeRetry:deadLetters")
+}
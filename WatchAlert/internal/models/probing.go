@@ -16,8 +16,17 @@ type ProbingRule struct {
 	UpdateAt              int64                 `json:"updateAt"`
 	UpdateBy              string                `json:"updateBy"`
 	Enabled               *bool                 `json:"enabled" gorm:"enabled"`
+	// 拨测位置，为空时仅在服务端执行；local 表示服务端，其余为拨测节点的位置标签
+	Locations []string `json:"locations" gorm:"locations;serializer:json"`
+	// 失败位置数达到该值时告警，默认 1
+	Quorum int `json:"quorum"`
+	// 月度可用性报告的通知对象，为空时不发送
+	ReportNoticeId string `json:"reportNoticeId"`
 }
 
+// ProbingLocalLocation 服务端本地拨测的位置标签
+const ProbingLocalLocation = "local"
+
 func (n *ProbingRule) TableName() string {
 	return "w8t_probing_rule"
 }
@@ -30,6 +39,18 @@ func (n *ProbingRule) GetRecoverNotify() *bool {
 	return n.RecoverNotify
 }
 
+// IsDistributed 是否为多位置拨测
+func (n *ProbingRule) IsDistributed() bool {
+	return len(n.Locations) > 0
+}
+
+func (n *ProbingRule) GetQuorum() int {
+	if n.Quorum <= 0 {
+		return 1
+	}
+	return n.Quorum
+}
+
 func (n *ProbingRule) GetEnabled() *bool {
 	if n.Enabled == nil {
 		isOk := false
@@ -43,6 +64,11 @@ type ProbingEndpointValues struct {
 	PICMP Picmp `json:"pIcmp"`
 	PTCP  Ptcp  `json:"pTcp"`
 	PSSL  Pssl  `json:"pSsl"`
+	// 多步骤 HTTP 事务
+	PTransaction Ptransaction `json:"pTransaction"`
+	PDNS         Pdns         `json:"pDns"`
+	PGRPC        Pgrpc        `json:"pGrpc"`
+	PWebSocket   Pwebsocket   `json:"pWebSocket"`
 }
 
 type Picmp struct {
@@ -61,6 +87,8 @@ type Phttp struct {
 	StatusCode string `json:"statusCode" json:"status_code,omitempty"`
 	// 响应时间, ms
 	Latency string `json:"latency" json:"latency,omitempty"`
+	// 未通过的断言
+	FailedAssertions string `json:"failedAssertions"`
 }
 
 type Ptcp struct {
@@ -75,13 +103,46 @@ type Pssl struct {
 	TimeRemaining string `json:"timeRemaining"`
 }
 
+type Ptransaction struct {
+	IsSuccessful string `json:"isSuccessful"`
+	FailedStep   string `json:"failedStep"`
+	Latency      string `json:"latency"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+type Pdns struct {
+	ResolveTime  string `json:"resolveTime"`
+	AnswerCount  string `json:"answerCount"`
+	Answers      string `json:"answers"`
+	IsSuccessful string `json:"isSuccessful"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+type Pgrpc struct {
+	ResponseTime  string `json:"responseTime"`
+	ServingStatus string `json:"servingStatus"`
+	IsSuccessful  string `json:"isSuccessful"`
+	ErrorMessage  string `json:"errorMessage"`
+}
+
+type Pwebsocket struct {
+	ConnectTime  string `json:"connectTime"`
+	ResponseTime string `json:"responseTime"`
+	IsSuccessful string `json:"isSuccessful"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
 type ProbingEndpointConfig struct {
 	// 端点
 	Endpoint string `json:"endpoint"`
 	// 评估策略
-	Strategy endpointStrategy `json:"strategy"`
-	HTTP     ehttp            `json:"http"`
-	ICMP     eicmp            `json:"icmp"`
+	Strategy    endpointStrategy `json:"strategy"`
+	HTTP        ehttp            `json:"http"`
+	ICMP        eicmp            `json:"icmp"`
+	Transaction etransaction     `json:"transaction"`
+	DNS         edns             `json:"dns"`
+	GRPC        egrpc            `json:"grpc"`
+	WebSocket   ewebsocket       `json:"webSocket"`
 }
 
 type endpointStrategy struct {
@@ -103,6 +164,37 @@ type ehttp struct {
 	Method string            `json:"method"`
 	Header map[string]string `json:"header"`
 	Body   string            `json:"body"`
+	// 响应断言，配置后拨测结果由断言决定
+	Assertions []HTTPAssertion `json:"assertions"`
+	// 断言组合方式 AND（默认）、OR
+	AssertionLogic string `json:"assertionLogic"`
+}
+
+const (
+	HTTPAssertionStatusCode      = "StatusCode"
+	HTTPAssertionLatency         = "Latency"
+	HTTPAssertionBodyContains    = "BodyContains"
+	HTTPAssertionBodyNotContains = "BodyNotContains"
+	HTTPAssertionBodyRegex       = "BodyRegex"
+	HTTPAssertionJSONPath        = "JSONPath"
+	HTTPAssertionHeader          = "Header"
+	HTTPAssertionCertDays        = "CertDays"
+	HTTPAssertionRedirectCount   = "RedirectCount"
+
+	HTTPAssertionLogicAnd = "AND"
+	HTTPAssertionLogicOr  = "OR"
+)
+
+// HTTPAssertion HTTP 响应断言
+type HTTPAssertion struct {
+	// 断言类型
+	Type string `json:"type"`
+	// JSONPath 表达式或响应头名称
+	Target string `json:"target"`
+	// 比较运算 ==、!=、>、>=、<、<=，BodyContains、BodyNotContains、BodyRegex、Header 无需配置
+	Operator string `json:"operator"`
+	// 期望值
+	Value string `json:"value"`
 }
 
 type eicmp struct {
@@ -110,6 +202,70 @@ type eicmp struct {
 	Count    int `json:"count"`
 }
 
+type edns struct {
+	// 记录类型 A、AAAA、CNAME、MX、TXT、NS，默认 A
+	RecordType string `json:"recordType"`
+	// DNS 服务器地址，如 8.8.8.8:53，为空时使用系统配置
+	Resolver string `json:"resolver"`
+	// 期望的解析结果，需全部包含
+	ExpectedAnswers []string `json:"expectedAnswers"`
+}
+
+type egrpc struct {
+	// grpc.health.v1 检查的服务名，为空检查整体状态
+	Service string `json:"service"`
+	// 是否使用 TLS
+	TLS bool `json:"tls"`
+}
+
+type ewebsocket struct {
+	Header map[string]string `json:"header"`
+	// 连接后发送的消息，为空时仅检查连接
+	Send string `json:"send"`
+	// 期望收到的消息需包含的内容
+	Expect string `json:"expect"`
+}
+
+type etransaction struct {
+	// 按顺序执行的步骤，共享 Cookie
+	Steps []HTTPTransactionStep `json:"steps"`
+}
+
+// HTTPTransactionStep 多步骤 HTTP 事务中的单个步骤，URL、Header、Body 支持 ${变量} 引用前序步骤提取的值
+type HTTPTransactionStep struct {
+	Name     string            `json:"name"`
+	Method   string            `json:"method"`
+	URL      string            `json:"url"`
+	Header   map[string]string `json:"header"`
+	Body     string            `json:"body"`
+	Extracts []HTTPStepExtract `json:"extracts"`
+	Assert   HTTPStepAssertion `json:"assert"`
+}
+
+// HTTPStepExtract 从响应中提取变量，优先级 Header > JSONPath > Regex
+type HTTPStepExtract struct {
+	// 变量名
+	Name string `json:"name"`
+	// 响应头名称
+	Header string `json:"header"`
+	// 响应体 JSONPath，如 $.data.token
+	JSONPath string `json:"jsonPath"`
+	// 响应体正则，有捕获组时取第一个捕获组
+	Regex string `json:"regex"`
+}
+
+// HTTPStepAssertion 步骤断言，均为空时仅要求状态码为 2xx
+type HTTPStepAssertion struct {
+	// 期望的状态码
+	StatusCodes []int `json:"statusCodes"`
+	// 响应头需包含的值
+	Headers map[string]string `json:"headers"`
+	// 响应体需包含的内容
+	BodyContains string `json:"bodyContains"`
+	// 最大响应时间, ms
+	MaxLatency int64 `json:"maxLatency"`
+}
+
 // ------------------------ Event ------------------------
 
 type ProbingEvent struct {
@@ -155,11 +311,92 @@ func BuildProbingValueCacheKey(tenantId, ruleId string) ProbingValueCacheKey {
 }
 
 type ProbingHistory struct {
-	Timestamp int64          `json:"timestamp"`
-	RuleId    string         `json:"ruleId"`
-	Value     map[string]any `json:"value" gorm:"value;serializer:json"`
+	Timestamp int64  `json:"timestamp"`
+	RuleId    string `json:"ruleId"`
+	// 多步骤 HTTP 事务的步骤名称，整体结果为空
+	Step string `json:"step"`
+	// 拨测位置，多位置拨测时记录
+	Location string         `json:"location"`
+	Value    map[string]any `json:"value" gorm:"value;serializer:json"`
 }
 
 func (p *ProbingHistory) TableName() string {
 	return "w8t_probing_history"
 }
+
+// ProbingHistoryRollup 拨测历史小时聚合，原始记录过期后生成
+type ProbingHistoryRollup struct {
+	// 整点时间戳
+	Timestamp int64  `json:"timestamp"`
+	RuleId    string `json:"ruleId"`
+	Location  string `json:"location"`
+	Total     int64  `json:"total"`
+	Failed    int64  `json:"failed"`
+	// 本小时内开始的故障次数及其持续时长（秒）
+	Outages         int64 `json:"outages"`
+	DowntimeSeconds int64 `json:"downtimeSeconds"`
+	// 本小时内恢复的故障次数及其恢复耗时（秒），用于计算 MTTR
+	Recoveries      int64 `json:"recoveries"`
+	RecoverySeconds int64 `json:"recoverySeconds"`
+	// 延迟分布，与 ProbingLatencyBuckets 对应，最后一个为溢出桶
+	LatencyBuckets []int64 `json:"latencyBuckets" gorm:"latencyBuckets;serializer:json"`
+}
+
+func (p *ProbingHistoryRollup) TableName() string {
+	return "w8t_probing_history_rollup"
+}
+
+// ProbingLatencyBuckets 延迟分布桶上限，单位（毫秒）
+var ProbingLatencyBuckets = []float64{1, 2, 5, 10, 20, 50, 100, 200, 300, 500, 750, 1000, 1500, 2000, 3000, 5000, 10000, 30000}
+
+// ProbingAvailability 拨测规则在统计周期内的可用性
+type ProbingAvailability struct {
+	RuleId   string `json:"ruleId"`
+	RuleName string `json:"ruleName"`
+	RuleType string `json:"ruleType"`
+	Endpoint string `json:"endpoint"`
+	Location string `json:"location"`
+	StartAt  int64  `json:"startAt"`
+	EndAt    int64  `json:"endAt"`
+	// 参与统计的拨测次数，不含静默期间
+	Total  int64 `json:"total"`
+	Failed int64 `json:"failed"`
+	// 静默期间被排除的拨测次数
+	Silenced int64 `json:"silenced"`
+	// NoData 统计范围内没有可参与统计的拨测记录，此时 Uptime 为 0 且无意义
+	NoData bool `json:"noData"`
+	// 可用率，百分比
+	Uptime          float64 `json:"uptime"`
+	Outages         int64   `json:"outages"`
+	DowntimeSeconds int64   `json:"downtimeSeconds"`
+	// 平均恢复时长，单位（秒）
+	MTTR float64 `json:"mttr"`
+	// 延迟分位数，单位（毫秒）
+	LatencyP50 float64 `json:"latencyP50"`
+	LatencyP90 float64 `json:"latencyP90"`
+	LatencyP99 float64 `json:"latencyP99"`
+}
+
+// ProbingAgent 分布式拨测节点
+type ProbingAgent struct {
+	AgentId  string `json:"agentId"`
+	Location string `json:"location"`
+	Hostname string `json:"hostname"`
+	Version  string `json:"version"`
+	// 节点来源地址
+	Address       string `json:"address"`
+	RegisteredAt  int64  `json:"registeredAt"`
+	LastHeartbeat int64  `json:"lastHeartbeat"`
+	// 是否在线，列表查询时按心跳计算
+	Online bool `json:"online"`
+}
+
+// ProbingLocationResult 多位置拨测中单个位置的最新结果
+type ProbingLocationResult struct {
+	Location     string         `json:"location"`
+	AgentId      string         `json:"agentId"`
+	Timestamp    int64          `json:"timestamp"`
+	Failed       bool           `json:"failed"`
+	Value        map[string]any `json:"value"`
+	ErrorMessage string         `json:"errorMessage"`
+}
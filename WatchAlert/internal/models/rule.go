@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type AlertRule struct {
 	//gorm.Model
 	TenantId             string            `json:"tenantId"`
@@ -15,6 +17,8 @@ type AlertRule struct {
 	Description          string            `json:"description"`
 	EffectiveTime        EffectiveTime     `json:"effectiveTime" gorm:"effectiveTime;serializer:json"`
 	Severity             string            `json:"severity"`
+	// 规则类型，Alert（默认）告警规则、Recording 记录规则、Composite 组合规则
+	RuleType string `json:"ruleType"`
 
 	// Prometheus
 	PrometheusConfig PrometheusConfig `json:"prometheusConfig" gorm:"prometheusConfig;serializer:json"`
@@ -41,12 +45,117 @@ type AlertRule struct {
 
 	LogEvalCondition string `json:"logEvalCondition" gorm:"logEvalCondition;serializer:json"`
 
+	// 无数据、数据源异常处理策略
+	NoDataConfig NoDataConfig `json:"noDataConfig" gorm:"noDataConfig;serializer:json"`
+
+	// 记录规则
+	RecordingConfig RecordingConfig `json:"recordingConfig" gorm:"recordingConfig;serializer:json"`
+
+	// 组合规则
+	CompositeConfig CompositeConfig `json:"compositeConfig" gorm:"compositeConfig;serializer:json"`
+
 	FaultCenterId string `json:"faultCenterId"`
 	UpdateAt      int64  `json:"updateAt"`
 	UpdateBy      string `json:"updateBy"`
 	Enabled       *bool  `json:"enabled" gorm:"enabled"`
 }
 
+const (
+	RuleTypeAlert     = "Alert"
+	RuleTypeRecording = "Recording"
+	RuleTypeComposite = "Composite"
+)
+
+// RecordingConfig 记录规则，定期执行 PromQL 并将结果以 Prometheus remote-write 协议写入目标数据源
+type RecordingConfig struct {
+	// Record 写入的指标名称
+	Record string `json:"record"`
+	// Expr 预计算的 PromQL 表达式
+	Expr string `json:"expr"`
+	// Labels 附加到结果序列的标签，会覆盖查询结果中的同名标签
+	Labels map[string]string `json:"labels"`
+	// WriteDatasourceId 写入的 Prometheus、VictoriaMetrics 数据源，为空时写回查询的数据源
+	WriteDatasourceId string `json:"writeDatasourceId"`
+}
+
+// IsRecordingRule 是否为记录规则
+func (a *AlertRule) IsRecordingRule() bool {
+	return a.RuleType == RuleTypeRecording
+}
+
+// CompositeConfig 组合规则，按共同标签关联多个条件，布尔表达式成立时告警
+type CompositeConfig struct {
+	// Conditions 参与组合的条件，至少两个
+	Conditions []CompositeCondition `json:"conditions"`
+	// JoinLabels 关联各条件结果的标签，如 service；为空时各条件整体参与计算
+	JoinLabels []string `json:"joinLabels"`
+	// Expr 基于条件别名的布尔表达式，支持 &&、||、!、括号，如 A && (B || !C)
+	Expr string `json:"expr"`
+}
+
+// CompositeCondition 组合规则中的单个条件，引用已有规则的告警状态，或直接查询指标数据源
+type CompositeCondition struct {
+	// Alias 条件别名，用于表达式、标签及注解
+	Alias string `json:"alias"`
+	// RuleId 引用的规则，该规则处于告警中的事件视为条件成立
+	RuleId string `json:"ruleId"`
+	// DatasourceId、Query、Expr 未引用规则时直接查询指标数据源，满足 Expr 的序列视为条件成立
+	DatasourceId string `json:"datasourceId"`
+	Query        string `json:"query"`
+	Expr         string `json:"expr"`
+}
+
+// IsCompositeRule 是否为组合规则
+func (a *AlertRule) IsCompositeRule() bool {
+	return a.RuleType == RuleTypeComposite
+}
+
+const (
+	// NoDataPolicyOK 视为正常，已有告警按恢复流程处理
+	NoDataPolicyOK = "OK"
+	// NoDataPolicyAlerting 视为告警，保持已有告警，无告警时立即产生 NoData 事件
+	NoDataPolicyAlerting = "Alerting"
+	// NoDataPolicyKeepLast 保持上次状态
+	NoDataPolicyKeepLast = "KeepLast"
+	// NoDataPolicyNoData 连续 N 次评估无数据后产生 NoData 事件
+	NoDataPolicyNoData = "NoData"
+
+	// 无数据、数据源异常事件的 alertstate 标签
+	AlertStateNoData          = "NoData"
+	AlertStateDatasourceError = "DatasourceError"
+)
+
+// NoDataConfig 查询无数据及数据源不可用时的处理策略
+type NoDataConfig struct {
+	// Policy 无数据处理策略，OK（默认）、Alerting、KeepLast、NoData
+	Policy string `json:"policy"`
+	// Times NoData 策略下连续无数据的评估次数，默认 3
+	Times int64 `json:"times"`
+	// ErrorPolicy 数据源不可用或查询失败时的处理策略，OK（默认，已有告警按恢复流程处理）、KeepLast、Alerting
+	ErrorPolicy string `json:"errorPolicy"`
+}
+
+func (n NoDataConfig) GetPolicy() string {
+	if n.Policy == "" {
+		return NoDataPolicyOK
+	}
+	return n.Policy
+}
+
+func (n NoDataConfig) GetTimes() int64 {
+	if n.Times <= 0 {
+		return 3
+	}
+	return n.Times
+}
+
+func (n NoDataConfig) GetErrorPolicy() string {
+	if n.ErrorPolicy == "" {
+		return NoDataPolicyOK
+	}
+	return n.ErrorPolicy
+}
+
 type ElasticSearchConfig struct {
 	Index           string            `json:"index"`
 	Scope           int64             `json:"scope"`
@@ -85,23 +194,141 @@ type KubernetesConfig struct {
 	Scope    int      `json:"scope"`
 }
 
+const (
+	// TraceConditionCount 存在异常链路即告警
+	TraceConditionCount = "Count"
+	// TraceConditionP95Latency Span 耗时 P95，单位（毫秒）
+	TraceConditionP95Latency = "P95Latency"
+	// TraceConditionP99Latency Span 耗时 P99，单位（毫秒）
+	TraceConditionP99Latency = "P99Latency"
+	// TraceConditionErrorRatio 错误 Span 占比，单位（%）
+	TraceConditionErrorRatio = "ErrorRatio"
+
+	// TraceDefaultSampleLimit 分位数、错误率按最近的链路采样计算，默认采样链路数
+	TraceDefaultSampleLimit int64 = 500
+	// TraceMaxSampleLimit 采样链路数上限
+	TraceMaxSampleLimit int64 = 5000
+)
+
+// JaegerConfig 链路规则配置，Jaeger、Tempo、Zipkin 数据源共用
 type JaegerConfig struct {
 	Service string `json:"service"`
 	Scope   int    `json:"scope"`
 	Tags    string `json:"tags"`
+	// Operation 接口名称，为空表示服务下所有接口
+	Operation string `json:"operation,omitempty"`
+	// Query Tempo TraceQL 查询语句，为空时按 Service、Operation 生成
+	Query string `json:"query,omitempty"`
+	// ConditionType 告警条件类型，Count（默认）、P95Latency、P99Latency、ErrorRatio
+	ConditionType string `json:"conditionType,omitempty"`
+	// Expr 告警条件，如 > 2000
+	Expr string `json:"expr,omitempty"`
+	// GroupByOperation 按接口分别计算并生成独立告警
+	GroupByOperation bool `json:"groupByOperation,omitempty"`
+	// SampleLimit 分位数、错误率采样的最大链路数，默认 500；后端不提供聚合查询，结果为采样近似值
+	SampleLimit int64 `json:"sampleLimit,omitempty"`
+}
+
+func (j JaegerConfig) GetSampleLimit() int64 {
+	if j.SampleLimit <= 0 {
+		return TraceDefaultSampleLimit
+	}
+	return j.SampleLimit
+}
+
+func (j JaegerConfig) GetConditionType() string {
+	if j.ConditionType == "" {
+		return TraceConditionCount
+	}
+	return j.ConditionType
 }
 
+// PrometheusConfig 指标类规则配置，Graphite、InfluxDB、OpenTSDB 数据源同样使用该配置，
+// PromQL 分别填写 Graphite target、InfluxQL/Flux 语句、OpenTSDB m 表达式
 type PrometheusConfig struct {
 	PromQL      string `json:"promQL"`
 	Annotations string `json:"annotations"`
 	//ForDuration int64   `json:"forDuration"`
 	Rules []Rules `json:"rules"`
+	// Mode 规则模式，Threshold（默认，静态阈值）、Anomaly（异常检测）
+	Mode          string        `json:"mode,omitempty"`
+	AnomalyConfig AnomalyConfig `json:"anomalyConfig,omitempty"`
+}
+
+func (p PrometheusConfig) IsAnomalyMode() bool {
+	return p.Mode == MetricRuleModeAnomaly
+}
+
+const (
+	MetricRuleModeThreshold = "Threshold"
+	MetricRuleModeAnomaly   = "Anomaly"
+
+	// AnomalyAlgorithmWeekOverWeek 以上周同一时段的均值和标准差作为基线
+	AnomalyAlgorithmWeekOverWeek = "WeekOverWeek"
+	// AnomalyAlgorithmStdDev 以最近窗口内的滚动均值和标准差作为基线
+	AnomalyAlgorithmStdDev = "StdDev"
+	// AnomalyAlgorithmHoltWinters 以 Holt-Winters 预测值和预测残差的标准差作为基线
+	AnomalyAlgorithmHoltWinters = "HoltWinters"
+
+	AnomalyDirectionBoth = "Both"
+	AnomalyDirectionUp   = "Up"
+	AnomalyDirectionDown = "Down"
+)
+
+// AnomalyConfig 异常检测配置，基线区间为 expected ± k·stddev，k 为各等级规则的 Sensitivity
+type AnomalyConfig struct {
+	Algorithm string `json:"algorithm"`
+	// Window 历史数据窗口，单位（分钟），默认 60；WeekOverWeek 取上周同一时刻前后各半个窗口
+	Window int64 `json:"window"`
+	// Step 历史数据步长，单位（秒），默认 60
+	Step int64 `json:"step"`
+	// Season HoltWinters 季节周期，单位（分钟），默认 1440，历史数据取两个周期
+	Season int64 `json:"season"`
+	// Direction 检测方向，Both（默认）、Up 仅检测高于基线、Down 仅检测低于基线
+	Direction string `json:"direction"`
+}
+
+func (a AnomalyConfig) GetWindow() time.Duration {
+	if a.Window <= 0 {
+		return time.Hour
+	}
+	return time.Duration(a.Window) * time.Minute
+}
+
+func (a AnomalyConfig) GetStep() time.Duration {
+	if a.Step <= 0 {
+		return time.Minute
+	}
+	return time.Duration(a.Step) * time.Second
+}
+
+func (a AnomalyConfig) GetSeason() time.Duration {
+	if a.Season <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(a.Season) * time.Minute
+}
+
+func (a AnomalyConfig) GetDirection() string {
+	if a.Direction == "" {
+		return AnomalyDirectionBoth
+	}
+	return a.Direction
 }
 
 type Rules struct {
 	ForDuration int64  `json:"forDuration"`
 	Severity    string `json:"severity"`
 	Expr        string `json:"expr"`
+	// Sensitivity 异常检测模式下的灵敏度 k，值越小越灵敏，默认 3
+	Sensitivity float64 `json:"sensitivity,omitempty"`
+}
+
+func (r Rules) GetSensitivity() float64 {
+	if r.Sensitivity <= 0 {
+		return 3
+	}
+	return r.Sensitivity
 }
 
 type EffectiveTime struct {
@@ -128,6 +355,7 @@ type VictoriaLogsConfig struct {
 	Limit    int    `json:"limit"`
 }
 
+// ClickHouseConfig ClickHouse 规则配置，MySQL、PostgreSQL 数据源同样使用该配置，LogQL 为只读 SQL 语句且 ValueField 必填
 type ClickHouseConfig struct {
 	LogQL string `json:"logQL"`
 	// ValueField 指定从查询结果中提取哪个字段作为告警判断的数值
@@ -0,0 +1,36 @@
+package models
+
+// RuleBacktestResult 规则回测结果
+type RuleBacktestResult struct {
+	StartAt         int64               `json:"startAt"`
+	EndAt           int64               `json:"endAt"`
+	Step            int64               `json:"step"`            // 实际使用的评估步长，单位（秒）
+	RecoverWaitTime int64               `json:"recoverWaitTime"` // 恢复等待时间，单位（秒）
+	Evaluations     int64               `json:"evaluations"`     // 评估次数
+	Events          []RuleBacktestEvent `json:"events"`
+	Summary         RuleBacktestSummary `json:"summary"`
+}
+
+// RuleBacktestEvent 回测期间产生的告警事件
+type RuleBacktestEvent struct {
+	DatasourceId string                 `json:"datasourceId"`
+	Fingerprint  string                 `json:"fingerprint"`
+	Severity     string                 `json:"severity"`
+	Labels       map[string]interface{} `json:"labels"`
+	PendingAt    int64                  `json:"pendingAt"`   // 首次满足条件时间
+	FiredAt      int64                  `json:"firedAt"`     // 达到持续时间进入告警状态的时间
+	RecoveredAt  int64                  `json:"recoveredAt"` // 恢复时间，为 0 表示回测结束时仍在告警
+	Duration     int64                  `json:"duration"`    // 告警持续时间，单位（秒）
+	FirstValue   float64                `json:"firstValue"`
+	MaxValue     float64                `json:"maxValue"`
+	Suppressed   bool                   `json:"suppressed"` // 触发时存在更高等级的告警，实际不会发送通知
+}
+
+// RuleBacktestSummary 回测汇总
+type RuleBacktestSummary struct {
+	Total       int64            `json:"total"`       // 告警次数
+	Notified    int64            `json:"notified"`    // 实际会发送通知的告警次数
+	StillFiring int64            `json:"stillFiring"` // 回测结束时仍在告警的数量
+	Severities  map[string]int64 `json:"severities"`  // 各等级告警次数
+	AvgDuration int64            `json:"avgDuration"` // 已恢复告警的平均持续时间，单位（秒）
+}
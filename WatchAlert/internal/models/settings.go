@@ -12,6 +12,8 @@ type Settings struct {
 	EmailConfig     emailConfig     `json:"emailConfig" gorm:"emailConfig;serializer:json"`
 	AppVersion      string          `json:"appVersion" gorm:"-"`
 	PhoneCallConfig phoneCallConfig `json:"phoneCallConfig" gorm:"phoneCallConfig;serializer:json"`
+	SmsConfig       SmsConfig       `json:"smsConfig" gorm:"smsConfig;serializer:json"`
+	ImActionConfig  ImActionConfig  `json:"imActionConfig" gorm:"imActionConfig;serializer:json"`
 	AiConfig        AiConfig        `json:"aiConfig" gorm:"aiConfig;serializer:json"`
 	LdapConfig      LdapConfig      `json:"ldapConfig" gorm:"ldapConfig;serializer:json"`
 	OidcConfig      OidcConfig      `json:"oidcConfig" gorm:"oidcConfig;serializer:json"`
@@ -32,6 +34,75 @@ type phoneCallConfig struct {
 	TtsCode         string `json:"ttsCode"`
 }
 
+// SmsConfig 短信服务配置
+type SmsConfig struct {
+	Provider  string           `json:"provider"`  // aliyun、tencent、twilio、http
+	MaxLength int              `json:"maxLength"` // 短信内容最大长度，超出部分截断
+	Aliyun    AliyunSmsConfig  `json:"aliyun"`
+	Tencent   TencentSmsConfig `json:"tencent"`
+	Twilio    TwilioSmsConfig  `json:"twilio"`
+	Http      HttpSmsConfig    `json:"http"`
+}
+
+// AliyunSmsConfig 阿里云短信，模版变量固定为 ${content}
+type AliyunSmsConfig struct {
+	Endpoint        string `json:"endpoint"`
+	AccessKeyId     string `json:"accessKeyId"`
+	AccessKeySecret string `json:"accessKeySecret"`
+	SignName        string `json:"signName"`
+	TemplateCode    string `json:"templateCode"`
+}
+
+// TencentSmsConfig 腾讯云短信，模版需包含一个变量 {1}
+type TencentSmsConfig struct {
+	Region     string `json:"region"`
+	SecretId   string `json:"secretId"`
+	SecretKey  string `json:"secretKey"`
+	SdkAppId   string `json:"sdkAppId"`
+	SignName   string `json:"signName"`
+	TemplateId string `json:"templateId"`
+}
+
+// TwilioSmsConfig Twilio 短信
+type TwilioSmsConfig struct {
+	AccountSid string `json:"accountSid"`
+	AuthToken  string `json:"authToken"`
+	From       string `json:"from"`
+}
+
+// HttpSmsConfig 通用 HTTP 短信网关
+type HttpSmsConfig struct {
+	Url     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	// 请求体模版，支持 ${phoneNumbers}（逗号分隔）与 ${content} 变量，为空时发送 {"phoneNumbers": [], "content": ""}
+	BodyTemplate string `json:"bodyTemplate"`
+}
+
+func (s SmsConfig) GetMaxLength() int {
+	if s.MaxLength <= 0 {
+		return 300
+	}
+	return s.MaxLength
+}
+
+// ImActionConfig IM 卡片交互配置，开启后飞书、钉钉、Slack 告警卡片附带认领、静默、创建工单、标记恢复按钮
+type ImActionConfig struct {
+	Enable *bool `json:"enable"`
+	// 钉钉企业内部机器人 AppSecret，用于校验 outgoing 回调签名
+	DingDingAppSecret string `json:"dingDingAppSecret"`
+	// 飞书应用 Verification Token，用于校验卡片回调签名
+	FeiShuVerificationToken string `json:"feiShuVerificationToken"`
+	// Slack App Signing Secret，用于校验交互回调签名
+	SlackSigningSecret string `json:"slackSigningSecret"`
+}
+
+func (i ImActionConfig) GetEnable() bool {
+	if i.Enable == nil {
+		return false
+	}
+	return *i.Enable
+}
+
 // AiConfig ai config
 type AiConfig struct {
 	Enable *bool `json:"enable"`
@@ -16,3 +16,22 @@ type At struct {
 	AtUserIds []string `json:"atUserIds"`
 	IsAtAll   bool     `json:"isAtAll"`
 }
+
+// DingActionCardMsg 钉钉 ActionCard 消息，按钮仅支持跳转链接
+type DingActionCardMsg struct {
+	Msgtype    string         `json:"msgtype"`
+	ActionCard DingActionCard `json:"actionCard"`
+	At         At             `json:"at"`
+}
+
+type DingActionCard struct {
+	Title          string              `json:"title"`
+	Text           string              `json:"text"`
+	BtnOrientation string              `json:"btnOrientation"`
+	Btns           []DingActionCardBtn `json:"btns"`
+}
+
+type DingActionCardBtn struct {
+	Title     string `json:"title"`
+	ActionURL string `json:"actionURL"`
+}
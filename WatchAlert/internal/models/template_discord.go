@@ -0,0 +1,13 @@
+package models
+
+// DiscordMsgTemplate Discord Webhook 消息
+type DiscordMsgTemplate struct {
+	Content string         `json:"content,omitempty"`
+	Embeds  []DiscordEmbed `json:"embeds,omitempty"`
+}
+
+type DiscordEmbed struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Color       int    `json:"color"`
+}
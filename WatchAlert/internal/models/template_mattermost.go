@@ -0,0 +1,14 @@
+package models
+
+// MattermostMsgTemplate Mattermost Incoming Webhook 消息
+type MattermostMsgTemplate struct {
+	Text        string                 `json:"text,omitempty"`
+	Attachments []MattermostAttachment `json:"attachments,omitempty"`
+}
+
+type MattermostAttachment struct {
+	Fallback string `json:"fallback"`
+	Color    string `json:"color"`
+	Title    string `json:"title"`
+	Text     string `json:"text"`
+}
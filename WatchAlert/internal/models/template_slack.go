@@ -1,5 +1,7 @@
 package models
 
 type SlackMsgTemplate struct {
-	Text string `json:"text"`
+	Text            string                   `json:"text"`
+	Blocks          []map[string]interface{} `json:"blocks,omitempty"`
+	ReplaceOriginal bool                     `json:"replace_original,omitempty"`
 }
@@ -0,0 +1,9 @@
+package models
+
+// TelegramMsgTemplate Telegram Bot sendMessage 消息
+type TelegramMsgTemplate struct {
+	ChatId                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode,omitempty"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
@@ -43,6 +43,9 @@ type (
 		Knowledge() InterKnowledgeRepo
 		AssignmentRule() InterAssignmentRuleRepo
 		AlertTicketRule() InterAlertTicketRuleRepo
+		Integration() InterIntegrationRepo
+		EscalationPolicy() InterEscalationPolicyRepo
+		EventTimeline() InterEventTimelineRepo
 	}
 )
 
@@ -101,3 +104,12 @@ func (e *entryRepo) AssignmentRule() InterAssignmentRuleRepo {
 func (e *entryRepo) AlertTicketRule() InterAlertTicketRuleRepo {
 	return newAlertTicketRuleInterface(e.db, e.g)
 }
+func (e *entryRepo) Integration() InterIntegrationRepo {
+	return newIntegrationInterface(e.db, e.g)
+}
+func (e *entryRepo) EscalationPolicy() InterEscalationPolicyRepo {
+	return newEscalationPolicyInterface(e.db, e.g)
+}
+func (e *entryRepo) EventTimeline() InterEventTimelineRepo {
+	return newEventTimelineInterface(e.db, e.g)
+}
@@ -0,0 +1,107 @@
+package repo
+
+import (
+	"gorm.io/gorm"
+	"watchAlert/internal/models"
+)
+
+type (
+	escalationPolicyRepo struct {
+		entryRepo
+	}
+
+	InterEscalationPolicyRepo interface {
+		Create(params models.EscalationPolicy) error
+		Update(params models.EscalationPolicy) error
+		Delete(tenantId, id string) error
+		List(tenantId, query string) ([]models.EscalationPolicy, error)
+		Get(tenantId, id string) (models.EscalationPolicy, error)
+	}
+)
+
+func newEscalationPolicyInterface(db *gorm.DB, g InterGormDBCli) InterEscalationPolicyRepo {
+	return &escalationPolicyRepo{
+		entryRepo{
+			g:  g,
+			db: db,
+		},
+	}
+}
+
+func (e escalationPolicyRepo) Create(params models.EscalationPolicy) error {
+	err := e.g.Create(&models.EscalationPolicy{}, params)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (e escalationPolicyRepo) Update(params models.EscalationPolicy) error {
+	u := Updates{
+		Table: &models.EscalationPolicy{},
+		Where: map[string]interface{}{
+			"tenant_id = ?": params.TenantId,
+			"id = ?":        params.ID,
+		},
+		Updates: params,
+	}
+	err := e.g.Updates(u)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (e escalationPolicyRepo) Delete(tenantId, id string) error {
+	del := Delete{
+		Table: &models.EscalationPolicy{},
+		Where: map[string]interface{}{
+			"tenant_id = ?": tenantId,
+			"id = ?":        id,
+		},
+	}
+	err := e.g.Delete(del)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (e escalationPolicyRepo) List(tenantId, query string) ([]models.EscalationPolicy, error) {
+	var (
+		data []models.EscalationPolicy
+		db   = e.db.Model(&models.EscalationPolicy{})
+	)
+
+	if tenantId != "" {
+		db.Where("tenant_id = ?", tenantId)
+	}
+	if query != "" {
+		db.Where("name LIKE ? OR id LIKE ? OR description LIKE ?", "%"+query+"%", "%"+query+"%", "%"+query+"%")
+	}
+
+	err := db.Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (e escalationPolicyRepo) Get(tenantId, id string) (models.EscalationPolicy, error) {
+	var (
+		data models.EscalationPolicy
+		db   = e.db.Model(&models.EscalationPolicy{})
+	)
+
+	if tenantId != "" {
+		db.Where("tenant_id = ?", tenantId)
+	}
+	db.Where("id = ?", id)
+
+	err := db.First(&data).Error
+	if err != nil {
+		return data, err
+	}
+	return data, nil
+}
@@ -0,0 +1,56 @@
+package repo
+
+import (
+	"gorm.io/gorm"
+	"watchAlert/internal/models"
+)
+
+type (
+	eventTimelineRepo struct {
+		entryRepo
+	}
+
+	InterEventTimelineRepo interface {
+		Create(params models.AlertEventTimeline) error
+		List(tenantId, eventId, fingerprint string) ([]models.AlertEventTimeline, error)
+	}
+)
+
+func newEventTimelineInterface(db *gorm.DB, g InterGormDBCli) InterEventTimelineRepo {
+	return &eventTimelineRepo{
+		entryRepo{
+			g:  g,
+			db: db,
+		},
+	}
+}
+
+func (e eventTimelineRepo) Create(params models.AlertEventTimeline) error {
+	err := e.g.Create(&models.AlertEventTimeline{}, &params)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// List 按时间顺序获取事件时间线，优先按事件ID查询
+func (e eventTimelineRepo) List(tenantId, eventId, fingerprint string) ([]models.AlertEventTimeline, error) {
+	var (
+		data []models.AlertEventTimeline
+		db   = e.db.Model(&models.AlertEventTimeline{})
+	)
+
+	db.Where("tenant_id = ?", tenantId)
+	if eventId != "" {
+		db.Where("event_id = ?", eventId)
+	} else {
+		db.Where("fingerprint = ?", fingerprint)
+	}
+
+	err := db.Order("create_at ASC, id ASC").Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
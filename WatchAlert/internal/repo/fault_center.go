@@ -17,6 +17,7 @@ type (
 		List(tenantId, query string) ([]models.FaultCenter, error)
 		Get(tenantId, id, name string) (models.FaultCenter, error)
 		Reset(tenantId, id, name, description, aggregationType string) error
+		SetAlertmanagerToken(tenantId, id, token string) error
 	}
 )
 
@@ -50,7 +51,11 @@ func (f faultCenterRepo) Update(params models.FaultCenter) error {
 	if err != nil {
 		return err
 	}
-	return nil
+
+	// 升级策略允许清空，Updates 会忽略零值，需单独更新
+	return f.db.Model(&models.FaultCenter{}).
+		Where("tenant_id = ? AND id = ?", params.TenantId, params.ID).
+		Update("escalationPolicyId", params.EscalationPolicyId).Error
 }
 
 func (f faultCenterRepo) Delete(tenantId, id string) error {
@@ -141,3 +146,10 @@ func (f faultCenterRepo) Reset(tenantId, id, name, description, aggregationType
 
 	return nil
 }
+
+// SetAlertmanagerToken 更新 Alertmanager 接入 Token
+func (f faultCenterRepo) SetAlertmanagerToken(tenantId, id, token string) error {
+	return f.db.Model(&models.FaultCenter{}).
+		Where("tenant_id = ? AND id = ?", tenantId, id).
+		Update("alertmanagerToken", token).Error
+}
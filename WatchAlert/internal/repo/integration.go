@@ -0,0 +1,110 @@
+package repo
+
+import (
+	"gorm.io/gorm"
+	"watchAlert/internal/models"
+)
+
+type (
+	integrationRepo struct {
+		entryRepo
+	}
+
+	InterIntegrationRepo interface {
+		Create(params models.Integration) error
+		Update(params models.Integration) error
+		Delete(tenantId, id string) error
+		List(tenantId, faultCenterId, query string) ([]models.Integration, error)
+		Get(tenantId, id string) (models.Integration, error)
+	}
+)
+
+func newIntegrationInterface(db *gorm.DB, g InterGormDBCli) InterIntegrationRepo {
+	return &integrationRepo{
+		entryRepo{
+			g:  g,
+			db: db,
+		},
+	}
+}
+
+func (i integrationRepo) Create(params models.Integration) error {
+	err := i.g.Create(&models.Integration{}, params)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i integrationRepo) Update(params models.Integration) error {
+	u := Updates{
+		Table: &models.Integration{},
+		Where: map[string]interface{}{
+			"tenant_id = ?": params.TenantId,
+			"id = ?":        params.ID,
+		},
+		Updates: params,
+	}
+	err := i.g.Updates(u)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i integrationRepo) Delete(tenantId, id string) error {
+	del := Delete{
+		Table: &models.Integration{},
+		Where: map[string]interface{}{
+			"tenant_id = ?": tenantId,
+			"id = ?":        id,
+		},
+	}
+	err := i.g.Delete(del)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i integrationRepo) List(tenantId, faultCenterId, query string) ([]models.Integration, error) {
+	var (
+		data []models.Integration
+		db   = i.db.Model(&models.Integration{})
+	)
+
+	if tenantId != "" {
+		db.Where("tenant_id = ?", tenantId)
+	}
+	if faultCenterId != "" {
+		db.Where("fault_center_id = ?", faultCenterId)
+	}
+	if query != "" {
+		db.Where("name LIKE ? OR id LIKE ? OR description LIKE ?", "%"+query+"%", "%"+query+"%", "%"+query+"%")
+	}
+
+	err := db.Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (i integrationRepo) Get(tenantId, id string) (models.Integration, error) {
+	var (
+		data models.Integration
+		db   = i.db.Model(&models.Integration{})
+	)
+
+	if tenantId != "" {
+		db.Where("tenant_id = ?", tenantId)
+	}
+	db.Where("id = ?", id)
+
+	err := db.First(&data).Error
+	if err != nil {
+		return data, err
+	}
+	return data, nil
+}
@@ -19,9 +19,16 @@ type (
 		Delete(tenantId, ruleId string) error
 		List(tenantId, ruleType, query string) ([]models.ProbingRule, error)
 		Search(tenantId, ruleId string) (models.ProbingRule, error)
+		ListEnabled() ([]models.ProbingRule, error)
 		AddRecord(history models.ProbingHistory) error
-		GetRecord(ruleId string, dateRange int64) ([]models.ProbingHistory, error)
-		DeleteRecord() error
+		GetRecord(ruleId, location string, dateRange int64) ([]models.ProbingHistory, error)
+		GetStepRecord(ruleId, location, step string, dateRange int64) ([]models.ProbingHistory, error)
+		ListRecords(ruleId string, start, end int64) ([]models.ProbingHistory, error)
+		ListRecordRuleIds(before int64) ([]string, error)
+		DeleteRecord(ruleId string, before int64) error
+		ReplaceRecordsWithRollups(ruleId string, bounds map[string]int64, rollups []models.ProbingHistoryRollup) error
+		ListRollups(ruleId string, start, end int64) ([]models.ProbingHistoryRollup, error)
+		DeleteRollup(before int64) error
 		ChangeState(tenantId, ruleId string, state *bool) error
 	}
 )
@@ -123,6 +130,16 @@ func (p ProbingRepo) Search(tenantId, ruleId string) (models.ProbingRule, error)
 	return data, nil
 }
 
+// ListEnabled 获取所有租户已启用的拨测规则
+func (p ProbingRepo) ListEnabled() ([]models.ProbingRule, error) {
+	var data []models.ProbingRule
+	err := p.db.Model(&models.ProbingRule{}).Where("enabled = ?", true).Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
 func (p ProbingRepo) AddRecord(history models.ProbingHistory) error {
 	err := p.g.Create(models.ProbingHistory{}, history)
 	if err != nil {
@@ -132,7 +149,7 @@ func (p ProbingRepo) AddRecord(history models.ProbingHistory) error {
 	return nil
 }
 
-func (p ProbingRepo) GetRecord(ruleId string, dateRange int64) ([]models.ProbingHistory, error) {
+func (p ProbingRepo) GetRecord(ruleId, location string, dateRange int64) ([]models.ProbingHistory, error) {
 	var (
 		data []models.ProbingHistory
 		db   = p.db.Model(&models.ProbingHistory{})
@@ -142,8 +159,12 @@ func (p ProbingRepo) GetRecord(ruleId string, dateRange int64) ([]models.Probing
 	now := time.Now().Unix()
 	startTime := now - dateRange
 
-	db.Where("rule_id = ?", ruleId).
+	// 仅返回整体拨测结果，HTTP 事务的步骤明细通过 GetStepRecord 查询
+	db.Where("rule_id = ? AND step = ?", ruleId, "").
 		Where("timestamp BETWEEN ? AND ?", startTime, now)
+	if location != "" {
+		db.Where("location = ?", location)
+	}
 
 	err := db.Find(&data).Error
 	if err != nil {
@@ -156,16 +177,121 @@ func (p ProbingRepo) GetRecord(ruleId string, dateRange int64) ([]models.Probing
 	return data, nil
 }
 
-func (p ProbingRepo) DeleteRecord() error {
-	var saveDays int64 = 3600 * 24
+// GetStepRecord 获取 HTTP 事务的步骤明细记录，step 为空时返回全部步骤
+func (p ProbingRepo) GetStepRecord(ruleId, location, step string, dateRange int64) ([]models.ProbingHistory, error) {
+	var (
+		data []models.ProbingHistory
+		db   = p.db.Model(&models.ProbingHistory{})
+	)
 
 	now := time.Now().Unix()
-	startTime := now - saveDays
+	startTime := now - dateRange
+
+	db.Where("rule_id = ? AND step <> ?", ruleId, "").
+		Where("timestamp BETWEEN ? AND ?", startTime, now)
+	if step != "" {
+		db.Where("step = ?", step)
+	}
+	if location != "" {
+		db.Where("location = ?", location)
+	}
+
+	err := db.Order("timestamp").Find(&data).Error
+	if err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
+
+// ListRecords 获取时间范围 [start, end) 内的整体拨测结果，不含事务步骤明细，按时间排序
+func (p ProbingRepo) ListRecords(ruleId string, start, end int64) ([]models.ProbingHistory, error) {
+	var data []models.ProbingHistory
+	err := p.db.Model(&models.ProbingHistory{}).
+		Where("rule_id = ? AND step = ?", ruleId, "").
+		Where("timestamp >= ? AND timestamp < ?", start, end).
+		Order("timestamp").
+		Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ListRecordRuleIds 获取存在早于 before 的拨测记录的规则
+func (p ProbingRepo) ListRecordRuleIds(before int64) ([]string, error) {
+	var ruleIds []string
+	err := p.db.Model(&models.ProbingHistory{}).
+		Where("timestamp < ?", before).
+		Distinct().
+		Pluck("rule_id", &ruleIds).Error
+	if err != nil {
+		return nil, err
+	}
+	return ruleIds, nil
+}
 
+// DeleteRecord 删除规则早于 before 的原始记录
+func (p ProbingRepo) DeleteRecord(ruleId string, before int64) error {
 	del := Delete{
 		Table: &models.ProbingHistory{},
 		Where: map[string]interface{}{
-			"timestamp < ?": startTime,
+			"rule_id = ?":   ruleId,
+			"timestamp < ?": before,
+		},
+	}
+	err := p.g.Delete(del)
+	if err != nil {
+		logc.Errorf(context.Background(), err.Error())
+		return err
+	}
+	return nil
+}
+
+// ReplaceRecordsWithRollups 在同一事务中写入小时聚合数据，并按位置删除早于 bounds 截止时间的原始记录（含事务步骤明细），
+// 避免中途失败后原始记录被重复聚合
+func (p ProbingRepo) ReplaceRecordsWithRollups(ruleId string, bounds map[string]int64, rollups []models.ProbingHistoryRollup) error {
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		if len(rollups) > 0 {
+			if err := tx.CreateInBatches(rollups, 500).Error; err != nil {
+				return err
+			}
+		}
+		for location, before := range bounds {
+			err := tx.Where("rule_id = ? AND location = ? AND timestamp < ?", ruleId, location, before).
+				Delete(&models.ProbingHistory{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logc.Errorf(context.Background(), err.Error())
+		return err
+	}
+	return nil
+}
+
+// ListRollups 获取时间范围 [start, end) 内的小时聚合数据
+func (p ProbingRepo) ListRollups(ruleId string, start, end int64) ([]models.ProbingHistoryRollup, error) {
+	var data []models.ProbingHistoryRollup
+	err := p.db.Model(&models.ProbingHistoryRollup{}).
+		Where("rule_id = ?", ruleId).
+		Where("timestamp >= ? AND timestamp < ?", start, end).
+		Order("timestamp").
+		Find(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (p ProbingRepo) DeleteRollup(before int64) error {
+	del := Delete{
+		Table: &models.ProbingHistoryRollup{},
+		Where: map[string]interface{}{
+			"timestamp < ?": before,
 		},
 	}
 	err := p.g.Delete(del)
@@ -19,6 +19,7 @@ type (
 		Delete(tenantId, ruleId string) error
 		GetRuleIsExist(ruleId string) bool
 		GetRuleObject(ruleId string) models.AlertRule
+		GetTenantRule(tenantId, ruleId string) (models.AlertRule, error)
 		ChangeStatus(tenantId, ruleGroupId, ruleId string, state *bool) error
 	}
 )
@@ -173,6 +174,16 @@ func (rr RuleRepo) GetRuleObject(ruleId string) models.AlertRule {
 	return data
 }
 
+// GetTenantRule 获取租户下的规则，不限定规则组
+func (rr RuleRepo) GetTenantRule(tenantId, ruleId string) (models.AlertRule, error) {
+	var data models.AlertRule
+	err := rr.DB().Model(&models.AlertRule{}).
+		Where("tenant_id = ? AND rule_id = ?", tenantId, ruleId).
+		First(&data).Error
+
+	return data, err
+}
+
 func (rr RuleRepo) ChangeStatus(tenantId, ruleGroupId, ruleId string, state *bool) error {
 	return rr.DB().Model(&models.AlertRule{}).
 		Where("tenant_id = ? AND rule_group_id = ? AND rule_id = ?", tenantId, ruleGroupId, ruleId).
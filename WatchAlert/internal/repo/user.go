@@ -20,6 +20,7 @@ type (
 	InterUserRepo interface {
 		List(query, joinDuty, tenantId string) ([]models.Member, error)
 		Get(userId, username, query string) (models.Member, bool, error)
+		GetByDutyUserId(dutyUserId string) (models.Member, bool, error)
 		Create(r models.Member) error
 		Update(r models.Member) error
 		Delete(userId string) error
@@ -91,6 +92,24 @@ func (ur UserRepo) Get(userId, username, query string) (models.Member, bool, err
 	return data, true, nil
 }
 
+// GetByDutyUserId 根据 IM 用户ID 获取用户
+func (ur UserRepo) GetByDutyUserId(dutyUserId string) (models.Member, bool, error) {
+	var data models.Member
+	if dutyUserId == "" {
+		return data, false, fmt.Errorf("用户不存在")
+	}
+
+	err := ur.db.Model(models.Member{}).Where("duty_user_id = ?", dutyUserId).First(&data).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return data, false, fmt.Errorf("用户不存在")
+		}
+		return data, false, err
+	}
+
+	return data, true, nil
+}
+
 func (ur UserRepo) Create(r models.Member) error {
 	err := ur.g.Create(&models.Member{}, &r)
 	if err != nil {
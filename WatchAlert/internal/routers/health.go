@@ -2,12 +2,17 @@ package routers
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"net/http"
 )
 
+var metricsHandler = promhttp.Handler()
+
 func HealthCheck(gin *gin.Engine) {
 
 	gin.GET("hello", health)
+	// Prometheus 指标
+	gin.GET("metrics", metrics)
 
 }
 
@@ -18,3 +23,9 @@ func health(ctx *gin.Context) {
 	})
 
 }
+
+func metrics(ctx *gin.Context) {
+
+	metricsHandler.ServeHTTP(ctx.Writer, ctx.Request)
+
+}
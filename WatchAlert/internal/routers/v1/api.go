@@ -44,6 +44,7 @@ func Router(engine *gin.Engine) {
 			api.KubernetesTypesController.API(w8t)
 			api.SubscribeController.API(w8t)
 			api.ProbingController.API(w8t)
+			api.ProbingAgentController.API(w8t)
 			api.FaultCenterController.API(w8t)
 			api.AiController.API(w8t)
 			api.TicketController.API(w8t)
@@ -54,6 +55,10 @@ func Router(engine *gin.Engine) {
 			api.AlertTicketRuleController.API(w8t)
 			api.WechatController.API(w8t)
 			api.DebugController.API(w8t)
+			api.AlertmanagerController.API(w8t)
+			api.IntegrationController.API(w8t)
+			api.ImActionController.API(w8t)
+			api.EscalationPolicyController.API(w8t)
 		}
 
 		oidc := v1.Group("oidc")
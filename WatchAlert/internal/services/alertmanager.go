@@ -0,0 +1,134 @@
+package services
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"sort"
+	"strings"
+	"watchAlert/alert/process"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/provider"
+)
+
+type (
+	alertmanagerService struct {
+		ctx *ctx.Context
+	}
+
+	InterAlertmanagerService interface {
+		Webhook(req interface{}) (interface{}, interface{})
+		PushAlerts(req interface{}) (interface{}, interface{})
+	}
+)
+
+func newInterAlertmanagerService(ctx *ctx.Context) InterAlertmanagerService {
+	return &alertmanagerService{
+		ctx: ctx,
+	}
+}
+
+// Webhook 接收 Alertmanager v4 webhook 推送
+func (a alertmanagerService) Webhook(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestAlertmanagerWebhook)
+	return a.ingest(r.TenantId, r.FaultCenterId, r.Token, r.Alerts)
+}
+
+// PushAlerts 接收 /api/v2/alerts 格式的推送
+func (a alertmanagerService) PushAlerts(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestAlertmanagerPush)
+	return a.ingest(r.TenantId, r.FaultCenterId, r.Token, r.Alerts)
+}
+
+func (a alertmanagerService) ingest(tenantId, faultCenterId, token string, alerts []types.AlertmanagerAlert) (interface{}, interface{}) {
+	if tenantId == "" || faultCenterId == "" {
+		return nil, fmt.Errorf("租户ID和故障中心ID不能为空")
+	}
+
+	faultCenter, err := a.ctx.DB.FaultCenter().Get(tenantId, faultCenterId, "")
+	if err != nil {
+		return nil, fmt.Errorf("故障中心 %s 不存在", faultCenterId)
+	}
+
+	// 未生成 Token 的故障中心不允许接入
+	if faultCenter.AlertmanagerToken == "" {
+		return nil, fmt.Errorf("故障中心 %s 未生成 Alertmanager 接入 Token", faultCenterId)
+	}
+	if subtle.ConstantTimeCompare([]byte(faultCenter.AlertmanagerToken), []byte(token)) != 1 {
+		return nil, fmt.Errorf("Token 校验失败")
+	}
+
+	for _, alert := range alerts {
+		if len(alert.Labels) == 0 {
+			continue
+		}
+
+		event := a.buildEvent(faultCenter, alert)
+		if alert.IsResolved() {
+			process.RecoverEvent(a.ctx, event)
+			continue
+		}
+
+		process.PushEventToFaultCenter(a.ctx, event)
+	}
+
+	return nil, nil
+}
+
+// buildEvent 将 Alertmanager 告警转换为故障中心事件
+func (a alertmanagerService) buildEvent(faultCenter models.FaultCenter, alert types.AlertmanagerAlert) *models.AlertCurEvent {
+	labels := make(map[string]interface{}, len(alert.Labels))
+	for k, v := range alert.Labels {
+		labels[k] = v
+	}
+
+	alertName := alert.Labels["alertname"]
+	// 指纹只依赖标签，保证 firing 与 resolved 推送命中同一事件
+	event := &models.AlertCurEvent{
+		TenantId:             faultCenter.TenantId,
+		DatasourceType:       types.AlertmanagerDatasourceType,
+		RuleId:               "alertmanager-" + alertName,
+		RuleName:             alertName,
+		Fingerprint:          provider.Metrics{Metric: labels}.GetFingerprint(),
+		Severity:             convertAlertmanagerSeverity(alert.Labels["severity"]),
+		Labels:               labels,
+		Annotations:          formatAlertmanagerAnnotations(alert.Annotations),
+		RepeatNoticeInterval: faultCenter.RepeatNoticeInterval,
+		FaultCenterId:        faultCenter.ID,
+	}
+
+	// Alertmanager 已经处理过 for 持续时间，沿用上游的触发时间
+	if !alert.StartsAt.IsZero() {
+		event.FirstTriggerTime = alert.StartsAt.Unix()
+	}
+
+	return event
+}
+
+// convertAlertmanagerSeverity 将 Alertmanager 常用的 severity 标签映射为告警等级
+func convertAlertmanagerSeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "p0", "critical", "disaster":
+		return "P0"
+	case "p2", "info", "none":
+		return "P2"
+	default:
+		return "P1"
+	}
+}
+
+func formatAlertmanagerAnnotations(annotations map[string]string) string {
+	keys := make([]string, 0, len(annotations))
+	for k := range annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("%s: %s", k, annotations[k]))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"sort"
 	"time"
 	"watchAlert/internal/ctx"
 	"watchAlert/internal/models"
@@ -20,6 +21,7 @@ type InterDatasourceService interface {
 	Delete(req interface{}) (interface{}, interface{})
 	List(req interface{}) (interface{}, interface{})
 	Get(req interface{}) (interface{}, interface{})
+	Health(req interface{}) (interface{}, interface{})
 	WithAddClientToProviderPools(datasource models.AlertDataSource) error
 	WithRemoveClientForProviderPools(datasourceId string)
 }
@@ -44,6 +46,8 @@ func (ds datasourceService) Create(req interface{}) (interface{}, interface{}) {
 		DsAliCloudConfig: dataSource.DsAliCloudConfig,
 		AWSCloudWatch:    dataSource.AWSCloudWatch,
 		ClickHouseConfig: dataSource.ClickHouseConfig,
+		InfluxDBConfig:   dataSource.InfluxDBConfig,
+		SQLConfig:        dataSource.SQLConfig,
 		Description:      dataSource.Description,
 		KubeConfig:       dataSource.KubeConfig,
 		UpdateBy:         dataSource.UpdateBy,
@@ -78,6 +82,8 @@ func (ds datasourceService) Update(req interface{}) (interface{}, interface{}) {
 		DsAliCloudConfig: dataSource.DsAliCloudConfig,
 		AWSCloudWatch:    dataSource.AWSCloudWatch,
 		ClickHouseConfig: dataSource.ClickHouseConfig,
+		InfluxDBConfig:   dataSource.InfluxDBConfig,
+		SQLConfig:        dataSource.SQLConfig,
 		Description:      dataSource.Description,
 		KubeConfig:       dataSource.KubeConfig,
 		UpdateBy:         dataSource.UpdateBy,
@@ -132,6 +138,31 @@ func (ds datasourceService) List(req interface{}) (interface{}, interface{}) {
 	return newData, nil
 }
 
+// Health 获取数据源健康状态，按 ID、类型过滤，异常的数据源排在前面
+func (ds datasourceService) Health(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestDatasourceQuery)
+
+	var data []models.DatasourceHealth
+	for _, health := range ds.ctx.Redis.DatasourceHealth().List(r.TenantId) {
+		if r.ID != "" && health.DatasourceId != r.ID {
+			continue
+		}
+		if r.Type != "" && health.DatasourceType != r.Type {
+			continue
+		}
+		data = append(data, health)
+	}
+
+	sort.Slice(data, func(i, j int) bool {
+		if data[i].Status != data[j].Status {
+			return data[i].Status == models.DatasourceUnhealthy
+		}
+		return data[i].DatasourceName < data[j].DatasourceName
+	})
+
+	return data, nil
+}
+
 func (ds datasourceService) WithAddClientToProviderPools(datasource models.AlertDataSource) error {
 	var (
 		cli interface{}
@@ -143,6 +174,12 @@ func (ds datasourceService) WithAddClientToProviderPools(datasource models.Alert
 		cli, err = provider.NewPrometheusClient(datasource)
 	case provider.VictoriaMetricsDsProvider:
 		cli, err = provider.NewVictoriaMetricsClient(datasource)
+	case provider.GraphiteDsProvider:
+		cli, err = provider.NewGraphiteClient(datasource)
+	case provider.InfluxDBDsProvider:
+		cli, err = provider.NewInfluxDBClient(datasource)
+	case provider.OpenTSDBDsProvider:
+		cli, err = provider.NewOpenTSDBClient(datasource)
 	case provider.LokiDsProviderName:
 		cli, err = provider.NewLokiClient(datasource)
 	case provider.AliCloudSLSDsProviderName:
@@ -153,12 +190,18 @@ func (ds datasourceService) WithAddClientToProviderPools(datasource models.Alert
 		cli, err = provider.NewVictoriaLogsClient(ctx.Ctx, datasource)
 	case provider.JaegerDsProviderName:
 		cli, err = provider.NewJaegerClient(datasource)
+	case provider.TempoDsProviderName:
+		cli, err = provider.NewTempoClient(datasource)
+	case provider.ZipkinDsProviderName:
+		cli, err = provider.NewZipkinClient(datasource)
 	case "Kubernetes":
 		cli, err = provider.NewKubernetesClient(ds.ctx.Ctx, datasource.KubeConfig, datasource.Labels)
 	case "CloudWatch":
 		cli, err = provider.NewAWSCredentialCfg(datasource.AWSCloudWatch.Region, datasource.AWSCloudWatch.AccessKey, datasource.AWSCloudWatch.SecretKey, datasource.Labels)
 	case "ClickHouse":
 		cli, err = provider.NewClickHouseClient(ctx.Ctx, datasource)
+	case provider.MySQLDsProviderName, provider.PostgreSQLDsProviderName:
+		cli, err = provider.NewSQLClient(datasource)
 	}
 
 	if err != nil {
@@ -33,6 +33,7 @@ var (
 	LdapService             InterLdapService
 	SubscribeService        InterAlertSubscribeService
 	ProbingService          InterProbingService
+	ProbingAgentService     InterProbingAgentService
 	FaultCenterService      InterFaultCenterService
 	AiService               InterAiService
 	OidcService             InterOidcService
@@ -42,6 +43,10 @@ var (
 	KnowledgeService        InterKnowledgeService
 	AssignmentRuleService   InterAssignmentRuleService
 	AlertTicketService      InterAlertTicketService
+	AlertmanagerService     InterAlertmanagerService
+	IntegrationService      InterIntegrationService
+	ImActionService         InterImActionService
+	EscalationPolicyService InterEscalationPolicyService
 )
 
 func NewServices(ctx *ctx.Context) {
@@ -71,6 +76,7 @@ func NewServices(ctx *ctx.Context) {
 	LdapService = newInterLdapService(ctx)
 	SubscribeService = newInterAlertSubscribe(ctx)
 	ProbingService = newInterProbingService(ctx)
+	ProbingAgentService = newInterProbingAgentService(ctx)
 	FaultCenterService = newInterFaultCenterService(ctx)
 	AiService = newInterAiService(ctx)
 	OidcService = newInterOidcService(ctx)
@@ -80,4 +86,8 @@ func NewServices(ctx *ctx.Context) {
 	KnowledgeService = newInterKnowledgeService(ctx)
 	AssignmentRuleService = newInterAssignmentRuleService(ctx)
 	AlertTicketService = newInterAlertTicketService(ctx)
+	AlertmanagerService = newInterAlertmanagerService(ctx)
+	IntegrationService = newInterIntegrationService(ctx)
+	ImActionService = newInterImActionService(ctx)
+	EscalationPolicyService = newInterEscalationPolicyService(ctx)
 }
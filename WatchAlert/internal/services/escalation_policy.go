@@ -0,0 +1,175 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/tools"
+)
+
+type (
+	escalationPolicyService struct {
+		ctx *ctx.Context
+	}
+
+	InterEscalationPolicyService interface {
+		Create(req interface{}) (interface{}, interface{})
+		Update(req interface{}) (interface{}, interface{})
+		Delete(req interface{}) (interface{}, interface{})
+		List(req interface{}) (interface{}, interface{})
+		Get(req interface{}) (interface{}, interface{})
+	}
+)
+
+func newInterEscalationPolicyService(ctx *ctx.Context) InterEscalationPolicyService {
+	return &escalationPolicyService{
+		ctx: ctx,
+	}
+}
+
+func (e escalationPolicyService) Create(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestEscalationPolicyCreate)
+	if err := e.validateLevels(r.TenantId, r.Levels); err != nil {
+		return nil, err
+	}
+
+	data := models.EscalationPolicy{
+		TenantId:    r.TenantId,
+		ID:          "ep-" + tools.RandId(),
+		Name:        r.Name,
+		Description: r.Description,
+		Levels:      r.Levels,
+		UpdateAt:    time.Now().Unix(),
+		UpdateBy:    r.UpdateBy,
+	}
+
+	err := e.ctx.DB.EscalationPolicy().Create(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (e escalationPolicyService) Update(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestEscalationPolicyUpdate)
+	if err := e.validateLevels(r.TenantId, r.Levels); err != nil {
+		return nil, err
+	}
+
+	data := models.EscalationPolicy{
+		TenantId:    r.TenantId,
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		Levels:      r.Levels,
+		UpdateAt:    time.Now().Unix(),
+		UpdateBy:    r.UpdateBy,
+	}
+
+	err := e.ctx.DB.EscalationPolicy().Update(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (e escalationPolicyService) Delete(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestEscalationPolicyQuery)
+	faultCenters, err := e.ctx.DB.FaultCenter().List(r.TenantId, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, fc := range faultCenters {
+		if fc.EscalationPolicyId == r.ID {
+			return nil, fmt.Errorf("升级策略正在被故障中心 %s 使用, 无法删除", fc.Name)
+		}
+	}
+
+	err = e.ctx.DB.EscalationPolicy().Delete(r.TenantId, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (e escalationPolicyService) List(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestEscalationPolicyQuery)
+	data, err := e.ctx.DB.EscalationPolicy().List(r.TenantId, r.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (e escalationPolicyService) Get(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestEscalationPolicyQuery)
+	data, err := e.ctx.DB.EscalationPolicy().Get(r.TenantId, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// validateLevels 校验升级级别配置
+func (e escalationPolicyService) validateLevels(tenantId string, levels []models.EscalationLevel) error {
+	if len(levels) == 0 {
+		return fmt.Errorf("升级级别不能为空")
+	}
+
+	for i, level := range levels {
+		idx := i + 1
+		if level.Delay < 0 || level.RepeatInterval < 0 {
+			return fmt.Errorf("第 %d 级升级的等待时间及重复间隔不能小于 0", idx)
+		}
+		if level.Repeat != 0 && level.RepeatInterval == 0 {
+			return fmt.Errorf("第 %d 级升级重复通知时需设置重复间隔", idx)
+		}
+
+		switch level.GetTrigger() {
+		case models.EscalationTriggerNotAcknowledged, models.EscalationTriggerNotResolved:
+		default:
+			return fmt.Errorf("第 %d 级升级的触发条件 %s 不支持", idx, level.Trigger)
+		}
+
+		if level.NoticeId == "" {
+			return fmt.Errorf("第 %d 级升级未设置通知对象", idx)
+		}
+		notice, err := e.ctx.DB.Notice().Get(tenantId, level.NoticeId)
+		if err != nil {
+			return fmt.Errorf("第 %d 级升级的通知对象 %s 不存在", idx, level.NoticeId)
+		}
+
+		switch level.GetTargetType() {
+		case models.EscalationTargetNotice:
+		case models.EscalationTargetDuty:
+			if level.DutyId == "" {
+				return fmt.Errorf("第 %d 级升级未设置值班表", idx)
+			}
+		case models.EscalationTargetUser:
+			if len(level.UserIds) == 0 {
+				return fmt.Errorf("第 %d 级升级未设置通知用户", idx)
+			}
+			// 值班人员随排班变化，发送时再校验；指定用户需具备通知渠道所需的联系方式
+			for _, userId := range level.UserIds {
+				user, ok, _ := e.ctx.DB.User().Get(userId, "", "")
+				if !ok {
+					return fmt.Errorf("第 %d 级升级的通知用户 %s 不存在", idx, userId)
+				}
+				if models.EscalationContact(notice.NoticeType, user) == "" {
+					return fmt.Errorf("第 %d 级升级的通知用户 %s 未配置 %s 通知所需的联系方式", idx, user.UserName, notice.NoticeType)
+				}
+			}
+		default:
+			return fmt.Errorf("第 %d 级升级的通知目标类型 %s 不支持", idx, level.TargetType)
+		}
+	}
+
+	return nil
+}
@@ -27,6 +27,7 @@ type InterEventService interface {
 	ListComments(req interface{}) (interface{}, interface{})
 	AddComment(req interface{}) (interface{}, interface{})
 	DeleteComment(req interface{}) (interface{}, interface{})
+	ListTimeline(req interface{}) (interface{}, interface{})
 }
 
 func newInterEventService(ctx *ctx.Context) InterEventService {
@@ -262,3 +263,18 @@ func (e eventService) DeleteComment(req interface{}) (interface{}, interface{})
 
 	return "删除评论成功", nil
 }
+
+// ListTimeline 获取事件时间线
+func (e eventService) ListTimeline(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestListEventTimeline)
+	if r.EventId == "" && r.Fingerprint == "" {
+		return nil, fmt.Errorf("事件ID与告警指纹不能同时为空")
+	}
+
+	data, err := e.ctx.DB.EventTimeline().List(r.TenantId, r.EventId, r.Fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("获取事件时间线失败, %s", err.Error())
+	}
+
+	return data, nil
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"fmt"
 	"time"
 	"watchAlert/alert"
 	"watchAlert/internal/ctx"
@@ -23,6 +24,7 @@ type (
 		Get(req interface{}) (data interface{}, err interface{})
 		Reset(req interface{}) (data interface{}, err interface{})
 		Slo(req interface{}) (data interface{}, err interface{})
+		ResetAlertmanagerToken(req interface{}) (data interface{}, err interface{})
 	}
 )
 
@@ -34,6 +36,12 @@ func newInterFaultCenterService(ctx *ctx.Context) InterFaultCenterService {
 
 func (f faultCenterService) Create(req interface{}) (data interface{}, err interface{}) {
 	r := req.(*types.RequestFaultCenterCreate)
+	if r.EscalationPolicyId != "" {
+		if _, e := f.ctx.DB.EscalationPolicy().Get(r.TenantId, r.EscalationPolicyId); e != nil {
+			return nil, fmt.Errorf("升级策略 %s 不存在", r.EscalationPolicyId)
+		}
+	}
+
 	fc := models.FaultCenter{
 		TenantId:             r.TenantId,
 		ID:                   "fc-" + tools.RandId(),
@@ -49,6 +57,10 @@ func (f faultCenterService) Create(req interface{}) (data interface{}, err inter
 		IsUpgradeEnabled:     r.IsUpgradeEnabled,
 		UpgradableSeverity:   r.UpgradableSeverity,
 		UpgradeStrategy:      r.UpgradeStrategy,
+		EscalationPolicyId:   r.EscalationPolicyId,
+		InhibitRules:         r.InhibitRules,
+		GroupStrategy:        r.GroupStrategy,
+		FlapDetection:        r.FlapDetection,
 	}
 
 	err = f.ctx.DB.FaultCenter().Create(fc)
@@ -77,6 +89,12 @@ func (f faultCenterService) Create(req interface{}) (data interface{}, err inter
 
 func (f faultCenterService) Update(req interface{}) (data interface{}, err interface{}) {
 	r := req.(*types.RequestFaultCenterUpdate)
+	if r.EscalationPolicyId != "" {
+		if _, e := f.ctx.DB.EscalationPolicy().Get(r.TenantId, r.EscalationPolicyId); e != nil {
+			return nil, fmt.Errorf("升级策略 %s 不存在", r.EscalationPolicyId)
+		}
+	}
+
 	fc := models.FaultCenter{
 		TenantId:             r.TenantId,
 		ID:                   r.ID,
@@ -92,6 +110,10 @@ func (f faultCenterService) Update(req interface{}) (data interface{}, err inter
 		IsUpgradeEnabled:     r.IsUpgradeEnabled,
 		UpgradableSeverity:   r.UpgradableSeverity,
 		UpgradeStrategy:      r.UpgradeStrategy,
+		EscalationPolicyId:   r.EscalationPolicyId,
+		InhibitRules:         r.InhibitRules,
+		GroupStrategy:        r.GroupStrategy,
+		FlapDetection:        r.FlapDetection,
 	}
 
 	err = f.ctx.DB.FaultCenter().Update(fc)
@@ -172,6 +194,8 @@ func (f faultCenterService) List(req interface{}) (data interface{}, err interfa
 				faultCenters[index].CurrentMuteNumber++
 			case models.StatePendingRecovery:
 				faultCenters[index].CurrentRecoverNumber++
+			case models.StateInhibited:
+				faultCenters[index].CurrentInhibitNumber++
 			}
 		}
 	}
@@ -219,6 +243,22 @@ func (f faultCenterService) Reset(req interface{}) (data interface{}, err interf
 	return nil, nil
 }
 
+// ResetAlertmanagerToken 重新生成 Alertmanager 接入 Token，旧 Token 立即失效
+func (f faultCenterService) ResetAlertmanagerToken(req interface{}) (data interface{}, err interface{}) {
+	r := req.(*types.RequestFaultCenterQuery)
+	if _, e := f.ctx.DB.FaultCenter().Get(r.TenantId, r.ID, ""); e != nil {
+		return nil, fmt.Errorf("故障中心 %s 不存在", r.ID)
+	}
+
+	token := newIntegrationToken()
+	err = f.ctx.DB.FaultCenter().SetAlertmanagerToken(r.TenantId, r.ID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{"alertmanagerToken": token}, nil
+}
+
 func (f faultCenterService) Slo(req interface{}) (data interface{}, err interface{}) {
 	r := req.(*types.RequestFaultCenterQuery)
 	// 拉取近7天的历史事件（一次性拉全量，后面按天聚合）
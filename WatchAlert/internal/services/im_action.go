@@ -0,0 +1,355 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"watchAlert/alert/process"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/global"
+	"watchAlert/internal/models"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/templates"
+	"watchAlert/pkg/tools"
+
+	"github.com/bytedance/sonic"
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// IM 回调请求有效期，单位（秒）
+const imActionCallbackExpire = 300
+
+type (
+	imActionService struct {
+		ctx *ctx.Context
+	}
+
+	InterImActionService interface {
+		FeiShuCallback(req interface{}) (interface{}, interface{})
+		SlackCallback(req interface{}) (interface{}, interface{})
+		DingDingCallback(req interface{}) (interface{}, interface{})
+	}
+)
+
+func newInterImActionService(ctx *ctx.Context) InterImActionService {
+	return &imActionService{
+		ctx: ctx,
+	}
+}
+
+// FeiShuCallback 飞书卡片按钮回调，返回更新后的卡片
+func (s imActionService) FeiShuCallback(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestImFeiShuCallback)
+	setting, err := s.ctx.DB.Setting().Get()
+	if err != nil {
+		return nil, err
+	}
+	config := setting.ImActionConfig
+
+	var body types.ImFeiShuCardAction
+	if err := sonic.Unmarshal(r.Body, &body); err != nil {
+		return nil, fmt.Errorf("解析飞书回调失败, %s", err.Error())
+	}
+
+	if config.FeiShuVerificationToken == "" || body.Token != config.FeiShuVerificationToken {
+		return nil, fmt.Errorf("飞书回调 Token 校验失败")
+	}
+
+	// 配置回调地址时的校验请求
+	if body.Type == "url_verification" {
+		return map[string]string{"challenge": body.Challenge}, nil
+	}
+
+	if !imActionFresh(r.Timestamp, imActionCallbackExpire) {
+		return nil, fmt.Errorf("飞书回调请求已过期")
+	}
+
+	h := sha1.New()
+	h.Write([]byte(r.Timestamp + r.Nonce + config.FeiShuVerificationToken))
+	h.Write(r.Body)
+	if !hmac.Equal([]byte(hex.EncodeToString(h.Sum(nil))), []byte(r.Signature)) {
+		return nil, fmt.Errorf("飞书回调签名校验失败")
+	}
+
+	member, ok, _ := s.ctx.DB.User().GetByDutyUserId(body.UserID)
+	if !ok {
+		member, ok, _ = s.ctx.DB.User().GetByDutyUserId(body.OpenID)
+	}
+	if !ok {
+		return nil, fmt.Errorf("未找到绑定该飞书账号的用户")
+	}
+
+	event, notice, result, err := s.executeImAction(body.Action.Value, member)
+	if err != nil {
+		return nil, err
+	}
+
+	return templates.ImActionResultCard(s.ctx, event, notice, result), nil
+}
+
+// SlackCallback Slack block_actions 回调，通过 response_url 更新原消息
+func (s imActionService) SlackCallback(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestImSlackCallback)
+	setting, err := s.ctx.DB.Setting().Get()
+	if err != nil {
+		return nil, err
+	}
+	config := setting.ImActionConfig
+
+	if !imActionFresh(r.Timestamp, imActionCallbackExpire) {
+		return nil, fmt.Errorf("Slack 回调请求已过期")
+	}
+
+	mac := hmac.New(sha256.New, []byte(config.SlackSigningSecret))
+	mac.Write([]byte("v0:" + r.Timestamp + ":"))
+	mac.Write(r.Body)
+	if config.SlackSigningSecret == "" || !hmac.Equal([]byte("v0="+hex.EncodeToString(mac.Sum(nil))), []byte(r.Signature)) {
+		return nil, fmt.Errorf("Slack 回调签名校验失败")
+	}
+
+	form, err := url.ParseQuery(string(r.Body))
+	if err != nil {
+		return nil, fmt.Errorf("解析 Slack 回调失败, %s", err.Error())
+	}
+
+	var payload types.ImSlackBlockAction
+	if err := sonic.UnmarshalString(form.Get("payload"), &payload); err != nil {
+		return nil, fmt.Errorf("解析 Slack 回调失败, %s", err.Error())
+	}
+	if len(payload.Actions) == 0 {
+		return nil, nil
+	}
+
+	var value models.ImActionValue
+	if err := sonic.UnmarshalString(payload.Actions[0].Value, &value); err != nil {
+		return nil, fmt.Errorf("解析 Slack 按钮失败, %s", err.Error())
+	}
+
+	member, ok, _ := s.ctx.DB.User().GetByDutyUserId(payload.User.Id)
+	if !ok {
+		return nil, fmt.Errorf("未找到绑定该 Slack 账号的用户")
+	}
+
+	event, notice, result, err := s.executeImAction(value, member)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.ResponseUrl != "" {
+		msg := templates.ImActionResultCard(s.ctx, event, notice, result)
+		res, err := tools.Post(nil, payload.ResponseUrl, bytes.NewReader([]byte(msg)), 10)
+		if err != nil {
+			logc.Errorf(s.ctx.Ctx, "更新 Slack 消息失败, %s", err.Error())
+		} else {
+			res.Body.Close()
+		}
+	}
+
+	return nil, nil
+}
+
+// DingDingCallback 钉钉企业内部机器人 outgoing 回调，卡片按钮以点击者身份发送「操作 指令」消息，
+// 回调中的 senderStaffId 即为操作人，返回内容作为机器人回复。群聊中需 @机器人 才会触发回调
+func (s imActionService) DingDingCallback(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestImDingDingCallback)
+	setting, err := s.ctx.DB.Setting().Get()
+	if err != nil {
+		return nil, err
+	}
+	secret := setting.ImActionConfig.DingDingAppSecret
+
+	// 钉钉回调时间戳为毫秒，有效期 1 小时
+	ts, err := strconv.ParseInt(r.Timestamp, 10, 64)
+	if err != nil || !imActionFresh(strconv.FormatInt(ts/1000, 10), 3600) {
+		return nil, fmt.Errorf("钉钉回调请求已过期")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(r.Timestamp + "\n" + secret))
+	if secret == "" || !hmac.Equal([]byte(base64.StdEncoding.EncodeToString(mac.Sum(nil))), []byte(r.Sign)) {
+		return nil, fmt.Errorf("钉钉回调签名校验失败")
+	}
+
+	var body types.ImDingDingOutgoing
+	if err := sonic.Unmarshal(r.Body, &body); err != nil {
+		return nil, fmt.Errorf("解析钉钉回调失败, %s", err.Error())
+	}
+
+	fields := strings.Fields(body.Text.Content)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("未识别的操作指令")
+	}
+	value, err := models.DecodeImActionValue(fields[len(fields)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	member, ok, _ := s.ctx.DB.User().GetByDutyUserId(body.SenderStaffId)
+	if !ok {
+		return nil, fmt.Errorf("未找到绑定该钉钉账号的用户")
+	}
+
+	_, _, result, err := s.executeImAction(value, member)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// executeImAction 执行卡片按钮对应的操作
+func (s imActionService) executeImAction(value models.ImActionValue, member models.Member) (models.AlertCurEvent, models.AlertNotice, string, error) {
+	var (
+		event  models.AlertCurEvent
+		notice models.AlertNotice
+	)
+
+	if err := value.Verify(global.StSignKey); err != nil {
+		return event, notice, "", err
+	}
+
+	operator := member.UserName
+	if !slices.Contains(member.Tenants, value.TenantId) {
+		return event, notice, "", fmt.Errorf("用户 %s 无该租户的操作权限", member.UserName)
+	}
+
+	event, err := s.ctx.Redis.Alert().GetEventFromCache(value.TenantId, value.FaultCenterId, value.Fingerprint)
+	if err != nil || event.Fingerprint == "" {
+		return event, notice, "", fmt.Errorf("告警事件不存在或已恢复")
+	}
+
+	notice, err = s.ctx.DB.Notice().Get(value.TenantId, value.NoticeId)
+	if err != nil {
+		return event, notice, "", fmt.Errorf("通知对象不存在")
+	}
+
+	var (
+		result  string
+		errResp interface{}
+	)
+	switch value.Action {
+	case models.ImActionClaim:
+		_, errResp = EventService.ProcessAlertEvent(&types.RequestProcessAlertEvent{
+			TenantId:      value.TenantId,
+			FaultCenterId: value.FaultCenterId,
+			Fingerprints:  []string{value.Fingerprint},
+			Time:          time.Now().Unix(),
+			Username:      operator,
+		})
+		result = fmt.Sprintf("✅ %s 已认领", operator)
+	case models.ImActionSilence:
+		now := time.Now().Unix()
+		_, errResp = SilenceService.Create(&types.RequestSilenceCreate{
+			TenantId:      value.TenantId,
+			Name:          fmt.Sprintf("%s-%s", event.RuleName, value.Fingerprint),
+			Labels:        imActionSilenceLabels(event.Labels),
+			StartsAt:      now,
+			EndsAt:        now + 3600,
+			UpdateBy:      operator,
+			FaultCenterId: value.FaultCenterId,
+			Comment:       "通过 IM 卡片静默",
+		})
+		result = fmt.Sprintf("🔕 %s 已静默 1 小时", operator)
+	case models.ImActionTicket:
+		_, errResp = TicketService.Create(&types.RequestTicketCreate{
+			TenantId:       value.TenantId,
+			Title:          event.RuleName,
+			Description:    event.Annotations,
+			Type:           models.TicketTypeAlert,
+			Priority:       imActionTicketPriority(event.Severity),
+			Source:         models.TicketSourceManual,
+			EventId:        event.EventId,
+			FaultCenterId:  event.FaultCenterId,
+			RuleId:         event.RuleId,
+			DatasourceType: event.DatasourceType,
+			CreatedBy:      member.UserId,
+		})
+		result = fmt.Sprintf("📝 %s 已创建工单", operator)
+	case models.ImActionResolve:
+		// 规则仍在触发时恢复后会立即产生新的告警，拒绝标记恢复
+		if s.isRuleFiring(event) {
+			return event, notice, "", fmt.Errorf("告警规则仍在触发，请在告警条件消除后再标记恢复")
+		}
+		process.RecoverEvent(s.ctx, &event)
+		result = fmt.Sprintf("✔️ %s 已标记恢复", operator)
+	default:
+		return event, notice, "", fmt.Errorf("不支持的操作: %s", value.Action)
+	}
+
+	if errResp != nil {
+		return event, notice, "", fmt.Errorf("%v", errResp)
+	}
+
+	logc.Infof(s.ctx.Ctx, "IM 卡片操作, action: %s, operator: %s, fingerprint: %s", value.Action, operator, value.Fingerprint)
+	return event, notice, result, nil
+}
+
+// imActionSilenceLabels 按事件标签精确匹配生成静默条件
+func imActionSilenceLabels(labels map[string]interface{}) []models.SilenceLabel {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var silenceLabels []models.SilenceLabel
+	for _, k := range keys {
+		silenceLabels = append(silenceLabels, models.SilenceLabel{
+			Key:      k,
+			Value:    "^" + regexp.QuoteMeta(fmt.Sprint(labels[k])) + "$",
+			Operator: "=",
+		})
+	}
+
+	return silenceLabels
+}
+
+func imActionTicketPriority(severity string) models.TicketPriority {
+	switch severity {
+	case "P0":
+		return models.TicketPriorityP0
+	case "P1":
+		return models.TicketPriorityP1
+	default:
+		return models.TicketPriorityP2
+	}
+}
+
+// isRuleFiring 告警规则在最近两个评估周期内仍判定为告警
+func (s imActionService) isRuleFiring(event models.AlertCurEvent) bool {
+	if event.Status != models.StateAlerting || event.RuleId == "" {
+		return false
+	}
+
+	rule := s.ctx.DB.Rule().GetRuleObject(event.RuleId)
+	interval := rule.EvalInterval
+	if rule.EvalTimeType == "millisecond" {
+		interval /= 1000
+	}
+	if interval <= 0 {
+		return false
+	}
+
+	return time.Now().Unix()-event.LastEvalTime <= interval*2
+}
+
+// imActionFresh 校验回调时间戳（秒）是否在有效期内，防止重放
+func imActionFresh(timestamp string, expire int64) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	diff := time.Now().Unix() - ts
+	return diff <= expire && diff >= -expire
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"watchAlert/alert/process"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/provider"
+	"watchAlert/pkg/tools"
+
+	"github.com/google/uuid"
+)
+
+const IntegrationDatasourceType = "Integration"
+
+type (
+	integrationService struct {
+		ctx *ctx.Context
+	}
+
+	InterIntegrationService interface {
+		Create(req interface{}) (interface{}, interface{})
+		Update(req interface{}) (interface{}, interface{})
+		Delete(req interface{}) (interface{}, interface{})
+		List(req interface{}) (interface{}, interface{})
+		Get(req interface{}) (interface{}, interface{})
+		ResetToken(req interface{}) (interface{}, interface{})
+		Push(req interface{}) (interface{}, interface{})
+	}
+)
+
+func newInterIntegrationService(ctx *ctx.Context) InterIntegrationService {
+	return &integrationService{
+		ctx: ctx,
+	}
+}
+
+func (i integrationService) Create(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestIntegrationCreate)
+	if _, err := i.ctx.DB.FaultCenter().Get(r.TenantId, r.FaultCenterId, ""); err != nil {
+		return nil, fmt.Errorf("故障中心 %s 不存在", r.FaultCenterId)
+	}
+
+	data := models.Integration{
+		TenantId:      r.TenantId,
+		ID:            "it-" + tools.RandId(),
+		Name:          r.Name,
+		Description:   r.Description,
+		FaultCenterId: r.FaultCenterId,
+		Token:         newIntegrationToken(),
+		Mapping:       r.Mapping,
+		Enabled:       r.Enabled,
+		UpdateAt:      time.Now().Unix(),
+		UpdateBy:      r.UpdateBy,
+	}
+
+	err := i.ctx.DB.Integration().Create(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (i integrationService) Update(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestIntegrationUpdate)
+	if _, err := i.ctx.DB.FaultCenter().Get(r.TenantId, r.FaultCenterId, ""); err != nil {
+		return nil, fmt.Errorf("故障中心 %s 不存在", r.FaultCenterId)
+	}
+
+	data := models.Integration{
+		TenantId:      r.TenantId,
+		ID:            r.ID,
+		Name:          r.Name,
+		Description:   r.Description,
+		FaultCenterId: r.FaultCenterId,
+		Mapping:       r.Mapping,
+		Enabled:       r.Enabled,
+		UpdateAt:      time.Now().Unix(),
+		UpdateBy:      r.UpdateBy,
+	}
+
+	err := i.ctx.DB.Integration().Update(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (i integrationService) Delete(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestIntegrationQuery)
+	err := i.ctx.DB.Integration().Delete(r.TenantId, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (i integrationService) List(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestIntegrationQuery)
+	data, err := i.ctx.DB.Integration().List(r.TenantId, r.FaultCenterId, r.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (i integrationService) Get(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestIntegrationQuery)
+	data, err := i.ctx.DB.Integration().Get(r.TenantId, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ResetToken 重新生成推送 Token，旧 Token 立即失效
+func (i integrationService) ResetToken(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestIntegrationQuery)
+	data, err := i.ctx.DB.Integration().Get(r.TenantId, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Token = newIntegrationToken()
+	err = i.ctx.DB.Integration().Update(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Push 接收外部系统推送的事件，按映射规则转换后进入故障中心状态机
+func (i integrationService) Push(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestIntegrationPush)
+	integration, err := i.ctx.DB.Integration().Get("", r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("集成 %s 不存在", r.ID)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(integration.Token), []byte(r.Token)) != 1 {
+		return nil, fmt.Errorf("Token 校验失败")
+	}
+
+	if !integration.GetEnabled() {
+		return nil, fmt.Errorf("集成 %s 未启用", integration.Name)
+	}
+
+	faultCenter, err := i.ctx.DB.FaultCenter().Get(integration.TenantId, integration.FaultCenterId, "")
+	if err != nil {
+		return nil, fmt.Errorf("故障中心 %s 不存在", integration.FaultCenterId)
+	}
+
+	var payloads []interface{}
+	mapping := integration.Mapping
+	if mapping.EventsPath == "" {
+		payloads = []interface{}{r.Payload}
+	} else {
+		list, ok := tools.GetJsonPathValue(r.Payload, mapping.EventsPath).([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("事件列表路径 %s 不是数组", mapping.EventsPath)
+		}
+		payloads = list
+	}
+
+	for _, payload := range payloads {
+		event := buildIntegrationEvent(integration, faultCenter, payload)
+		if event.Fingerprint == "" {
+			continue
+		}
+
+		if isIntegrationResolved(mapping, payload) {
+			process.RecoverEvent(i.ctx, event)
+			continue
+		}
+
+		process.PushEventToFaultCenter(i.ctx, event)
+	}
+
+	return nil, nil
+}
+
+// buildIntegrationEvent 按映射规则构建告警事件
+func buildIntegrationEvent(integration models.Integration, faultCenter models.FaultCenter, payload interface{}) *models.AlertCurEvent {
+	mapping := integration.Mapping
+
+	labels := make(map[string]interface{}, len(mapping.Labels))
+	for key, expr := range mapping.Labels {
+		value := tools.RenderJsonPathValue(expr, payload)
+		if value == "" {
+			continue
+		}
+		labels[key] = value
+	}
+
+	ruleName := tools.RenderJsonPathValue(mapping.RuleName, payload)
+	if ruleName == "" {
+		ruleName = integration.Name
+	}
+
+	// 指纹由指定的标签与集成ID计算，未指定时使用全部标签
+	fingerprintLabels := map[string]interface{}{
+		"integration_id": integration.ID,
+	}
+	for key, value := range labels {
+		if len(mapping.FingerprintKeys) == 0 || slices.Contains(mapping.FingerprintKeys, key) {
+			fingerprintLabels[key] = value
+		}
+	}
+	if len(fingerprintLabels) == 1 {
+		fingerprintLabels["rule_name"] = ruleName
+	}
+
+	return &models.AlertCurEvent{
+		TenantId:             integration.TenantId,
+		DatasourceType:       IntegrationDatasourceType,
+		DatasourceId:         integration.ID,
+		RuleId:               integration.ID,
+		RuleName:             ruleName,
+		Fingerprint:          provider.Metrics{Metric: fingerprintLabels}.GetFingerprint(),
+		Severity:             convertIntegrationSeverity(mapping, tools.RenderJsonPathValue(mapping.Severity, payload)),
+		Labels:               labels,
+		Annotations:          tools.RenderJsonPathValue(mapping.Annotations, payload),
+		RepeatNoticeInterval: faultCenter.RepeatNoticeInterval,
+		FaultCenterId:        faultCenter.ID,
+		// 外部推送的事件已由上游判定为告警，不再等待持续时间，首次推送即进入告警状态
+		FirstTriggerTime: time.Now().Unix() - 1,
+		ForDuration:      0,
+	}
+}
+
+// isIntegrationResolved 判断事件是否满足恢复条件
+func isIntegrationResolved(mapping models.IntegrationMapping, payload interface{}) bool {
+	if mapping.ResolvePath == "" || len(mapping.ResolveValues) == 0 {
+		return false
+	}
+
+	value := tools.FormatJsonValue(tools.GetJsonPathValue(payload, mapping.ResolvePath))
+	for _, v := range mapping.ResolveValues {
+		if strings.EqualFold(value, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func convertIntegrationSeverity(mapping models.IntegrationMapping, severity string) string {
+	if s, ok := mapping.SeverityMapping[severity]; ok {
+		return s
+	}
+
+	return convertAlertmanagerSeverity(severity)
+}
+
+func newIntegrationToken() string {
+	return strings.ReplaceAll(uuid.NewString(), "-", "")
+}
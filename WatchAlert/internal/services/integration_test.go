@@ -0,0 +1,27 @@
+package services
+
+import (
+	"testing"
+	"watchAlert/internal/models"
+)
+
+func TestBuildIntegrationEventArrivesForDuration(t *testing.T) {
+	integration := models.Integration{
+		ID:       "int-1",
+		TenantId: "default",
+		Name:     "zabbix",
+		Mapping: models.IntegrationMapping{
+			Labels: map[string]string{"host": "$.host"},
+		},
+	}
+	event := buildIntegrationEvent(integration, models.FaultCenter{ID: "fc-1"}, map[string]interface{}{"host": "db-1"})
+
+	// 首次推送时缓存中不存在该事件
+	event.InheritEvalTime(models.AlertCurEvent{})
+	if !event.IsArriveForDuration() {
+		t.Fatalf("single push should reach alerting state, firstTriggerTime=%d lastEvalTime=%d", event.FirstTriggerTime, event.LastEvalTime)
+	}
+	if event.Fingerprint == "" || event.Labels["host"] != "db-1" {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+}
@@ -3,8 +3,10 @@ package services
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"time"
 	"watchAlert/internal/ctx"
+	"watchAlert/internal/global"
 	"watchAlert/internal/models"
 	"watchAlert/internal/types"
 	"watchAlert/pkg/sender"
@@ -27,6 +29,9 @@ type InterNoticeService interface {
 	GetRecordMetric(req interface{}) (interface{}, interface{})
 	DeleteRecord(req interface{}) (interface{}, interface{})
 	Test(req interface{}) (interface{}, interface{})
+	ListDeadLetter(req interface{}) (interface{}, interface{})
+	ReplayDeadLetter(req interface{}) (interface{}, interface{})
+	DiscardDeadLetter(req interface{}) (interface{}, interface{})
 }
 
 func newInterAlertNoticeService(ctx *ctx.Context) InterNoticeService {
@@ -58,19 +63,22 @@ func (n noticeService) Create(req interface{}) (interface{}, interface{}) {
 	}
 
 	err := n.ctx.DB.Notice().Create(models.AlertNotice{
-		TenantId:     r.TenantId,
-		Uuid:         "n-" + tools.RandId(),
-		Name:         r.Name,
-		DutyId:       r.DutyId,
-		NoticeType:   r.NoticeType,
-		NoticeTmplId: r.NoticeTmplId,
-		DefaultHook:  hook,
-		DefaultSign:  r.DefaultSign,
-		Routes:       r.Routes,
-		Email:        r.Email,
-		PhoneNumber:  r.PhoneNumber,
-		UpdateAt:     time.Now().Unix(),
-		UpdateBy:     r.UpdateBy,
+		TenantId:      r.TenantId,
+		Uuid:          "n-" + tools.RandId(),
+		Name:          r.Name,
+		DutyId:        r.DutyId,
+		NoticeType:    r.NoticeType,
+		NoticeTmplId:  r.NoticeTmplId,
+		DefaultHook:   hook,
+		DefaultSign:   r.DefaultSign,
+		ChatId:        r.ChatId,
+		Routes:        r.Routes,
+		Email:         r.Email,
+		PhoneNumber:   r.PhoneNumber,
+		RateLimit:     r.RateLimit,
+		CallDutyUsers: r.CallDutyUsers,
+		UpdateAt:      time.Now().Unix(),
+		UpdateBy:      r.UpdateBy,
 	})
 	if err != nil {
 		return nil, err
@@ -86,19 +94,22 @@ func (n noticeService) Update(req interface{}) (interface{}, interface{}) {
 			r.CorpId, r.Secret, r.AgentId, r.ToUser)
 	}
 	err := n.ctx.DB.Notice().Update(models.AlertNotice{
-		TenantId:     r.TenantId,
-		Uuid:         r.Uuid,
-		Name:         r.Name,
-		DutyId:       r.GetDutyId(),
-		NoticeType:   r.NoticeType,
-		NoticeTmplId: r.NoticeTmplId,
-		DefaultHook:  hook,
-		DefaultSign:  r.DefaultSign,
-		Routes:       r.Routes,
-		Email:        r.Email,
-		PhoneNumber:  r.PhoneNumber,
-		UpdateAt:     time.Now().Unix(),
-		UpdateBy:     r.UpdateBy,
+		TenantId:      r.TenantId,
+		Uuid:          r.Uuid,
+		Name:          r.Name,
+		DutyId:        r.GetDutyId(),
+		NoticeType:    r.NoticeType,
+		NoticeTmplId:  r.NoticeTmplId,
+		DefaultHook:   hook,
+		DefaultSign:   r.DefaultSign,
+		ChatId:        r.ChatId,
+		Routes:        r.Routes,
+		Email:         r.Email,
+		PhoneNumber:   r.PhoneNumber,
+		RateLimit:     r.RateLimit,
+		CallDutyUsers: r.CallDutyUsers,
+		UpdateAt:      time.Now().Unix(),
+		UpdateBy:      r.UpdateBy,
 	})
 	if err != nil {
 		return nil, err
@@ -145,6 +156,65 @@ func (n noticeService) DeleteRecord(req interface{}) (interface{}, interface{})
 	return nil, nil
 }
 
+// ListDeadLetter 获取重试失败进入死信列表的通知
+func (n noticeService) ListDeadLetter(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestNoticeRetryQuery)
+	list := n.ctx.Redis.NoticeRetry().ListDeadLetters(r.TenantId)
+	// 通知地址及签名包含访问凭证，列表中脱敏展示
+	for i := range list {
+		list[i].Hook = maskNoticeHook(list[i].Hook)
+		list[i].Sign = maskNoticeSecret(list[i].Sign)
+	}
+	return list, nil
+}
+
+// maskNoticeHook 仅保留通知地址的协议及域名
+func maskNoticeHook(hook string) string {
+	if hook == "" {
+		return ""
+	}
+	u, err := url.Parse(hook)
+	if err != nil || u.Host == "" {
+		return maskNoticeSecret(hook)
+	}
+	return u.Scheme + "://" + u.Host + "/******"
+}
+
+func maskNoticeSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "******"
+}
+
+// ReplayDeadLetter 将死信通知重新加入重试队列
+func (n noticeService) ReplayDeadLetter(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestNoticeRetryQuery)
+	retryCache := n.ctx.Redis.NoticeRetry()
+	task, ok := retryCache.GetDeadLetter(r.TenantId, r.ID)
+	if !ok {
+		return nil, fmt.Errorf("死信通知 %s 不存在", r.ID)
+	}
+
+	task.Attempts = 0
+	task.MaxAttempts = global.Config.Notice.Retry.GetRetryPolicy(task.NoticeType).MaxAttempts
+	task.NextRetryAt = time.Now().Unix()
+	task.UpdateAt = task.NextRetryAt
+	if !retryCache.Enqueue(task) {
+		return nil, fmt.Errorf("通知 %s 已在重试队列中", r.ID)
+	}
+	retryCache.RemoveDeadLetter(r.TenantId, r.ID)
+
+	return nil, nil
+}
+
+// DiscardDeadLetter 丢弃死信通知
+func (n noticeService) DiscardDeadLetter(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestNoticeRetryQuery)
+	n.ctx.Redis.NoticeRetry().RemoveDeadLetter(r.TenantId, r.ID)
+	return nil, nil
+}
+
 type ResponseRecordMetric struct {
 	Date   []string `json:"date"`
 	Series series   `json:"series"`
@@ -215,10 +285,12 @@ func (n noticeService) Test(req interface{}) (interface{}, interface{}) {
 		r.NoticeType, r.DefaultHook, len(r.Routes)))
 
 	err := sender.Tester(n.ctx, sender.SendParams{
-		NoticeType: r.NoticeType,
-		Hook:       r.DefaultHook,
-		Email:      r.Email,
-		Sign:       r.DefaultSign,
+		NoticeType:  r.NoticeType,
+		Hook:        r.DefaultHook,
+		Email:       r.Email,
+		Sign:        r.DefaultSign,
+		ChatId:      r.ChatId,
+		PhoneNumber: r.PhoneNumber,
 	})
 	if err != nil {
 		errList = append(errList, struct {
@@ -1,6 +1,10 @@
 package services
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"watchAlert/alert"
 	"watchAlert/alert/probing"
 	"watchAlert/internal/ctx"
@@ -26,6 +30,8 @@ type (
 		Search(req interface{}) (interface{}, interface{})
 		Once(req interface{}) (interface{}, interface{})
 		GetHistory(req interface{}) (interface{}, interface{})
+		GetStepHistory(req interface{}) (interface{}, interface{})
+		GetAvailability(req interface{}) (interface{}, interface{})
 		ChangeState(req interface{}) (interface{}, interface{})
 	}
 )
@@ -38,6 +44,17 @@ func newInterProbingService(ctx *ctx.Context) InterProbingService {
 
 func (m probingService) Create(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestProbingRuleCreate)
+	if err := checkProbingConfig(r.RuleType, r.ProbingEndpointConfig); err != nil {
+		return nil, err
+	}
+	if err := checkProbingLocations(r.Locations, r.Quorum); err != nil {
+		return nil, err
+	}
+	if r.ReportNoticeId != "" {
+		if _, err := m.ctx.DB.Notice().Get(r.TenantId, r.ReportNoticeId); err != nil {
+			return nil, fmt.Errorf("报告通知对象不存在, %s", err.Error())
+		}
+	}
 	data := models.ProbingRule{
 		TenantId:              r.TenantId,
 		RuleName:              r.RuleName,
@@ -52,6 +69,9 @@ func (m probingService) Create(req interface{}) (interface{}, interface{}) {
 		UpdateAt:              time.Now().Unix(),
 		UpdateBy:              r.UpdateBy,
 		Enabled:               r.Enabled,
+		Locations:             r.Locations,
+		Quorum:                r.Quorum,
+		ReportNoticeId:        r.ReportNoticeId,
 	}
 
 	err := m.ctx.DB.Probing().Create(data)
@@ -81,6 +101,17 @@ func (m probingService) Create(req interface{}) (interface{}, interface{}) {
 
 func (m probingService) Update(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestProbingRuleUpdate)
+	if err := checkProbingConfig(r.RuleType, r.ProbingEndpointConfig); err != nil {
+		return nil, err
+	}
+	if err := checkProbingLocations(r.Locations, r.Quorum); err != nil {
+		return nil, err
+	}
+	if r.ReportNoticeId != "" {
+		if _, err := m.ctx.DB.Notice().Get(r.TenantId, r.ReportNoticeId); err != nil {
+			return nil, fmt.Errorf("报告通知对象不存在, %s", err.Error())
+		}
+	}
 	data := models.ProbingRule{
 		TenantId:              r.TenantId,
 		RuleName:              r.RuleName,
@@ -95,6 +126,9 @@ func (m probingService) Update(req interface{}) (interface{}, interface{}) {
 		UpdateAt:              time.Now().Unix(),
 		UpdateBy:              r.UpdateBy,
 		Enabled:               r.Enabled,
+		Locations:             r.Locations,
+		Quorum:                r.Quorum,
+		ReportNoticeId:        r.ReportNoticeId,
 	}
 
 	_, err := m.ctx.DB.Probing().Search(r.TenantId, r.RuleId)
@@ -160,6 +194,7 @@ func (m probingService) Delete(req interface{}) (interface{}, interface{}) {
 	if err != nil {
 		return nil, err
 	}
+	m.ctx.Redis.ProbingAgent().DeleteLocationResults(res.TenantId, res.RuleId)
 
 	return nil, nil
 }
@@ -178,6 +213,7 @@ func (m probingService) List(req interface{}) (interface{}, interface{}) {
 		case provider.HTTPEndpointProvider:
 			value.PHTTP.Latency = nv["Latency"]
 			value.PHTTP.StatusCode = nv["StatusCode"]
+			value.PHTTP.FailedAssertions = nv["FailedAssertions"]
 		case provider.ICMPEndpointProvider:
 			value.PICMP.PacketLoss = nv["PacketLoss"]
 			value.PICMP.MinRtt = nv["MinRtt"]
@@ -191,6 +227,27 @@ func (m probingService) List(req interface{}) (interface{}, interface{}) {
 			value.PSSL.StartTime = nv["StartTime"]
 			value.PSSL.ResponseTime = nv["ResponseTime"]
 			value.PSSL.TimeRemaining = nv["TimeRemaining"]
+		case provider.HTTPTransactionEndpointProvider:
+			value.PTransaction.IsSuccessful = nv["IsSuccessful"]
+			value.PTransaction.FailedStep = nv["FailedStep"]
+			value.PTransaction.Latency = nv["Latency"]
+			value.PTransaction.ErrorMessage = nv["ErrorMessage"]
+		case provider.DNSEndpointProvider:
+			value.PDNS.ResolveTime = nv["ResolveTime"]
+			value.PDNS.AnswerCount = nv["AnswerCount"]
+			value.PDNS.Answers = nv["Answers"]
+			value.PDNS.IsSuccessful = nv["IsSuccessful"]
+			value.PDNS.ErrorMessage = nv["ErrorMessage"]
+		case provider.GRPCEndpointProvider:
+			value.PGRPC.ResponseTime = nv["ResponseTime"]
+			value.PGRPC.ServingStatus = nv["ServingStatus"]
+			value.PGRPC.IsSuccessful = nv["IsSuccessful"]
+			value.PGRPC.ErrorMessage = nv["ErrorMessage"]
+		case provider.WebSocketEndpointProvider:
+			value.PWebSocket.ConnectTime = nv["ConnectTime"]
+			value.PWebSocket.ResponseTime = nv["ResponseTime"]
+			value.PWebSocket.IsSuccessful = nv["IsSuccessful"]
+			value.PWebSocket.ErrorMessage = nv["ErrorMessage"]
 		}
 	}
 
@@ -209,44 +266,27 @@ func (m probingService) Search(req interface{}) (interface{}, interface{}) {
 
 func (m probingService) Once(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestProbingOnce)
-	var ruleConfig = r.ProbingEndpointConfig
-	switch r.RuleType {
-	case provider.ICMPEndpointProvider:
-		return provider.NewEndpointPinger().Pilot(provider.EndpointOption{
-			Endpoint: ruleConfig.Endpoint,
-			Timeout:  ruleConfig.Strategy.Timeout,
-			ICMP: provider.Eicmp{
-				Interval: ruleConfig.ICMP.Interval,
-				Count:    ruleConfig.ICMP.Count,
-			},
-		})
-	case provider.HTTPEndpointProvider:
-		return provider.NewEndpointHTTPer().Pilot(provider.EndpointOption{
-			Endpoint: ruleConfig.Endpoint,
-			Timeout:  ruleConfig.Strategy.Timeout,
-			HTTP: provider.Ehttp{
-				Method: ruleConfig.HTTP.Method,
-				Header: ruleConfig.HTTP.Header,
-				Body:   ruleConfig.HTTP.Body,
-			},
-		})
-	case provider.TCPEndpointProvider:
-		return provider.NewEndpointTcper().Pilot(provider.EndpointOption{
-			Endpoint: ruleConfig.Endpoint,
-			Timeout:  ruleConfig.Strategy.Timeout,
-		})
-	case provider.SSLEndpointProvider:
-		return provider.NewEndpointSSLer().Pilot(provider.EndpointOption{
-			Endpoint: ruleConfig.Endpoint,
-			Timeout:  ruleConfig.Strategy.Timeout,
-		})
+	endpoint, err := provider.NewEndpointProvider(r.RuleType)
+	if err != nil {
+		return nil, err
 	}
-	return nil, nil
+	return endpoint.Pilot(provider.NewEndpointOption(r.ProbingEndpointConfig))
 }
 
 func (m probingService) GetHistory(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestProbingHistoryRecord)
-	data, err := m.ctx.DB.Probing().GetRecord(r.RuleId, r.DateRange)
+	data, err := m.ctx.DB.Probing().GetRecord(r.RuleId, r.Location, r.DateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetStepHistory 获取 HTTP 事务拨测各步骤的历史记录
+func (m probingService) GetStepHistory(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestProbingStepHistoryRecord)
+	data, err := m.ctx.DB.Probing().GetStepRecord(r.RuleId, r.Location, r.Step, r.DateRange)
 	if err != nil {
 		return nil, err
 	}
@@ -254,6 +294,31 @@ func (m probingService) GetHistory(req interface{}) (interface{}, interface{}) {
 	return data, nil
 }
 
+// GetAvailability 统计拨测规则在指定窗口内的可用率、故障次数、MTTR 及延迟分位数
+func (m probingService) GetAvailability(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestProbingAvailability)
+	rule, err := m.ctx.DB.Probing().Search(r.TenantId, r.RuleId)
+	if err != nil {
+		return nil, err
+	}
+	if rule.RuleId == "" {
+		return nil, fmt.Errorf("拨测规则 %s 不存在", r.RuleId)
+	}
+
+	start, end := r.StartAt, r.EndAt
+	if r.Window != "" {
+		start, end, err = probing.AvailabilityRange(r.Window, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if start <= 0 || end <= start {
+		return nil, fmt.Errorf("统计时间范围无效")
+	}
+
+	return probing.CalcAvailability(m.ctx, rule, r.Location, start, end)
+}
+
 func (m probingService) ChangeState(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestProbeChangeState)
 	var action string
@@ -294,3 +359,101 @@ func (m probingService) ChangeState(req interface{}) (interface{}, interface{})
 
 	return nil, nil
 }
+
+// checkProbingLocations 校验多位置拨测配置，Quorum 不能超过位置数
+func checkProbingLocations(locations []string, quorum int) error {
+	if len(locations) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(locations))
+	for _, location := range locations {
+		if location == "" {
+			return fmt.Errorf("拨测位置不能为空")
+		}
+		if _, ok := seen[location]; ok {
+			return fmt.Errorf("拨测位置 %s 重复", location)
+		}
+		seen[location] = struct{}{}
+	}
+	if quorum < 0 || quorum > len(locations) {
+		return fmt.Errorf("Quorum 需在 1 到 %d 之间", len(locations))
+	}
+	return nil
+}
+
+// checkProbingConfig 校验拨测配置
+func checkProbingConfig(ruleType string, config models.ProbingEndpointConfig) error {
+	switch ruleType {
+	case provider.HTTPEndpointProvider:
+		return checkHTTPAssertions(config.HTTP.AssertionLogic, config.HTTP.Assertions)
+	case provider.HTTPTransactionEndpointProvider:
+		if len(config.Transaction.Steps) == 0 {
+			return fmt.Errorf("HTTP 事务拨测至少需要一个步骤")
+		}
+		for i, step := range config.Transaction.Steps {
+			if step.URL == "" {
+				return fmt.Errorf("HTTP 事务第 %d 个步骤的 URL 不能为空", i+1)
+			}
+			for _, extract := range step.Extracts {
+				if extract.Name == "" {
+					return fmt.Errorf("HTTP 事务步骤 %s 的提取变量名不能为空", step.Name)
+				}
+				if extract.Regex == "" {
+					continue
+				}
+				if _, err := regexp.Compile(extract.Regex); err != nil {
+					return fmt.Errorf("HTTP 事务步骤 %s 的正则表达式不合法, err: %s", step.Name, err.Error())
+				}
+			}
+		}
+	case provider.DNSEndpointProvider:
+		switch strings.ToUpper(config.DNS.RecordType) {
+		case "", "A", "AAAA", "CNAME", "MX", "TXT", "NS":
+		default:
+			return fmt.Errorf("不支持的 DNS 记录类型: %s", config.DNS.RecordType)
+		}
+	case provider.WebSocketEndpointProvider:
+		if !strings.HasPrefix(config.Endpoint, "ws://") && !strings.HasPrefix(config.Endpoint, "wss://") {
+			return fmt.Errorf("WebSocket 拨测地址需以 ws:// 或 wss:// 开头")
+		}
+	}
+
+	return nil
+}
+
+// checkHTTPAssertions 校验 HTTP 断言配置
+func checkHTTPAssertions(logic string, assertions []models.HTTPAssertion) error {
+	switch strings.ToUpper(logic) {
+	case "", models.HTTPAssertionLogicAnd, models.HTTPAssertionLogicOr:
+	default:
+		return fmt.Errorf("不支持的断言组合方式: %s", logic)
+	}
+
+	for _, assertion := range assertions {
+		switch assertion.Type {
+		case models.HTTPAssertionBodyContains, models.HTTPAssertionBodyNotContains:
+		case models.HTTPAssertionBodyRegex:
+			if _, err := regexp.Compile(assertion.Value); err != nil {
+				return fmt.Errorf("断言正则表达式不合法, err: %s", err.Error())
+			}
+		case models.HTTPAssertionJSONPath, models.HTTPAssertionHeader:
+			if assertion.Target == "" {
+				return fmt.Errorf("%s 断言需配置 JSONPath 或响应头名称", assertion.Type)
+			}
+		case models.HTTPAssertionStatusCode, models.HTTPAssertionLatency, models.HTTPAssertionCertDays, models.HTTPAssertionRedirectCount:
+			if _, err := strconv.ParseFloat(assertion.Value, 64); err != nil {
+				return fmt.Errorf("%s 断言的期望值需为数值", assertion.Type)
+			}
+		default:
+			return fmt.Errorf("不支持的断言类型: %s", assertion.Type)
+		}
+
+		switch assertion.Operator {
+		case "", "==", "!=", ">", ">=", "<", "<=":
+		default:
+			return fmt.Errorf("不支持的断言运算: %s", assertion.Operator)
+		}
+	}
+
+	return nil
+}
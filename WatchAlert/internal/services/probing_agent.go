@@ -0,0 +1,178 @@
+package services
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"slices"
+	"time"
+	"watchAlert/alert/probing"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/global"
+	"watchAlert/internal/models"
+	"watchAlert/internal/types"
+	"watchAlert/pkg/provider"
+)
+
+type (
+	probingAgentService struct {
+		ctx *ctx.Context
+	}
+
+	InterProbingAgentService interface {
+		Register(req interface{}) (interface{}, interface{})
+		Rules(req interface{}) (interface{}, interface{})
+		PushResults(req interface{}) (interface{}, interface{})
+		List(req interface{}) (interface{}, interface{})
+	}
+)
+
+func newInterProbingAgentService(ctx *ctx.Context) InterProbingAgentService {
+	return &probingAgentService{
+		ctx: ctx,
+	}
+}
+
+// checkProbingAgentToken 校验节点 Token，未配置 Token 时拒绝所有节点
+func checkProbingAgentToken(token string) error {
+	expected := global.Config.ProbingAgent.Token
+	if expected == "" {
+		return fmt.Errorf("未配置拨测节点 Token，不允许节点接入")
+	}
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(token)) != 1 {
+		return fmt.Errorf("Token 校验失败")
+	}
+	return nil
+}
+
+// Register 节点注册，节点定期调用作为心跳
+func (p probingAgentService) Register(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestProbingAgentRegister)
+	if err := checkProbingAgentToken(r.Token); err != nil {
+		return nil, err
+	}
+	if r.AgentId == "" || r.Location == "" {
+		return nil, fmt.Errorf("节点ID和位置标签不能为空")
+	}
+	if r.Location == models.ProbingLocalLocation {
+		return nil, fmt.Errorf("位置标签 %s 为服务端保留", models.ProbingLocalLocation)
+	}
+
+	now := time.Now().Unix()
+	agent, ok := p.ctx.Redis.ProbingAgent().GetAgent(r.AgentId)
+	if !ok {
+		agent.RegisteredAt = now
+	}
+	agent.AgentId = r.AgentId
+	agent.Location = r.Location
+	agent.Hostname = r.Hostname
+	agent.Version = r.Version
+	agent.Address = r.Address
+	agent.LastHeartbeat = now
+	p.ctx.Redis.ProbingAgent().SetAgent(agent)
+
+	return nil, nil
+}
+
+// getAgent 校验 Token 并获取已注册的节点
+func (p probingAgentService) getAgent(token, agentId, location string) (models.ProbingAgent, error) {
+	if err := checkProbingAgentToken(token); err != nil {
+		return models.ProbingAgent{}, err
+	}
+	agent, ok := p.ctx.Redis.ProbingAgent().GetAgent(agentId)
+	if !ok {
+		return agent, fmt.Errorf("节点 %s 未注册", agentId)
+	}
+	if agent.Location != location {
+		return agent, fmt.Errorf("节点 %s 的位置标签与注册时不一致", agentId)
+	}
+	return agent, nil
+}
+
+// Rules 获取节点所在位置需要执行的拨测规则
+func (p probingAgentService) Rules(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestProbingAgentRules)
+	agent, err := p.getAgent(r.Token, r.AgentId, r.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := p.ctx.DB.Probing().ListEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]models.ProbingRule, 0)
+	for _, rule := range rules {
+		if slices.Contains(rule.Locations, agent.Location) {
+			data = append(data, rule)
+		}
+	}
+
+	return data, nil
+}
+
+// PushResults 节点上报拨测结果
+func (p probingAgentService) PushResults(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestProbingAgentResults)
+	agent, err := p.getAgent(r.Token, r.AgentId, r.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	for _, result := range r.Results {
+		rule, err := p.ctx.DB.Probing().Search(result.TenantId, result.RuleId)
+		if err != nil {
+			return nil, err
+		}
+		if rule.RuleId == "" || !slices.Contains(rule.Locations, agent.Location) {
+			continue
+		}
+
+		// 节点时间不可信，超前的时间戳以服务端时间为准
+		ts := result.Timestamp
+		if ts <= 0 || ts > now {
+			ts = now
+		}
+
+		var probeErr error
+		if result.Error != "" {
+			probeErr = fmt.Errorf("%s", result.Error)
+		}
+		probing.SaveLocationResult(p.ctx, rule, agent.Location, agent.AgentId, provider.EndpointValue(result.Value), probeErr, ts)
+	}
+
+	agent.LastHeartbeat = now
+	p.ctx.Redis.ProbingAgent().SetAgent(agent)
+
+	return nil, nil
+}
+
+// List 获取拨测节点列表，心跳超过结果过期时间的节点视为离线
+func (p probingAgentService) List(req interface{}) (interface{}, interface{}) {
+	var (
+		agents = p.ctx.Redis.ProbingAgent().ListAgents()
+		now    = time.Now().Unix()
+		ttl    = global.Config.ProbingAgent.GetResultTTL()
+	)
+	for i := range agents {
+		agents[i].Online = now-agents[i].LastHeartbeat <= ttl
+	}
+	slices.SortFunc(agents, func(a, b models.ProbingAgent) int {
+		if a.Location != b.Location {
+			if a.Location < b.Location {
+				return -1
+			}
+			return 1
+		}
+		if a.AgentId < b.AgentId {
+			return -1
+		}
+		if a.AgentId > b.AgentId {
+			return 1
+		}
+		return 0
+	})
+
+	return agents, nil
+}
@@ -2,12 +2,15 @@ package services
 
 import (
 	"fmt"
+	"regexp"
 	"time"
 	"watchAlert/alert"
+	"watchAlert/alert/eval"
 	"watchAlert/internal/ctx"
 	"watchAlert/internal/models"
 	"watchAlert/internal/types"
 	"watchAlert/pkg/client"
+	"watchAlert/pkg/provider"
 	"watchAlert/pkg/tools"
 
 	"github.com/bytedance/sonic"
@@ -27,6 +30,7 @@ type InterRuleService interface {
 	Get(req interface{}) (interface{}, interface{})
 	ChangeStatus(req interface{}) (interface{}, interface{})
 	Import(req interface{}) (interface{}, interface{})
+	Backtest(req interface{}) (interface{}, interface{})
 }
 
 func newInterRuleService(ctx *ctx.Context) InterRuleService {
@@ -41,6 +45,15 @@ func (rs ruleService) Create(req interface{}) (interface{}, interface{}) {
 	if !ok {
 		return nil, fmt.Errorf("创建失败, 配额不足")
 	}
+	if err := checkRuleConfig(r.DatasourceType, r.PrometheusConfig, r.ClickHouseConfig, r.JaegerConfig); err != nil {
+		return nil, err
+	}
+	if err := rs.checkRecordingConfig(r.TenantId, r.RuleType, r.DatasourceType, r.RecordingConfig); err != nil {
+		return nil, err
+	}
+	if err := rs.checkCompositeConfig(r.TenantId, "", r.RuleType, r.CompositeConfig); err != nil {
+		return nil, err
+	}
 
 	data := models.AlertRule{
 		TenantId:             r.TenantId,
@@ -66,6 +79,10 @@ func (rs ruleService) Create(req interface{}) (interface{}, interface{}) {
 		KubernetesConfig:     r.KubernetesConfig,
 		ElasticSearchConfig:  r.ElasticSearchConfig,
 		LogEvalCondition:     r.LogEvalCondition,
+		NoDataConfig:         r.NoDataConfig,
+		RuleType:             r.RuleType,
+		RecordingConfig:      r.RecordingConfig,
+		CompositeConfig:      r.CompositeConfig,
 		FaultCenterId:        r.FaultCenterId,
 		UpdateAt:             time.Now().Unix(),
 		UpdateBy:             r.UpdateBy,
@@ -98,6 +115,16 @@ func (rs ruleService) Create(req interface{}) (interface{}, interface{}) {
 
 func (rs ruleService) Update(req interface{}) (interface{}, interface{}) {
 	r := req.(*types.RequestRuleUpdate)
+	if err := checkRuleConfig(r.DatasourceType, r.PrometheusConfig, r.ClickHouseConfig, r.JaegerConfig); err != nil {
+		return nil, err
+	}
+	if err := rs.checkRecordingConfig(r.TenantId, r.RuleType, r.DatasourceType, r.RecordingConfig); err != nil {
+		return nil, err
+	}
+	if err := rs.checkCompositeConfig(r.TenantId, r.RuleId, r.RuleType, r.CompositeConfig); err != nil {
+		return nil, err
+	}
+
 	oldRule := models.AlertRule{}
 	rs.ctx.DB.DB().Model(&models.AlertRule{}).
 		Where("tenant_id = ? AND rule_id = ?", r.TenantId, r.RuleId).
@@ -147,6 +174,10 @@ func (rs ruleService) Update(req interface{}) (interface{}, interface{}) {
 		KubernetesConfig:     r.KubernetesConfig,
 		ElasticSearchConfig:  r.ElasticSearchConfig,
 		LogEvalCondition:     r.LogEvalCondition,
+		NoDataConfig:         r.NoDataConfig,
+		RuleType:             r.RuleType,
+		RecordingConfig:      r.RecordingConfig,
+		CompositeConfig:      r.CompositeConfig,
 		FaultCenterId:        r.FaultCenterId,
 		UpdateAt:             time.Now().Unix(),
 		UpdateBy:             r.UpdateBy,
@@ -393,6 +424,10 @@ func (rs ruleService) Import(req interface{}) (interface{}, interface{}) {
 			KubernetesConfig:     rule.KubernetesConfig,
 			ElasticSearchConfig:  rule.ElasticSearchConfig,
 			LogEvalCondition:     rule.LogEvalCondition,
+			NoDataConfig:         rule.NoDataConfig,
+			RuleType:             rule.RuleType,
+			RecordingConfig:      rule.RecordingConfig,
+			CompositeConfig:      rule.CompositeConfig,
 			FaultCenterId:        rule.FaultCenterId,
 			Enabled:              &disable,
 		})
@@ -404,3 +439,204 @@ func (rs ruleService) Import(req interface{}) (interface{}, interface{}) {
 
 	return nil, nil
 }
+
+// Backtest 使用历史数据回放规则，评估规则上线后的告警情况
+func (rs ruleService) Backtest(req interface{}) (interface{}, interface{}) {
+	r := req.(*types.RequestRuleBacktest)
+	rule := r.Rule
+	rule.TenantId = r.TenantId
+
+	datasourceIds := r.DatasourceIds
+	if len(datasourceIds) == 0 {
+		datasourceIds = rule.DatasourceIdList
+	}
+
+	recoverWaitTime := r.RecoverWaitTime
+	if recoverWaitTime <= 0 && rule.FaultCenterId != "" {
+		faultCenter, err := rs.ctx.DB.FaultCenter().Get(r.TenantId, rule.FaultCenterId, "")
+		if err == nil {
+			recoverWaitTime = faultCenter.RecoverWaitTime
+		}
+	}
+	if recoverWaitTime <= 0 {
+		recoverWaitTime = eval.DefaultRecoverWaitTime
+	}
+
+	if rule.IsRecordingRule() {
+		return nil, fmt.Errorf("记录规则不支持回测")
+	}
+	if rule.IsCompositeRule() {
+		return nil, fmt.Errorf("组合规则不支持回测")
+	}
+
+	for _, datasourceId := range datasourceIds {
+		if _, err := rs.getTenantDatasource(r.TenantId, datasourceId); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := eval.Backtest(rs.ctx, rule, datasourceIds, r.StartAt, r.EndAt, r.Step, recoverWaitTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// getTenantDatasource 获取当前租户下的数据源，避免跨租户查询或写入数据
+func (rs ruleService) getTenantDatasource(tenantId, datasourceId string) (models.AlertDataSource, error) {
+	list, err := rs.ctx.DB.Datasource().List(tenantId, datasourceId, "", "")
+	if err != nil {
+		return models.AlertDataSource{}, err
+	}
+	if datasourceId == "" || len(list) == 0 {
+		return models.AlertDataSource{}, fmt.Errorf("数据源 %s 不存在", datasourceId)
+	}
+
+	return list[0], nil
+}
+
+// checkRuleConfig 校验指标异常检测、SQL、链路数据源规则配置
+func checkRuleConfig(datasourceType string, prometheusConfig models.PrometheusConfig, clickhouseConfig models.ClickHouseConfig, jaegerConfig models.JaegerConfig) error {
+	if prometheusConfig.IsAnomalyMode() && !provider.SupportsRangeStep(datasourceType) {
+		return fmt.Errorf("数据源 %s 不支持按步长查询历史数据, 无法使用异常检测模式", datasourceType)
+	}
+
+	switch datasourceType {
+	case provider.PrometheusDsProvider, provider.VictoriaMetricsDsProvider, provider.GraphiteDsProvider, provider.InfluxDBDsProvider, provider.OpenTSDBDsProvider:
+		if !prometheusConfig.IsAnomalyMode() {
+			return nil
+		}
+		switch prometheusConfig.AnomalyConfig.Algorithm {
+		case models.AnomalyAlgorithmWeekOverWeek, models.AnomalyAlgorithmStdDev, models.AnomalyAlgorithmHoltWinters:
+		default:
+			return fmt.Errorf("不支持的异常检测算法: %s", prometheusConfig.AnomalyConfig.Algorithm)
+		}
+		switch prometheusConfig.AnomalyConfig.GetDirection() {
+		case models.AnomalyDirectionBoth, models.AnomalyDirectionUp, models.AnomalyDirectionDown:
+		default:
+			return fmt.Errorf("不支持的异常检测方向: %s", prometheusConfig.AnomalyConfig.Direction)
+		}
+	case provider.MySQLDsProviderName, provider.PostgreSQLDsProviderName:
+		// SQL 规则必须配置 ValueField，且查询语句为单条只读语句
+		if clickhouseConfig.ValueField == "" {
+			return fmt.Errorf("SQL 数据源规则需配置 ValueField")
+		}
+		if _, err := provider.CheckReadOnlySQL(datasourceType, clickhouseConfig.LogQL); err != nil {
+			return fmt.Errorf("SQL 语句校验失败, err: %s", err.Error())
+		}
+	case provider.JaegerDsProviderName, provider.TempoDsProviderName, provider.ZipkinDsProviderName:
+		switch jaegerConfig.GetConditionType() {
+		case models.TraceConditionCount:
+		case models.TraceConditionP95Latency, models.TraceConditionP99Latency, models.TraceConditionErrorRatio:
+			if _, _, err := tools.ProcessRuleExpr(jaegerConfig.Expr); err != nil {
+				return fmt.Errorf("链路告警条件校验失败, err: %s", err.Error())
+			}
+			if jaegerConfig.SampleLimit < 0 || jaegerConfig.SampleLimit > models.TraceMaxSampleLimit {
+				return fmt.Errorf("链路采样数需在 1-%d 之间", models.TraceMaxSampleLimit)
+			}
+		default:
+			return fmt.Errorf("不支持的链路告警条件类型: %s", jaegerConfig.ConditionType)
+		}
+	}
+
+	return nil
+}
+
+// recordNameRegexp 合法的 Prometheus 指标名称
+var recordNameRegexp = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// checkRecordingConfig 校验记录规则配置，仅支持 Prometheus、VictoriaMetrics 数据源，写入的数据源需属于同一租户
+func (rs ruleService) checkRecordingConfig(tenantId, ruleType, datasourceType string, config models.RecordingConfig) error {
+	switch ruleType {
+	case "", models.RuleTypeAlert, models.RuleTypeComposite:
+		return nil
+	case models.RuleTypeRecording:
+	default:
+		return fmt.Errorf("不支持的规则类型: %s", ruleType)
+	}
+
+	switch datasourceType {
+	case provider.PrometheusDsProvider, provider.VictoriaMetricsDsProvider:
+	default:
+		return fmt.Errorf("记录规则仅支持 Prometheus、VictoriaMetrics 数据源")
+	}
+	if !recordNameRegexp.MatchString(config.Record) {
+		return fmt.Errorf("记录规则指标名称不合法: %s", config.Record)
+	}
+	if config.Expr == "" {
+		return fmt.Errorf("记录规则表达式不能为空")
+	}
+	if config.WriteDatasourceId != "" {
+		ds, err := rs.getTenantDatasource(tenantId, config.WriteDatasourceId)
+		if err != nil {
+			return fmt.Errorf("记录规则写入的数据源不存在: %s", config.WriteDatasourceId)
+		}
+		if ds.Type != provider.PrometheusDsProvider && ds.Type != provider.VictoriaMetricsDsProvider {
+			return fmt.Errorf("记录规则仅支持写入 Prometheus、VictoriaMetrics 数据源")
+		}
+	}
+
+	return nil
+}
+
+// checkCompositeConfig 校验组合规则配置，至少两个条件，别名唯一，引用的规则为同租户下的告警规则、数据源为同租户的数据源，表达式仅引用已定义的别名
+func (rs ruleService) checkCompositeConfig(tenantId, ruleId, ruleType string, config models.CompositeConfig) error {
+	if ruleType != models.RuleTypeComposite {
+		return nil
+	}
+
+	if len(config.Conditions) < 2 {
+		return fmt.Errorf("组合规则至少需要两个条件")
+	}
+
+	aliases := make(map[string]struct{}, len(config.Conditions))
+	for _, condition := range config.Conditions {
+		if !compositeAliasRegexp.MatchString(condition.Alias) {
+			return fmt.Errorf("组合规则条件别名不合法: %s", condition.Alias)
+		}
+		if _, ok := aliases[condition.Alias]; ok {
+			return fmt.Errorf("组合规则条件别名重复: %s", condition.Alias)
+		}
+		aliases[condition.Alias] = struct{}{}
+
+		if condition.RuleId != "" {
+			// 引用的规则需属于同一租户，且不能引用自身或其他组合规则，避免循环依赖
+			if condition.RuleId == ruleId {
+				return fmt.Errorf("组合规则条件 %s 不能引用自身", condition.Alias)
+			}
+			refRule, err := rs.ctx.DB.Rule().GetTenantRule(tenantId, condition.RuleId)
+			if err != nil {
+				return fmt.Errorf("组合规则条件 %s 引用的规则不存在: %s", condition.Alias, condition.RuleId)
+			}
+			if refRule.IsCompositeRule() || refRule.IsRecordingRule() {
+				return fmt.Errorf("组合规则条件 %s 只能引用告警规则: %s", condition.Alias, condition.RuleId)
+			}
+			continue
+		}
+		if condition.DatasourceId == "" || condition.Query == "" {
+			return fmt.Errorf("组合规则条件 %s 需引用规则或配置数据源及查询语句", condition.Alias)
+		}
+		if _, err := rs.getTenantDatasource(tenantId, condition.DatasourceId); err != nil {
+			return fmt.Errorf("组合规则条件 %s 引用的数据源不存在: %s", condition.Alias, condition.DatasourceId)
+		}
+		if _, _, err := tools.ProcessRuleExpr(condition.Expr); err != nil {
+			return fmt.Errorf("组合规则条件 %s 表达式校验失败, err: %s", condition.Alias, err.Error())
+		}
+	}
+
+	expr, err := tools.ParseBoolExpr(config.Expr)
+	if err != nil {
+		return fmt.Errorf("组合规则表达式校验失败, err: %s", err.Error())
+	}
+	for _, ident := range expr.Idents() {
+		if _, ok := aliases[ident]; !ok {
+			return fmt.Errorf("组合规则表达式引用了未定义的条件: %s", ident)
+		}
+	}
+
+	return nil
+}
+
+// compositeAliasRegexp 合法的条件别名，需可作为标签名前缀
+var compositeAliasRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
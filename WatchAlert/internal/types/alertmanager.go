@@ -0,0 +1,56 @@
+package types
+
+import "time"
+
+const (
+	// AlertmanagerDatasourceType 外部 Alertmanager 推送事件的数据源类型
+	AlertmanagerDatasourceType = "Alertmanager"
+
+	AlertmanagerStatusFiring   = "firing"
+	AlertmanagerStatusResolved = "resolved"
+)
+
+// RequestAlertmanagerWebhook Alertmanager v4 webhook 请求体
+type RequestAlertmanagerWebhook struct {
+	TenantId          string              `json:"-"`
+	FaultCenterId     string              `json:"-"`
+	Token             string              `json:"-"`
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	TruncatedAlerts   int64               `json:"truncatedAlerts"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+// RequestAlertmanagerPush Alertmanager /api/v2/alerts 推送请求
+type RequestAlertmanagerPush struct {
+	TenantId      string              `json:"-"`
+	FaultCenterId string              `json:"-"`
+	Token         string              `json:"-"`
+	Alerts        []AlertmanagerAlert `json:"-"`
+}
+
+// AlertmanagerAlert 单条告警，兼容 webhook 与 /api/v2/alerts 两种格式
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// IsResolved 判断告警是否已恢复，/api/v2/alerts 没有 status 字段，需根据 endsAt 判断
+func (a AlertmanagerAlert) IsResolved() bool {
+	if a.Status != "" {
+		return a.Status == AlertmanagerStatusResolved
+	}
+
+	return !a.EndsAt.IsZero() && a.EndsAt.Before(time.Now())
+}
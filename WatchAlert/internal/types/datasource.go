@@ -21,6 +21,8 @@ type RequestDatasourceCreate struct {
 	DsAliCloudConfig models.DsAliCloudConfig   `json:"dsAliCloudConfig" `
 	AWSCloudWatch    models.AWSCloudWatch      `json:"awsCloudwatch" `
 	ClickHouseConfig models.DsClickHouseConfig `json:"clickhouseConfig"`
+	InfluxDBConfig   models.DsInfluxDBConfig   `json:"influxdbConfig"`
+	SQLConfig        models.DsSQLConfig        `json:"sqlConfig"`
 	Description      string                    `json:"description"`
 	KubeConfig       string                    `json:"kubeConfig"`
 	UpdateBy         string                    `json:"updateBy"`
@@ -38,6 +40,8 @@ type RequestDatasourceUpdate struct {
 	DsAliCloudConfig models.DsAliCloudConfig   `json:"dsAliCloudConfig" `
 	AWSCloudWatch    models.AWSCloudWatch      `json:"awsCloudwatch" `
 	ClickHouseConfig models.DsClickHouseConfig `json:"clickhouseConfig"`
+	InfluxDBConfig   models.DsInfluxDBConfig   `json:"influxdbConfig"`
+	SQLConfig        models.DsSQLConfig        `json:"sqlConfig"`
 	Description      string                    `json:"description"`
 	KubeConfig       string                    `json:"kubeConfig"`
 	UpdateBy         string                    `json:"updateBy"`
@@ -0,0 +1,29 @@
+package types
+
+import "watchAlert/internal/models"
+
+// RequestEscalationPolicyCreate 请求创建升级策略
+type RequestEscalationPolicyCreate struct {
+	TenantId    string                   `json:"tenantId"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Levels      []models.EscalationLevel `json:"levels"`
+	UpdateBy    string                   `json:"updateBy"`
+}
+
+// RequestEscalationPolicyUpdate 请求更新升级策略
+type RequestEscalationPolicyUpdate struct {
+	TenantId    string                   `json:"tenantId"`
+	ID          string                   `json:"id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Levels      []models.EscalationLevel `json:"levels"`
+	UpdateBy    string                   `json:"updateBy"`
+}
+
+// RequestEscalationPolicyQuery 请求查询升级策略
+type RequestEscalationPolicyQuery struct {
+	TenantId string `json:"tenantId" form:"tenantId"`
+	ID       string `json:"id" form:"id"`
+	Query    string `json:"query" form:"query"`
+}
@@ -95,3 +95,13 @@ type RequestListEventComments struct {
 	// 告警指纹
 	Fingerprint string `json:"fingerprint" form:"fingerprint"`
 }
+
+// RequestListEventTimeline 获取事件时间线
+type RequestListEventTimeline struct {
+	// 租户
+	TenantId string `json:"tenantId" form:"tenantId"`
+	// 事件 ID，为空时按告警指纹查询
+	EventId string `json:"eventId" form:"eventId"`
+	// 告警指纹
+	Fingerprint string `json:"fingerprint" form:"fingerprint"`
+}
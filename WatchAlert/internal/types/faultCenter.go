@@ -18,9 +18,14 @@ type RequestFaultCenterCreate struct {
 	CurrentAlertNumber    int64                  `json:"currentAlertNumber" gorm:"-"`
 	CurrentMuteNumber     int64                  `json:"currentMuteNumber" gorm:"-"`
 	CurrentRecoverNumber  int64                  `json:"currentRecoverNumber" gorm:"-"`
+	CurrentInhibitNumber  int64                  `json:"currentInhibitNumber" gorm:"-"`
 	IsUpgradeEnabled      *bool                  `json:"isUpgradeEnabled" gorm:"column:isUpgradeEnabled"`
 	UpgradableSeverity    []string               `json:"upgradableSeverity" gorm:"column:upgradableSeverity;serializer:json"`
 	UpgradeStrategy       models.UpgradeStrategy `json:"upgradeStrategy" gorm:"column:upgradeStrategy;serializer:json"`
+	EscalationPolicyId    string                 `json:"escalationPolicyId" gorm:"column:escalationPolicyId"`
+	InhibitRules          []models.InhibitRule   `json:"inhibitRules" gorm:"column:inhibitRules;serializer:json"`
+	GroupStrategy         models.GroupStrategy   `json:"groupStrategy" gorm:"column:groupStrategy;serializer:json"`
+	FlapDetection         models.FlapDetection   `json:"flapDetection" gorm:"column:flapDetection;serializer:json"`
 }
 
 // RequestFaultCenterUpdate 请求更新故障中心
@@ -40,9 +45,14 @@ type RequestFaultCenterUpdate struct {
 	CurrentAlertNumber    int64                  `json:"currentAlertNumber" gorm:"-"`
 	CurrentMuteNumber     int64                  `json:"currentMuteNumber" gorm:"-"`
 	CurrentRecoverNumber  int64                  `json:"currentRecoverNumber" gorm:"-"`
+	CurrentInhibitNumber  int64                  `json:"currentInhibitNumber" gorm:"-"`
 	IsUpgradeEnabled      *bool                  `json:"isUpgradeEnabled" gorm:"column:isUpgradeEnabled"`
 	UpgradableSeverity    []string               `json:"upgradableSeverity" gorm:"column:upgradableSeverity;serializer:json"`
 	UpgradeStrategy       models.UpgradeStrategy `json:"upgradeStrategy" gorm:"column:upgradeStrategy;serializer:json"`
+	EscalationPolicyId    string                 `json:"escalationPolicyId" gorm:"column:escalationPolicyId"`
+	InhibitRules          []models.InhibitRule   `json:"inhibitRules" gorm:"column:inhibitRules;serializer:json"`
+	GroupStrategy         models.GroupStrategy   `json:"groupStrategy" gorm:"column:groupStrategy;serializer:json"`
+	FlapDetection         models.FlapDetection   `json:"flapDetection" gorm:"column:flapDetection;serializer:json"`
 }
 
 // RequestFaultCenterQuery 请求查询故障中心
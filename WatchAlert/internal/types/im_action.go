@@ -0,0 +1,63 @@
+package types
+
+import "watchAlert/internal/models"
+
+// RequestImFeiShuCallback 飞书卡片回调
+type RequestImFeiShuCallback struct {
+	Timestamp string
+	Nonce     string
+	Signature string
+	Body      []byte
+}
+
+// RequestImSlackCallback Slack 交互回调
+type RequestImSlackCallback struct {
+	Timestamp string
+	Signature string
+	Body      []byte
+}
+
+// RequestImDingDingCallback 钉钉机器人 outgoing 回调
+type RequestImDingDingCallback struct {
+	Timestamp string
+	Sign      string
+	Body      []byte
+}
+
+// ImDingDingOutgoing 钉钉机器人 outgoing 回调内容
+type ImDingDingOutgoing struct {
+	MsgType string `json:"msgtype"`
+	Text    struct {
+		Content string `json:"content"`
+	} `json:"text"`
+	SenderStaffId  string `json:"senderStaffId"`
+	SenderNick     string `json:"senderNick"`
+	ConversationId string `json:"conversationId"`
+}
+
+// ImFeiShuCardAction 飞书卡片回调内容
+type ImFeiShuCardAction struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Token     string `json:"token"`
+	OpenID    string `json:"open_id"`
+	UserID    string `json:"user_id"`
+	Action    struct {
+		Value models.ImActionValue `json:"value"`
+		Tag   string               `json:"tag"`
+	} `json:"action"`
+}
+
+// ImSlackBlockAction Slack block_actions 回调内容
+type ImSlackBlockAction struct {
+	Type string `json:"type"`
+	User struct {
+		Id       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	ResponseUrl string `json:"response_url"`
+	Actions     []struct {
+		ActionId string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
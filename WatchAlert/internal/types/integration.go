@@ -0,0 +1,41 @@
+package types
+
+import "watchAlert/internal/models"
+
+// RequestIntegrationCreate 请求创建事件集成
+type RequestIntegrationCreate struct {
+	TenantId      string                    `json:"tenantId"`
+	Name          string                    `json:"name"`
+	Description   string                    `json:"description"`
+	FaultCenterId string                    `json:"faultCenterId"`
+	Mapping       models.IntegrationMapping `json:"mapping"`
+	Enabled       *bool                     `json:"enabled"`
+	UpdateBy      string                    `json:"updateBy"`
+}
+
+// RequestIntegrationUpdate 请求更新事件集成
+type RequestIntegrationUpdate struct {
+	TenantId      string                    `json:"tenantId"`
+	ID            string                    `json:"id"`
+	Name          string                    `json:"name"`
+	Description   string                    `json:"description"`
+	FaultCenterId string                    `json:"faultCenterId"`
+	Mapping       models.IntegrationMapping `json:"mapping"`
+	Enabled       *bool                     `json:"enabled"`
+	UpdateBy      string                    `json:"updateBy"`
+}
+
+// RequestIntegrationQuery 请求查询事件集成
+type RequestIntegrationQuery struct {
+	TenantId      string `json:"tenantId" form:"tenantId"`
+	ID            string `json:"id" form:"id"`
+	FaultCenterId string `json:"faultCenterId" form:"faultCenterId"`
+	Query         string `json:"query" form:"query"`
+}
+
+// RequestIntegrationPush 外部系统推送事件
+type RequestIntegrationPush struct {
+	ID      string      `json:"-"`
+	Token   string      `json:"-"`
+	Payload interface{} `json:"-"`
+}
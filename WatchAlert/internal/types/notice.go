@@ -3,40 +3,46 @@ package types
 import "watchAlert/internal/models"
 
 type RequestNoticeCreate struct {
-	TenantId     string         `json:"tenantId"`
-	Name         string         `json:"name"`
-	DutyId       *string        `json:"dutyId"`
-	NoticeType   string         `json:"noticeType"`
-	NoticeTmplId string         `json:"noticeTmplId"`
-	DefaultHook  string         `json:"hook" gorm:"column:hook"`
-	DefaultSign  string         `json:"sign" gorm:"column:sign"`
-	Routes       []models.Route `json:"routes" gorm:"column:routes;serializer:json"`
-	Email        models.Email   `json:"email" gorm:"email;serializer:json"`
-	PhoneNumber  []string       `json:"phoneNumber" gorm:"phoneNumber;serializer:json"`
-	CorpId       string         `json:"corpId"`
-	AgentId      string         `json:"agentId"`
-	Secret       string         `json:"secret"`
-	ToUser       string         `json:"toUser"`
-	UpdateBy     string         `json:"updateBy"`
+	TenantId      string           `json:"tenantId"`
+	Name          string           `json:"name"`
+	DutyId        *string          `json:"dutyId"`
+	NoticeType    string           `json:"noticeType"`
+	NoticeTmplId  string           `json:"noticeTmplId"`
+	DefaultHook   string           `json:"hook" gorm:"column:hook"`
+	DefaultSign   string           `json:"sign" gorm:"column:sign"`
+	ChatId        string           `json:"chatId"`
+	Routes        []models.Route   `json:"routes" gorm:"column:routes;serializer:json"`
+	Email         models.Email     `json:"email" gorm:"email;serializer:json"`
+	PhoneNumber   []string         `json:"phoneNumber" gorm:"phoneNumber;serializer:json"`
+	RateLimit     models.RateLimit `json:"rateLimit" gorm:"column:rateLimit;serializer:json"`
+	CallDutyUsers *bool            `json:"callDutyUsers"`
+	CorpId        string           `json:"corpId"`
+	AgentId       string           `json:"agentId"`
+	Secret        string           `json:"secret"`
+	ToUser        string           `json:"toUser"`
+	UpdateBy      string           `json:"updateBy"`
 }
 
 type RequestNoticeUpdate struct {
-	TenantId     string         `json:"tenantId"`
-	Uuid         string         `json:"uuid"`
-	Name         string         `json:"name"`
-	DutyId       *string        `json:"dutyId"`
-	NoticeType   string         `json:"noticeType"`
-	NoticeTmplId string         `json:"noticeTmplId"`
-	DefaultHook  string         `json:"hook" gorm:"column:hook"`
-	DefaultSign  string         `json:"sign" gorm:"column:sign"`
-	Routes       []models.Route `json:"routes" gorm:"column:routes;serializer:json"`
-	Email        models.Email   `json:"email" gorm:"email;serializer:json"`
-	PhoneNumber  []string       `json:"phoneNumber" gorm:"phoneNumber;serializer:json"`
-	CorpId       string         `json:"corpId"`
-	AgentId      string         `json:"agentId"`
-	Secret       string         `json:"secret"`
-	ToUser       string         `json:"toUser"`
-	UpdateBy     string         `json:"updateBy"`
+	TenantId      string           `json:"tenantId"`
+	Uuid          string           `json:"uuid"`
+	Name          string           `json:"name"`
+	DutyId        *string          `json:"dutyId"`
+	NoticeType    string           `json:"noticeType"`
+	NoticeTmplId  string           `json:"noticeTmplId"`
+	DefaultHook   string           `json:"hook" gorm:"column:hook"`
+	DefaultSign   string           `json:"sign" gorm:"column:sign"`
+	ChatId        string           `json:"chatId"`
+	Routes        []models.Route   `json:"routes" gorm:"column:routes;serializer:json"`
+	Email         models.Email     `json:"email" gorm:"email;serializer:json"`
+	PhoneNumber   []string         `json:"phoneNumber" gorm:"phoneNumber;serializer:json"`
+	RateLimit     models.RateLimit `json:"rateLimit" gorm:"column:rateLimit;serializer:json"`
+	CallDutyUsers *bool            `json:"callDutyUsers"`
+	CorpId        string           `json:"corpId"`
+	AgentId       string           `json:"agentId"`
+	Secret        string           `json:"secret"`
+	ToUser        string           `json:"toUser"`
+	UpdateBy      string           `json:"updateBy"`
 }
 
 func (requestNoticeUpdate *RequestNoticeUpdate) GetDutyId() *string {
@@ -46,6 +52,11 @@ func (requestNoticeUpdate *RequestNoticeUpdate) GetDutyId() *string {
 	return requestNoticeUpdate.DutyId
 }
 
+type RequestNoticeRetryQuery struct {
+	TenantId string `json:"tenantId" form:"tenantId"`
+	ID       string `json:"id" form:"id"`
+}
+
 type RequestNoticeQuery struct {
 	TenantId     string `json:"tenantId" form:"tenantId"`
 	EventId      string `json:"eventId" form:"eventId"`
@@ -62,6 +73,8 @@ type RequestNoticeTest struct {
 	NoticeType  string         `json:"noticeType"`
 	DefaultHook string         `json:"hook"`
 	DefaultSign string         `json:"sign"`
+	ChatId      string         `json:"chatId"`
 	Routes      []models.Route `json:"routes"`
 	Email       models.Email   `json:"email"`
+	PhoneNumber []string       `json:"phoneNumber"`
 }
@@ -16,6 +16,9 @@ type RequestProbingRuleCreate struct {
 	UpdateAt              int64                        `json:"updateAt"`
 	UpdateBy              string                       `json:"updateBy"`
 	Enabled               *bool                        `json:"enabled" `
+	Locations             []string                     `json:"locations"`
+	Quorum                int                          `json:"quorum"`
+	ReportNoticeId        string                       `json:"reportNoticeId"`
 }
 
 func (requestProbingRuleCreate *RequestProbingRuleCreate) GetEnabled() *bool {
@@ -41,6 +44,9 @@ type RequestProbingRuleUpdate struct {
 	UpdateAt              int64                        `json:"updateAt"`
 	UpdateBy              string                       `json:"updateBy"`
 	Enabled               *bool                        `json:"enabled" `
+	Locations             []string                     `json:"locations"`
+	Quorum                int                          `json:"quorum"`
+	ReportNoticeId        string                       `json:"reportNoticeId"`
 }
 
 func (requestProbingRuleUpdate *RequestProbingRuleUpdate) GetEnabled() *bool {
@@ -70,6 +76,28 @@ type RequestProbingOnce struct {
 type RequestProbingHistoryRecord struct {
 	RuleId    string `json:"ruleId" form:"ruleId"`
 	DateRange int64  `json:"dateRange" form:"dateRange"`
+	// 按拨测位置过滤，为空返回全部
+	Location string `json:"location" form:"location"`
+}
+
+// RequestProbingStepHistoryRecord 获取 HTTP 事务拨测的步骤明细记录
+type RequestProbingStepHistoryRecord struct {
+	RuleId    string `json:"ruleId" form:"ruleId"`
+	DateRange int64  `json:"dateRange" form:"dateRange"`
+	// 按拨测位置过滤，为空返回全部
+	Location string `json:"location" form:"location"`
+	// 步骤名称，为空返回全部步骤
+	Step string `json:"step" form:"step"`
+}
+
+// RequestProbingAvailability 获取拨测规则可用性，指定 Window 时按最近的 day/week/month 统计，否则使用 StartAt 与 EndAt
+type RequestProbingAvailability struct {
+	TenantId string `json:"tenantId" form:"tenantId"`
+	RuleId   string `json:"ruleId" form:"ruleId"`
+	Window   string `json:"window" form:"window"`
+	StartAt  int64  `json:"startAt" form:"startAt"`
+	EndAt    int64  `json:"endAt" form:"endAt"`
+	Location string `json:"location" form:"location"`
 }
 
 // RequestProbeChangeState 修改拨测规则状态
@@ -86,3 +114,37 @@ func (r *RequestProbeChangeState) GetEnabled() *bool {
 	}
 	return r.Enabled
 }
+
+// RequestProbingAgentRegister 拨测节点注册及心跳
+type RequestProbingAgentRegister struct {
+	Token    string `json:"-"`
+	Address  string `json:"-"`
+	AgentId  string `json:"agentId"`
+	Location string `json:"location"`
+	Hostname string `json:"hostname"`
+	Version  string `json:"version"`
+}
+
+// RequestProbingAgentRules 拨测节点拉取规则
+type RequestProbingAgentRules struct {
+	Token    string `json:"-" form:"-"`
+	AgentId  string `json:"agentId" form:"agentId"`
+	Location string `json:"location" form:"location"`
+}
+
+// RequestProbingAgentResults 拨测节点上报结果
+type RequestProbingAgentResults struct {
+	Token    string               `json:"-"`
+	AgentId  string               `json:"agentId"`
+	Location string               `json:"location"`
+	Results  []ProbingAgentResult `json:"results"`
+}
+
+type ProbingAgentResult struct {
+	TenantId  string         `json:"tenantId"`
+	RuleId    string         `json:"ruleId"`
+	Timestamp int64          `json:"timestamp"`
+	Value     map[string]any `json:"value"`
+	// 拨测执行失败时的错误信息
+	Error string `json:"error"`
+}
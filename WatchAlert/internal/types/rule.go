@@ -25,6 +25,10 @@ type RequestRuleCreate struct {
 	KubernetesConfig     models.KubernetesConfig    `json:"kubernetesConfig"`
 	ElasticSearchConfig  models.ElasticSearchConfig `json:"elasticSearchConfig"`
 	LogEvalCondition     string                     `json:"logEvalCondition"`
+	NoDataConfig         models.NoDataConfig        `json:"noDataConfig"`
+	RuleType             string                     `json:"ruleType"`
+	RecordingConfig      models.RecordingConfig     `json:"recordingConfig"`
+	CompositeConfig      models.CompositeConfig     `json:"compositeConfig"`
 	FaultCenterId        string                     `json:"faultCenterId"`
 	UpdateBy             string                     `json:"updateBy"`
 	Enabled              *bool                      `json:"enabled"`
@@ -62,6 +66,10 @@ type RequestRuleUpdate struct {
 	KubernetesConfig     models.KubernetesConfig    `json:"kubernetesConfig"`
 	ElasticSearchConfig  models.ElasticSearchConfig `json:"elasticSearchConfig"`
 	LogEvalCondition     string                     `json:"logEvalCondition"`
+	NoDataConfig         models.NoDataConfig        `json:"noDataConfig"`
+	RuleType             string                     `json:"ruleType"`
+	RecordingConfig      models.RecordingConfig     `json:"recordingConfig"`
+	CompositeConfig      models.CompositeConfig     `json:"compositeConfig"`
 	FaultCenterId        string                     `json:"faultCenterId"`
 	UpdateBy             string                     `json:"updateBy"`
 	Enabled              *bool                      `json:"enabled"`
@@ -144,3 +152,14 @@ func (r Rule) GetEnable() *bool {
 	var enable = false
 	return &enable
 }
+
+// RequestRuleBacktest 规则回测，Rule 无需保存
+type RequestRuleBacktest struct {
+	TenantId        string           `json:"tenantId"`
+	Rule            models.AlertRule `json:"rule"`
+	DatasourceIds   []string         `json:"datasourceIds"`   // 为空时使用规则配置的数据源
+	StartAt         int64            `json:"startAt"`         // 开始时间，单位（秒）
+	EndAt           int64            `json:"endAt"`           // 结束时间，单位（秒）
+	Step            int64            `json:"step"`            // 评估步长，单位（秒），为空时使用规则评估间隔
+	RecoverWaitTime int64            `json:"recoverWaitTime"` // 恢复等待时间，单位（秒），为空时使用故障中心配置
+}
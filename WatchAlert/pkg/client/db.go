@@ -90,6 +90,7 @@ func NewDBClient(config DBConfig) *gorm.DB {
 		&models.FaultCenter{},
 		&models.AiContentRecord{},
 		&models.ProbingHistory{},
+		&models.ProbingHistoryRollup{},
 		&models.Comment{},
 		&models.AlertTicketRule{},
 		&models.AlertTicketRuleHistory{},
@@ -108,6 +109,9 @@ func NewDBClient(config DBConfig) *gorm.DB {
 		&models.KnowledgeCategory{},
 		&models.AssignmentRule{},
 		&models.WechatRepairRequest{},
+		&models.Integration{},
+		&models.EscalationPolicy{},
+		&models.AlertEventTimeline{},
 	)
 	if err != nil {
 		logc.Error(context.Background(), err.Error())
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/zeromicro/go-zero/core/logc"
+	"io"
 	"watchAlert/internal/models"
 )
 
@@ -23,6 +24,15 @@ var datasourceFactories = map[string]ClientFactory{
 	"VictoriaMetrics": func(ds models.AlertDataSource) (HealthChecker, error) {
 		return NewVictoriaMetricsClient(ds)
 	},
+	"Graphite": func(ds models.AlertDataSource) (HealthChecker, error) {
+		return NewGraphiteClient(ds)
+	},
+	"InfluxDB": func(ds models.AlertDataSource) (HealthChecker, error) {
+		return NewInfluxDBClient(ds)
+	},
+	"OpenTSDB": func(ds models.AlertDataSource) (HealthChecker, error) {
+		return NewOpenTSDBClient(ds)
+	},
 	"Kubernetes": func(ds models.AlertDataSource) (HealthChecker, error) {
 		return NewKubernetesClient(context.Background(), ds.KubeConfig, ds.Labels)
 	},
@@ -38,6 +48,12 @@ var datasourceFactories = map[string]ClientFactory{
 	"Jaeger": func(ds models.AlertDataSource) (HealthChecker, error) {
 		return NewJaegerClient(ds)
 	},
+	"Tempo": func(ds models.AlertDataSource) (HealthChecker, error) {
+		return NewTempoClient(ds)
+	},
+	"Zipkin": func(ds models.AlertDataSource) (HealthChecker, error) {
+		return NewZipkinClient(ds)
+	},
 	"CloudWatch": func(ds models.AlertDataSource) (HealthChecker, error) {
 		return &CloudWatchDummyChecker{}, nil
 	},
@@ -47,6 +63,12 @@ var datasourceFactories = map[string]ClientFactory{
 	"ClickHouse": func(ds models.AlertDataSource) (HealthChecker, error) {
 		return NewClickHouseClient(context.Background(), ds)
 	},
+	"MySQL": func(ds models.AlertDataSource) (HealthChecker, error) {
+		return NewSQLClient(ds)
+	},
+	"PostgreSQL": func(ds models.AlertDataSource) (HealthChecker, error) {
+		return NewSQLClient(ds)
+	},
 }
 
 // CloudWatchDummyChecker 云监控哑检查器
@@ -73,6 +95,11 @@ func CheckDatasourceHealth(datasource models.AlertDataSource) (bool, error) {
 		return false, err
 	}
 
+	// 临时创建的数据库连接在检查结束后释放
+	if closer, ok := client.(io.Closer); ok {
+		defer closer.Close()
+	}
+
 	// 执行健康检查
 	healthy, err := client.Check()
 	if err != nil || !healthy {
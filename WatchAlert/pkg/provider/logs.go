@@ -16,6 +16,8 @@ const (
 	ElasticSearchDsProviderName string = "ElasticSearch"
 	VictoriaLogsDsProviderName  string = "VictoriaLogs"
 	ClickHouseDsProviderName    string = "ClickHouse"
+	MySQLDsProviderName         string = "MySQL"
+	PostgreSQLDsProviderName    string = "PostgreSQL"
 )
 
 type LogsFactoryProvider interface {
@@ -30,6 +32,7 @@ type LogQueryOptions struct {
 	ElasticSearch Elasticsearch
 	VictoriaLogs  VictoriaLogs
 	ClickHouse    ClickHouse
+	SQL           SQL
 	StartAt       interface{} // 查询的开始时间。
 	EndAt         interface{} // 查询的结束时间。
 }
@@ -72,6 +75,12 @@ type ClickHouse struct {
 	Query string
 }
 
+// SQL MySQL、PostgreSQL 数据源查询配置
+type SQL struct {
+	// 查询语句，仅支持只读语句
+	Query string
+}
+
 func (e Elasticsearch) GetIndexName() string {
 	if strings.Contains(e.Index, "YYYY") && strings.Contains(e.Index, "MM") && strings.Contains(e.Index, "dd") {
 		indexName := e.Index
@@ -0,0 +1,454 @@
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"watchAlert/internal/models"
+
+	"github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+const (
+	// 默认查询超时时间，单位（秒）
+	defaultSQLTimeout = 10
+	// 默认单次查询返回的最大行数
+	defaultSQLMaxRows = 1000
+)
+
+// 只读查询允许的语句类型
+var readOnlySQLKeywords = []string{"SELECT", "WITH", "SHOW", "EXPLAIN", "DESC", "DESCRIBE", "VALUES"}
+
+// 只读查询中不允许出现的写操作语句关键字，如 WITH ... DELETE、SELECT ... INTO OUTFILE；
+// REPLACE、SET、TRUNCATE 同时是函数名或子句，不在此列，以这些关键字开头的语句已由首个关键字校验拒绝
+var writeSQLKeywords = []string{
+	"INSERT", "UPDATE", "DELETE", "MERGE", "UPSERT", "INTO", "DROP", "ALTER", "CREATE",
+	"RENAME", "GRANT", "REVOKE", "CALL", "EXEC", "EXECUTE", "COPY", "LOAD", "LOCK", "HANDLER",
+}
+
+// SQLProvider MySQL、PostgreSQL 数据源，所有查询均在只读事务中执行
+type SQLProvider struct {
+	client         *sql.DB
+	providerName   string
+	timeout        time.Duration
+	maxRows        int
+	ExternalLabels map[string]interface{}
+}
+
+func NewSQLClient(ds models.AlertDataSource) (SQLProvider, error) {
+	timeout := ds.SQLConfig.Timeout
+	if timeout <= 0 {
+		timeout = defaultSQLTimeout
+	}
+	maxRows := ds.SQLConfig.MaxRows
+	if maxRows <= 0 {
+		maxRows = defaultSQLMaxRows
+	}
+
+	var (
+		driver string
+		dsn    string
+		err    error
+	)
+	switch ds.Type {
+	case MySQLDsProviderName:
+		driver = "mysql"
+		dsn, err = mysqlDSN(ds, timeout)
+	case PostgreSQLDsProviderName:
+		driver = "postgres"
+		dsn, err = postgresDSN(ds, timeout)
+	default:
+		err = fmt.Errorf("unsupported sql datasource type: %s", ds.Type)
+	}
+	if err != nil {
+		return SQLProvider{}, err
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return SQLProvider{}, err
+	}
+	db.SetMaxOpenConns(5)
+	db.SetMaxIdleConns(2)
+	db.SetConnMaxLifetime(10 * time.Minute)
+
+	return SQLProvider{
+		client:         db,
+		providerName:   ds.Type,
+		timeout:        time.Duration(timeout) * time.Second,
+		maxRows:        int(maxRows),
+		ExternalLabels: ds.Labels,
+	}, nil
+}
+
+func mysqlDSN(ds models.AlertDataSource, timeout int64) (string, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = ds.Auth.User
+	cfg.Passwd = ds.Auth.Pass
+	cfg.Net = "tcp"
+	cfg.Addr = ds.SQLConfig.Addr
+	cfg.DBName = ds.SQLConfig.Database
+	cfg.Timeout = time.Duration(timeout) * time.Second
+	cfg.ReadTimeout = time.Duration(timeout) * time.Second
+	cfg.ParseTime = true
+
+	params, err := url.ParseQuery(ds.SQLConfig.Params)
+	if err != nil {
+		return "", fmt.Errorf("invalid params: %w", err)
+	}
+	for k := range params {
+		// 禁止开启多语句执行，避免只读事务被 COMMIT 等语句提前结束
+		if strings.EqualFold(k, "multiStatements") {
+			continue
+		}
+		if cfg.Params == nil {
+			cfg.Params = make(map[string]string)
+		}
+		cfg.Params[k] = params.Get(k)
+	}
+
+	return cfg.FormatDSN(), nil
+}
+
+func postgresDSN(ds models.AlertDataSource, timeout int64) (string, error) {
+	params, err := url.ParseQuery(ds.SQLConfig.Params)
+	if err != nil {
+		return "", fmt.Errorf("invalid params: %w", err)
+	}
+	if params.Get("connect_timeout") == "" {
+		params.Set("connect_timeout", strconv.FormatInt(timeout, 10))
+	}
+
+	u := url.URL{
+		Scheme:   "postgres",
+		Host:     ds.SQLConfig.Addr,
+		Path:     "/" + ds.SQLConfig.Database,
+		RawQuery: params.Encode(),
+	}
+	if ds.Auth.User != "" {
+		u.User = url.UserPassword(ds.Auth.User, ds.Auth.Pass)
+	}
+
+	return u.String(), nil
+}
+
+// CheckReadOnlySQL 校验 SQL 为单条只读语句，返回去除结尾分号后的语句；
+// 校验前按数据库方言去除字符串常量及注释，避免 'a;b' 或 /* ; */ 等内容误判，并按单词检查是否包含写操作
+func CheckReadOnlySQL(dialect, statement string) (string, error) {
+	statement = strings.TrimRight(strings.TrimSpace(statement), "; \t\r\n")
+	stripped := strings.TrimRight(strings.TrimSpace(stripSQLLiterals(dialect, statement)), "; \t\r\n")
+	if stripped == "" {
+		return "", errors.New("sql statement is empty")
+	}
+	if strings.Contains(stripped, ";") {
+		return "", errors.New("only a single sql statement is allowed")
+	}
+
+	tokens := strings.FieldsFunc(strings.ToUpper(stripped), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	if len(tokens) == 0 || !slices.Contains(readOnlySQLKeywords, tokens[0]) {
+		return "", fmt.Errorf("only read-only statements are allowed, got: %s", strings.Fields(stripped)[0])
+	}
+	for _, token := range tokens {
+		if slices.Contains(writeSQLKeywords, token) {
+			return "", fmt.Errorf("only read-only statements are allowed, found: %s", token)
+		}
+	}
+
+	return statement, nil
+}
+
+// stripSQLLiterals 按数据库方言将字符串常量、引用标识符替换为占位符并去除注释：
+// MySQL 支持 # 注释，-- 后需跟空白字符才是注释，/*! */ 中的内容会被执行因此保留；
+// PostgreSQL 中 # 为运算符，仅 E 前缀字符串支持反斜杠转义，并支持 $tag$ 字符串及嵌套注释
+func stripSQLLiterals(dialect, statement string) string {
+	var (
+		b           strings.Builder
+		runes       = []rune(statement)
+		isMySQL     = dialect == MySQLDsProviderName
+		execComment bool
+	)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\'' || r == '"' || (isMySQL && r == '`'):
+			backslash := isMySQL && r != '`' || !isMySQL && r == '\'' && isPostgresEscapeString(runes, i)
+			i = skipSQLQuoted(runes, i, backslash)
+			b.WriteString(" ? ")
+		case !isMySQL && r == '$' && postgresDollarTag(runes, i) > 0:
+			i = skipDollarQuoted(runes, i, postgresDollarTag(runes, i))
+			b.WriteString(" ? ")
+		case isMySQL && r == '#', r == '-' && isSQLLineComment(runes, i, isMySQL):
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			b.WriteRune(' ')
+		case execComment && r == '*' && i+1 < len(runes) && runes[i+1] == '/':
+			execComment = false
+			i++
+			b.WriteRune(' ')
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			if isMySQL && i+2 < len(runes) && (runes[i+2] == '!' || runes[i+2] == '+') {
+				execComment = true
+				i += 2
+			} else {
+				i = skipSQLBlockComment(runes, i, !isMySQL)
+			}
+			b.WriteRune(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// skipSQLQuoted 跳过引号内的内容，返回结束引号的位置；连续两个引号不结束字符串
+func skipSQLQuoted(runes []rune, start int, backslash bool) int {
+	quote := runes[start]
+	for i := start + 1; i < len(runes); i++ {
+		if backslash && runes[i] == '\\' {
+			i++
+			continue
+		}
+		if runes[i] == quote {
+			if i+1 < len(runes) && runes[i+1] == quote {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+
+	return len(runes)
+}
+
+// skipSQLBlockComment 跳过 /* */ 注释，返回注释结束的位置，PostgreSQL 支持嵌套注释
+func skipSQLBlockComment(runes []rune, start int, nested bool) int {
+	depth := 0
+	for i := start; i < len(runes); i++ {
+		switch {
+		case runes[i] == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			if depth == 0 || nested {
+				depth++
+			}
+			i++
+		case runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/':
+			depth--
+			i++
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return len(runes)
+}
+
+// isSQLLineComment 判断 -- 是否为行注释，MySQL 要求 -- 后跟空白或控制字符
+func isSQLLineComment(runes []rune, i int, isMySQL bool) bool {
+	if i+1 >= len(runes) || runes[i+1] != '-' {
+		return false
+	}
+	if !isMySQL || i+2 >= len(runes) {
+		return true
+	}
+
+	return unicode.IsSpace(runes[i+2]) || unicode.IsControl(runes[i+2])
+}
+
+// isPostgresEscapeString 判断字符串是否为 E'...' 形式，仅此类字符串支持反斜杠转义
+func isPostgresEscapeString(runes []rune, quote int) bool {
+	if quote == 0 || (runes[quote-1] != 'E' && runes[quote-1] != 'e') {
+		return false
+	}
+
+	return quote == 1 || !isSQLIdentRune(runes[quote-2])
+}
+
+// postgresDollarTag 返回 $tag$ 字符串起始标记的长度，非 $tag$ 字符串时返回 0
+func postgresDollarTag(runes []rune, start int) int {
+	if start > 0 && (isSQLIdentRune(runes[start-1]) || runes[start-1] == '$') {
+		return 0
+	}
+	for i := start + 1; i < len(runes); i++ {
+		switch {
+		case runes[i] == '$':
+			return i - start + 1
+		case unicode.IsLetter(runes[i]) || runes[i] == '_' || (unicode.IsDigit(runes[i]) && i > start+1):
+		default:
+			return 0
+		}
+	}
+
+	return 0
+}
+
+// skipDollarQuoted 跳过 $tag$ 字符串，返回结束标记最后一个字符的位置
+func skipDollarQuoted(runes []rune, start, tagLen int) int {
+	tag := runes[start : start+tagLen]
+	for i := start + tagLen; i+tagLen <= len(runes); i++ {
+		if slices.Equal(runes[i:i+tagLen], tag) {
+			return i + tagLen - 1
+		}
+	}
+
+	return len(runes)
+}
+
+func isSQLIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// query 在只读事务中执行查询，超过超时时间自动取消
+func (s SQLProvider) query(statement string) ([]map[string]interface{}, error) {
+	statement, err := CheckReadOnlySQL(s.providerName, statement)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	tx, err := s.client.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// 使用预处理语句执行，由数据库在协议层拒绝多条语句
+	stmt, err := tx.PrepareContext(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		message []map[string]interface{}
+		values  = make([]interface{}, len(columns))
+	)
+	for rows.Next() {
+		if len(message) >= s.maxRows {
+			logc.Infof(context.Background(), "sql query result exceeds max rows %d, truncated", s.maxRows)
+			break
+		}
+
+		for i := range columns {
+			values[i] = new(interface{})
+		}
+
+		if err := rows.Scan(values...); err != nil {
+			logc.Error(context.Background(), "sql scan error:", err)
+			return nil, err
+		}
+
+		entry := make(map[string]interface{})
+		for i, col := range columns {
+			entry[col] = convertSQLValue(*values[i].(*interface{}), columnTypes[i].DatabaseTypeName())
+		}
+
+		message = append(message, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// convertSQLValue 数值类型的列在文本协议下以字节返回，按列类型还原为数值
+func convertSQLValue(value interface{}, typeName string) interface{} {
+	v, ok := value.([]byte)
+	if !ok {
+		return convertClickHouseValueAdvanced(value, typeName)
+	}
+
+	switch strings.ToUpper(typeName) {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT", "INT2", "INT4", "INT8", "YEAR":
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return i
+		}
+	case "UNSIGNED TINYINT", "UNSIGNED SMALLINT", "UNSIGNED MEDIUMINT", "UNSIGNED INT", "UNSIGNED BIGINT":
+		if i, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			return i
+		}
+	case "DECIMAL", "NUMERIC", "FLOAT", "DOUBLE", "FLOAT4", "FLOAT8", "REAL":
+		if f, err := strconv.ParseFloat(string(v), 64); err == nil {
+			return f
+		}
+	}
+
+	return string(v)
+}
+
+// Query 普通查询，用于数据预览
+func (s SQLProvider) Query(options LogQueryOptions) (Logs, int, error) {
+	message, err := s.query(options.SQL.Query)
+	if err != nil {
+		return Logs{}, 0, err
+	}
+
+	return Logs{
+		ProviderName: s.providerName,
+		Message:      message,
+	}, len(message), nil
+}
+
+// QueryAdvanced 高级模式查询，保持原始数据类型
+func (s SQLProvider) QueryAdvanced(options LogQueryOptions) (LogsAdvanced, int, error) {
+	message, err := s.query(options.SQL.Query)
+	if err != nil {
+		return LogsAdvanced{}, 0, err
+	}
+
+	return LogsAdvanced{
+		ProviderName: s.providerName,
+		Message:      message,
+	}, len(message), nil
+}
+
+func (s SQLProvider) Check() (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	if err := s.client.PingContext(ctx); err != nil {
+		return false, fmt.Errorf("check sql datasource is unhealthy: %w", err)
+	}
+
+	return true, nil
+}
+
+func (s SQLProvider) Close() error {
+	return s.client.Close()
+}
+
+func (s SQLProvider) GetExternalLabels() map[string]interface{} {
+	return s.ExternalLabels
+}
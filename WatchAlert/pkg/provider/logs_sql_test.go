@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"testing"
+	"watchAlert/internal/models"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestCheckReadOnlySQL(t *testing.T) {
+	tests := []struct {
+		name      string
+		dialect   string
+		statement string
+		want      string
+		wantErr   bool
+	}{
+		{name: "trailing semicolon", dialect: MySQLDsProviderName, statement: "select 1;", want: "select 1"},
+		{name: "semicolon in string", dialect: MySQLDsProviderName, statement: "select ';drop' from t", want: "select ';drop' from t"},
+		{name: "escaped quote in string", dialect: PostgreSQLDsProviderName, statement: "select 'it''s; x'", want: "select 'it''s; x'"},
+		{name: "semicolon in comment", dialect: MySQLDsProviderName, statement: "/* ; */ select 1", want: "/* ; */ select 1"},
+		{name: "column containing keyword", dialect: MySQLDsProviderName, statement: "select updated_at from t", want: "select updated_at from t"},
+		{name: "replace function", dialect: MySQLDsProviderName, statement: "select replace(name, 'a', 'b') from t", want: "select replace(name, 'a', 'b') from t"},
+		{name: "truncate function", dialect: MySQLDsProviderName, statement: "select truncate(1.234, 1)", want: "select truncate(1.234, 1)"},
+		{name: "mysql hash comment", dialect: MySQLDsProviderName, statement: "select 1 # ; commit", want: "select 1 # ; commit"},
+		{name: "postgres dollar string", dialect: PostgreSQLDsProviderName, statement: "select $x$a;b$x$", want: "select $x$a;b$x$"},
+		{name: "multiple statements", dialect: MySQLDsProviderName, statement: "select 1; drop table t", wantErr: true},
+		{name: "postgres hash is operator", dialect: PostgreSQLDsProviderName, statement: "select 1 # 1; commit; drop table orders", wantErr: true},
+		{name: "postgres double dash comment", dialect: PostgreSQLDsProviderName, statement: "select 1 --1\n; drop table t", wantErr: true},
+		{name: "mysql double dash without space", dialect: MySQLDsProviderName, statement: "select 1 --1; commit; drop table t", wantErr: true},
+		{name: "postgres backslash does not escape", dialect: PostgreSQLDsProviderName, statement: `select 'a\'; drop table t; --'`, wantErr: true},
+		{name: "postgres dollar hides quote", dialect: PostgreSQLDsProviderName, statement: "select $$ ' $$; drop table t; --'", wantErr: true},
+		{name: "mysql executable comment", dialect: MySQLDsProviderName, statement: "select 1 /*! ; drop table t */", wantErr: true},
+		{name: "write in cte", dialect: PostgreSQLDsProviderName, statement: "with x as (delete from t returning *) select * from x", wantErr: true},
+		{name: "select into", dialect: MySQLDsProviderName, statement: "select * into outfile '/tmp/x' from t", wantErr: true},
+		{name: "insert", dialect: MySQLDsProviderName, statement: "insert into t values (1)", wantErr: true},
+		{name: "empty", dialect: MySQLDsProviderName, statement: " ; ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CheckReadOnlySQL(tt.dialect, tt.statement)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckReadOnlySQL(%q) error = %v, wantErr %v", tt.statement, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("CheckReadOnlySQL(%q) = %q, want %q", tt.statement, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMySQLDSNDropsMultiStatements(t *testing.T) {
+	var ds models.AlertDataSource
+	ds.SQLConfig.Addr = "127.0.0.1:3306"
+	ds.SQLConfig.Params = "multiStatements=true&charset=utf8mb4"
+
+	dsn, err := mysqlDSN(ds, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MultiStatements || cfg.Params["charset"] != "utf8mb4" {
+		t.Fatalf("unexpected dsn: %s", dsn)
+	}
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"time"
 	"watchAlert/pkg/tools"
@@ -10,8 +11,21 @@ import (
 const (
 	PrometheusDsProvider      string = "Prometheus"
 	VictoriaMetricsDsProvider string = "VictoriaMetrics"
+	GraphiteDsProvider        string = "Graphite"
+	InfluxDBDsProvider        string = "InfluxDB"
+	OpenTSDBDsProvider        string = "OpenTSDB"
 )
 
+// SupportsRangeStep 数据源的 QueryRange 是否按 step 返回等间隔数据点，异常检测、回测依赖该能力
+func SupportsRangeStep(datasourceType string) bool {
+	switch datasourceType {
+	case PrometheusDsProvider, VictoriaMetricsDsProvider, GraphiteDsProvider, InfluxDBDsProvider, OpenTSDBDsProvider:
+		return true
+	default:
+		return false
+	}
+}
+
 type MetricsFactoryProvider interface {
 	Query(promQL string) ([]Metrics, error)
 	QueryRange(promQL string, start, end time.Time, step time.Duration) ([]Metrics, error)
@@ -50,3 +64,47 @@ func (m Metrics) GetMetric() map[string]interface{} {
 func (m Metrics) GetValue() float64 {
 	return m.Value
 }
+
+// stepSeconds 查询步长，不足 1 秒时按 1 秒处理
+func stepSeconds(step time.Duration) int64 {
+	seconds := int64(math.Ceil(step.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+// downsampleMetrics 按 step 对齐数据点，同一序列同一时间桶内保留最后一个数据点，用于数据源无法按步长返回数据的场景
+func downsampleMetrics(metrics []Metrics, start time.Time, step time.Duration) []Metrics {
+	if step <= 0 {
+		return metrics
+	}
+
+	var (
+		seconds = stepSeconds(step)
+		from    = start.Unix()
+		index   = make(map[string]int)
+		latest  []float64 // 各时间桶内保留数据点的原始时间戳
+		result  []Metrics
+	)
+	for _, m := range metrics {
+		bucket := from + int64(math.Round((m.Timestamp-float64(from))/float64(seconds)))*seconds
+		key := m.GetFingerprint() + "/" + strconv.FormatInt(bucket, 10)
+
+		point := m
+		point.Timestamp = float64(bucket)
+		idx, ok := index[key]
+		if !ok {
+			index[key] = len(result)
+			result = append(result, point)
+			latest = append(latest, m.Timestamp)
+			continue
+		}
+		if m.Timestamp >= latest[idx] {
+			result[idx] = point
+			latest[idx] = m.Timestamp
+		}
+	}
+
+	return result
+}
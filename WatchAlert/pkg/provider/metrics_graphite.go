@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"watchAlert/internal/models"
+	utilsHttp "watchAlert/pkg/tools"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+type GraphiteProvider struct {
+	ExternalLabels map[string]interface{}
+	address        string
+	username       string
+	password       string
+}
+
+func NewGraphiteClient(ds models.AlertDataSource) (MetricsFactoryProvider, error) {
+	return GraphiteProvider{
+		address:        ds.HTTP.URL,
+		ExternalLabels: ds.Labels,
+		username:       ds.Auth.User,
+		password:       ds.Auth.Pass,
+	}, nil
+}
+
+// GraphiteSeries Graphite render 接口返回的单条序列，datapoints 格式为 [value, timestamp]，value 可能为 null
+type GraphiteSeries struct {
+	Target     string                 `json:"target"`
+	Tags       map[string]interface{} `json:"tags"`
+	Datapoints [][]*float64           `json:"datapoints"`
+}
+
+// Query 查询最近 5 分钟的数据，取每条序列最后一个非空数据点
+func (g GraphiteProvider) Query(target string) ([]Metrics, error) {
+	end := time.Now()
+	series, err := g.render(target, end.Add(-5*time.Minute), end, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics []Metrics
+	for _, s := range series {
+		for i := len(s.Datapoints) - 1; i >= 0; i-- {
+			point := s.Datapoints[i]
+			if len(point) < 2 || point[0] == nil || point[1] == nil {
+				continue
+			}
+
+			metrics = append(metrics, Metrics{
+				Metric:    s.labels(),
+				Value:     *point[0],
+				Timestamp: *point[1],
+			})
+			break
+		}
+	}
+
+	return metrics, nil
+}
+
+func (g GraphiteProvider) QueryRange(target string, start, end time.Time, step time.Duration) ([]Metrics, error) {
+	series, err := g.render(target, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics []Metrics
+	for _, s := range series {
+		labels := s.labels()
+		for _, point := range s.Datapoints {
+			if len(point) < 2 || point[0] == nil || point[1] == nil {
+				continue
+			}
+
+			metrics = append(metrics, Metrics{
+				Metric:    labels,
+				Value:     *point[0],
+				Timestamp: *point[1],
+			})
+		}
+	}
+
+	return metrics, nil
+}
+
+// render 调用 render 接口，step 大于 0 时通过 summarize 按步长聚合并与起始时间对齐，同时限制返回的数据点数
+func (g GraphiteProvider) render(target string, start, end time.Time, step time.Duration) ([]GraphiteSeries, error) {
+	var (
+		params        = url.Values{}
+		summarizeArgs string
+	)
+	if step > 0 {
+		seconds := stepSeconds(step)
+		summarizeArgs = fmt.Sprintf(`, "%ds", "avg", true)`, seconds)
+		target = "summarize(" + target + summarizeArgs
+		params.Add("maxDataPoints", strconv.FormatInt((end.Unix()-start.Unix())/seconds+1, 10))
+	}
+	params.Add("target", target)
+	params.Add("from", strconv.FormatInt(start.Unix(), 10))
+	params.Add("until", strconv.FormatInt(end.Unix(), 10))
+	params.Add("format", "json")
+	fullURL := fmt.Sprintf("%s%s?%s", g.address, "/render", params.Encode())
+
+	resp, err := utilsHttp.Get(utilsHttp.CreateBasicAuthHeader(g.username, g.password), fullURL, 30)
+	if err != nil {
+		logc.Error(context.Background(), "Graphite render failed", "error", err)
+		return nil, fmt.Errorf("render failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var series []GraphiteSeries
+	if err := utilsHttp.ParseReaderBody(resp.Body, &series); err != nil {
+		logc.Error(context.Background(), "Parse response failed", "error", err)
+		return nil, fmt.Errorf("parse response failed: %w", err)
+	}
+	if summarizeArgs != "" {
+		for i := range series {
+			series[i].unwrapSummarize(summarizeArgs)
+		}
+	}
+
+	return series, nil
+}
+
+// unwrapSummarize 去除 summarize 在序列名和 tags 中添加的内容，使按步长查询的序列与 Query 的指纹一致
+func (s *GraphiteSeries) unwrapSummarize(args string) {
+	if name, ok := strings.CutPrefix(s.Target, "summarize("); ok {
+		if name, ok = strings.CutSuffix(name, args); ok {
+			s.Target = name
+		}
+	}
+	delete(s.Tags, "summarize")
+	delete(s.Tags, "summarizeFunction")
+}
+
+// labels 使用 tags 作为标签，未开启 tag 支持时仅包含 target 名称
+func (s GraphiteSeries) labels() map[string]interface{} {
+	labels := make(map[string]interface{}, len(s.Tags)+1)
+	for k, v := range s.Tags {
+		labels[k] = v
+	}
+	labels["__name__"] = s.Target
+	return labels
+}
+
+func (g GraphiteProvider) Check() (bool, error) {
+	res, err := utilsHttp.Get(utilsHttp.CreateBasicAuthHeader(g.username, g.password), g.address+"/metrics/find?query=*", 10)
+	if err != nil {
+		logc.Error(context.Background(), fmt.Errorf("health check failed: %w", err))
+		return false, fmt.Errorf("health check failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		logc.Error(context.Background(), fmt.Errorf("unhealthy status: %d", res.StatusCode))
+		return false, fmt.Errorf("unhealthy status: %d", res.StatusCode)
+	}
+	return true, nil
+}
+
+func (g GraphiteProvider) GetExternalLabels() map[string]interface{} {
+	return g.ExternalLabels
+}
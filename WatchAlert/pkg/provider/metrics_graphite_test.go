@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"watchAlert/internal/models"
+)
+
+// startGraphiteServer 模拟 render 接口，summarize 与 Graphite 一致改写序列名并添加 tags
+func startGraphiteServer(t *testing.T) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := r.URL.Query().Get("target")
+		now := float64(time.Now().Unix())
+		var series []map[string]interface{}
+		for _, name := range []string{"servers.a.cpu", "servers.b.cpu"} {
+			tags := map[string]interface{}{"name": name}
+			if args, ok := strings.CutPrefix(target, "summarize(servers.*.cpu"); ok {
+				tags["summarize"] = "60s"
+				tags["summarizeFunction"] = "avg"
+				name = "summarize(" + name + args
+			}
+			series = append(series, map[string]interface{}{
+				"target":     name,
+				"tags":       tags,
+				"datapoints": [][]interface{}{{1.5, now - 60}, {2.5, now}},
+			})
+		}
+		json.NewEncoder(w).Encode(series)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestGraphiteQueryRangeFingerprint(t *testing.T) {
+	client, _ := NewGraphiteClient(models.AlertDataSource{HTTP: models.HTTP{URL: startGraphiteServer(t)}})
+
+	instant, err := client.Query("servers.*.cpu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	end := time.Now()
+	ranged, err := client.QueryRange("servers.*.cpu", end.Add(-time.Hour), end, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instant) != 2 || len(ranged) != 4 {
+		t.Fatalf("got %d instant and %d range points", len(instant), len(ranged))
+	}
+
+	fingerprints := make(map[string]struct{})
+	for _, m := range instant {
+		fingerprints[m.GetFingerprint()] = struct{}{}
+	}
+	for _, m := range ranged {
+		if _, ok := fingerprints[m.GetFingerprint()]; !ok {
+			t.Fatalf("range series %v does not match any instant series", m.Metric)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"watchAlert/internal/models"
+	utilsHttp "watchAlert/pkg/tools"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+type InfluxDBProvider struct {
+	ExternalLabels map[string]interface{}
+	address        string
+	username       string
+	password       string
+	config         models.DsInfluxDBConfig
+}
+
+func NewInfluxDBClient(ds models.AlertDataSource) (MetricsFactoryProvider, error) {
+	return InfluxDBProvider{
+		address:        strings.TrimSuffix(ds.HTTP.URL, "/"),
+		ExternalLabels: ds.Labels,
+		username:       ds.Auth.User,
+		password:       ds.Auth.Pass,
+		config:         ds.InfluxDBConfig,
+	}, nil
+}
+
+// InfluxQLResponse InfluxDB 1.x /query 接口返回结构
+type InfluxQLResponse struct {
+	Results []struct {
+		Series []InfluxQLSeries `json:"series"`
+		Error  string           `json:"error"`
+	} `json:"results"`
+	Error string `json:"error"`
+}
+
+type InfluxQLSeries struct {
+	Name    string            `json:"name"`
+	Tags    map[string]string `json:"tags"`
+	Columns []string          `json:"columns"`
+	Values  [][]interface{}   `json:"values"`
+}
+
+func (i InfluxDBProvider) isV2() bool {
+	return i.config.Version == "2"
+}
+
+// Query 查询最近 5 分钟的数据，取每条序列最后一个数据点；
+// 语句中可使用 $__from、$__to 变量引用查询时间范围，$__interval 引用范围查询的步长，如 GROUP BY time($__interval)
+func (i InfluxDBProvider) Query(query string) ([]Metrics, error) {
+	end := time.Now()
+	metrics, err := i.QueryRange(query, end.Add(-5*time.Minute), end, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]int)
+	var vectors []Metrics
+	for _, m := range metrics {
+		fingerprint := m.GetFingerprint()
+		idx, ok := latest[fingerprint]
+		if !ok {
+			latest[fingerprint] = len(vectors)
+			vectors = append(vectors, m)
+			continue
+		}
+		if m.Timestamp >= vectors[idx].Timestamp {
+			vectors[idx] = m
+		}
+	}
+
+	return vectors, nil
+}
+
+func (i InfluxDBProvider) QueryRange(query string, start, end time.Time, step time.Duration) ([]Metrics, error) {
+	// 未通过 $__interval 按步长聚合的语句，查询结果按步长对齐
+	aggregated := step > 0 && strings.Contains(query, "$__interval")
+	query = strings.ReplaceAll(query, "$__interval", influxInterval(step))
+
+	var (
+		metrics []Metrics
+		err     error
+	)
+	if i.isV2() {
+		metrics, err = i.queryFlux(query, start, end)
+	} else {
+		metrics, err = i.queryInfluxQL(query, start, end)
+	}
+	if err != nil || aggregated {
+		return metrics, err
+	}
+
+	return downsampleMetrics(metrics, start, step), nil
+}
+
+// influxInterval 步长对应的 InfluxQL / Flux 时长，未指定步长时默认 1m
+func influxInterval(step time.Duration) string {
+	if step <= 0 {
+		return "1m"
+	}
+	return strconv.FormatInt(stepSeconds(step), 10) + "s"
+}
+
+func (i InfluxDBProvider) queryInfluxQL(query string, start, end time.Time) ([]Metrics, error) {
+	query = strings.NewReplacer(
+		"$__from", strconv.FormatInt(start.Unix(), 10)+"s",
+		"$__to", strconv.FormatInt(end.Unix(), 10)+"s",
+	).Replace(query)
+
+	params := url.Values{}
+	params.Add("q", query)
+	params.Add("epoch", "s")
+	if i.config.Database != "" {
+		params.Add("db", i.config.Database)
+	}
+	if i.config.RetentionPolicy != "" {
+		params.Add("rp", i.config.RetentionPolicy)
+	}
+	fullURL := fmt.Sprintf("%s%s?%s", i.address, "/query", params.Encode())
+
+	resp, err := utilsHttp.Get(utilsHttp.CreateBasicAuthHeader(i.username, i.password), fullURL, 30)
+	if err != nil {
+		logc.Error(context.Background(), "InfluxDB query failed", "error", err)
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var respBody InfluxQLResponse
+	if err := utilsHttp.ParseReaderBody(resp.Body, &respBody); err != nil {
+		logc.Error(context.Background(), "Parse response failed", "error", err)
+		return nil, fmt.Errorf("parse response failed: %w", err)
+	}
+	if respBody.Error != "" {
+		return nil, fmt.Errorf("query failed: %s", respBody.Error)
+	}
+
+	var metrics []Metrics
+	for _, result := range respBody.Results {
+		if result.Error != "" {
+			return nil, fmt.Errorf("query failed: %s", result.Error)
+		}
+		for _, series := range result.Series {
+			metrics = append(metrics, series.metrics()...)
+		}
+	}
+
+	return metrics, nil
+}
+
+// metrics 以 time 列为时间戳，第一个数值列为值，tags 作为标签
+func (s InfluxQLSeries) metrics() []Metrics {
+	labels := make(map[string]interface{}, len(s.Tags)+1)
+	for k, v := range s.Tags {
+		labels[k] = v
+	}
+	labels["__name__"] = s.Name
+
+	timeIdx := -1
+	for idx, column := range s.Columns {
+		if column == "time" {
+			timeIdx = idx
+			break
+		}
+	}
+
+	var metrics []Metrics
+	for _, row := range s.Values {
+		if timeIdx < 0 || timeIdx >= len(row) {
+			continue
+		}
+		timestamp, ok := row[timeIdx].(float64)
+		if !ok {
+			continue
+		}
+
+		for idx, v := range row {
+			if idx == timeIdx {
+				continue
+			}
+			value, ok := v.(float64)
+			if !ok {
+				continue
+			}
+			metrics = append(metrics, Metrics{
+				Metric:    labels,
+				Value:     value,
+				Timestamp: timestamp,
+			})
+			break
+		}
+	}
+
+	return metrics
+}
+
+func (i InfluxDBProvider) queryFlux(query string, start, end time.Time) ([]Metrics, error) {
+	query = strings.NewReplacer(
+		"$__from", start.UTC().Format(time.RFC3339),
+		"$__to", end.UTC().Format(time.RFC3339),
+	).Replace(query)
+
+	body, _ := json.Marshal(map[string]string{
+		"query": query,
+		"type":  "flux",
+	})
+
+	params := url.Values{}
+	params.Add("org", i.config.Org)
+	fullURL := fmt.Sprintf("%s%s?%s", i.address, "/api/v2/query", params.Encode())
+
+	resp, err := utilsHttp.Post(i.fluxHeaders(), fullURL, bytes.NewReader(body), 30)
+	if err != nil {
+		logc.Error(context.Background(), "InfluxDB flux query failed", "error", err)
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code: %d, %s", resp.StatusCode, string(msg))
+	}
+
+	return parseFluxCSV(resp.Body)
+}
+
+func (i InfluxDBProvider) fluxHeaders() map[string]string {
+	headers := map[string]string{
+		"Accept": "application/csv",
+	}
+	if i.config.Token != "" {
+		headers["Authorization"] = "Token " + i.config.Token
+	} else {
+		for k, v := range utilsHttp.CreateBasicAuthHeader(i.username, i.password) {
+			headers[k] = v
+		}
+	}
+	return headers
+}
+
+// isFluxHeader 表头行首列为空，且包含 result 或 _value 列
+func isFluxHeader(record []string) bool {
+	if record[0] != "" {
+		return false
+	}
+	return slices.Contains(record, "result") || slices.Contains(record, "_value")
+}
+
+// parseFluxCSV 解析 Flux 返回的 annotated CSV，每个表头块之后的行为一组数据，
+// 除 _time、_value 及系统列以外的列均作为标签
+func parseFluxCSV(r io.Reader) ([]Metrics, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var (
+		metrics []Metrics
+		header  []string
+	)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse response failed: %w", err)
+		}
+
+		// 注解行以 # 开头；encoding/csv 会跳过表之间的空行，需按内容识别每张表的表头
+		if strings.HasPrefix(record[0], "#") {
+			continue
+		}
+		if isFluxHeader(record) {
+			header = record
+			continue
+		}
+		if header == nil {
+			continue
+		}
+
+		var (
+			labels    = make(map[string]interface{})
+			value     float64
+			timestamp float64
+			hasValue  bool
+		)
+		for idx, column := range header {
+			if idx >= len(record) {
+				break
+			}
+			switch column {
+			case "", "result", "table", "_start", "_stop":
+			case "_time":
+				t, err := time.Parse(time.RFC3339Nano, record[idx])
+				if err == nil {
+					timestamp = float64(t.Unix())
+				}
+			case "_value":
+				v, err := strconv.ParseFloat(record[idx], 64)
+				if err == nil {
+					value = v
+					hasValue = true
+				}
+			case "_measurement":
+				labels["__name__"] = record[idx]
+			default:
+				labels[column] = record[idx]
+			}
+		}
+		if !hasValue {
+			continue
+		}
+
+		metrics = append(metrics, Metrics{
+			Metric:    labels,
+			Value:     value,
+			Timestamp: timestamp,
+		})
+	}
+
+	return metrics, nil
+}
+
+func (i InfluxDBProvider) Check() (bool, error) {
+	var (
+		path    = "/ping"
+		headers = utilsHttp.CreateBasicAuthHeader(i.username, i.password)
+	)
+	if i.isV2() {
+		path = "/health"
+		headers = i.fluxHeaders()
+	}
+
+	res, err := utilsHttp.Get(headers, i.address+path, 10)
+	if err != nil {
+		logc.Error(context.Background(), fmt.Errorf("health check failed: %w", err))
+		return false, fmt.Errorf("health check failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		logc.Error(context.Background(), fmt.Errorf("unhealthy status: %d", res.StatusCode))
+		return false, fmt.Errorf("unhealthy status: %d", res.StatusCode)
+	}
+	return true, nil
+}
+
+func (i InfluxDBProvider) GetExternalLabels() map[string]interface{} {
+	return i.ExternalLabels
+}
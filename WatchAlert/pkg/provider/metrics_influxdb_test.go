@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFluxCSVMultipleTables(t *testing.T) {
+	// 两张表的列不同，表之间以空行分隔
+	body := strings.Join([]string{
+		"#datatype,string,long,dateTime:RFC3339,double,string,string",
+		"#group,false,false,false,false,true,true",
+		"#default,_result,,,,,",
+		",result,table,_time,_value,_measurement,host",
+		",,0,2024-01-01T00:00:00Z,1.5,cpu,a",
+		",,0,2024-01-01T00:01:00Z,2.5,cpu,a",
+		"",
+		"#datatype,string,long,dateTime:RFC3339,double,string,string,string",
+		"#group,false,false,false,false,true,true,true",
+		"#default,_result,,,,,,",
+		",result,table,_time,_value,_measurement,region,device",
+		",,1,2024-01-01T00:00:00Z,7,disk,eu,sda",
+		"",
+	}, "\r\n")
+
+	metrics, err := parseFluxCSV(strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics) != 3 {
+		t.Fatalf("got %d metrics, want 3: %+v", len(metrics), metrics)
+	}
+	if metrics[1].Value != 2.5 || metrics[1].Metric["host"] != "a" || metrics[1].Metric["__name__"] != "cpu" {
+		t.Fatalf("unexpected first table metric: %+v", metrics[1])
+	}
+	last := metrics[2]
+	if last.Value != 7 || last.Metric["__name__"] != "disk" || last.Metric["region"] != "eu" || last.Metric["device"] != "sda" {
+		t.Fatalf("second table labelled with wrong header: %+v", last)
+	}
+	if _, ok := last.Metric["host"]; ok || last.Timestamp != 1704067200 {
+		t.Fatalf("unexpected second table metric: %+v", last)
+	}
+}
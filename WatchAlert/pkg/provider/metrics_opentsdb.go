@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"watchAlert/internal/models"
+	utilsHttp "watchAlert/pkg/tools"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+// openTSDBDownsamplePattern 降采样表达式，如 1m-avg、30s-sum-nan
+var openTSDBDownsamplePattern = regexp.MustCompile(`^(\d+[a-z]+|all|0all)-\w+(-\w+)?$`)
+
+type OpenTSDBProvider struct {
+	ExternalLabels map[string]interface{}
+	address        string
+	username       string
+	password       string
+}
+
+func NewOpenTSDBClient(ds models.AlertDataSource) (MetricsFactoryProvider, error) {
+	return OpenTSDBProvider{
+		address:        ds.HTTP.URL,
+		ExternalLabels: ds.Labels,
+		username:       ds.Auth.User,
+		password:       ds.Auth.Pass,
+	}, nil
+}
+
+// OpenTSDBSeries /api/query 返回的单条序列，dps 格式为 {timestamp: value}
+type OpenTSDBSeries struct {
+	Metric string             `json:"metric"`
+	Tags   map[string]string  `json:"tags"`
+	Dps    map[string]float64 `json:"dps"`
+}
+
+// Query 查询最近 5 分钟的数据，取每条序列最新的数据点；expr 为 m 参数，如 sum:sys.cpu.user{host=*}
+func (o OpenTSDBProvider) Query(expr string) ([]Metrics, error) {
+	end := time.Now()
+	series, err := o.query(expr, end.Add(-5*time.Minute), end)
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics []Metrics
+	for _, s := range series {
+		points := s.points()
+		if len(points) == 0 {
+			continue
+		}
+
+		latest := points[len(points)-1]
+		latest.Metric = s.labels()
+		metrics = append(metrics, latest)
+	}
+
+	return metrics, nil
+}
+
+func (o OpenTSDBProvider) QueryRange(expr string, start, end time.Time, step time.Duration) ([]Metrics, error) {
+	series, err := o.query(openTSDBDownsample(expr, step), start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics []Metrics
+	for _, s := range series {
+		labels := s.labels()
+		for _, point := range s.points() {
+			point.Metric = labels
+			metrics = append(metrics, point)
+		}
+	}
+
+	return metrics, nil
+}
+
+// openTSDBDownsample 表达式未指定降采样时按 step 插入降采样，格式为 aggregator:downsample:metric{tags}
+func openTSDBDownsample(expr string, step time.Duration) string {
+	parts := strings.Split(expr, ":")
+	if step <= 0 || len(parts) < 2 {
+		return expr
+	}
+	for _, part := range parts[1 : len(parts)-1] {
+		if openTSDBDownsamplePattern.MatchString(part) {
+			return expr
+		}
+	}
+
+	return fmt.Sprintf("%s:%ds-avg:%s", parts[0], stepSeconds(step), strings.Join(parts[1:], ":"))
+}
+
+func (o OpenTSDBProvider) query(expr string, start, end time.Time) ([]OpenTSDBSeries, error) {
+	params := url.Values{}
+	params.Add("start", strconv.FormatInt(start.Unix(), 10))
+	params.Add("end", strconv.FormatInt(end.Unix(), 10))
+	params.Add("m", expr)
+	fullURL := fmt.Sprintf("%s%s?%s", o.address, "/api/query", params.Encode())
+
+	resp, err := utilsHttp.Get(utilsHttp.CreateBasicAuthHeader(o.username, o.password), fullURL, 30)
+	if err != nil {
+		logc.Error(context.Background(), "OpenTSDB query failed", "error", err)
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var series []OpenTSDBSeries
+	if err := utilsHttp.ParseReaderBody(resp.Body, &series); err != nil {
+		logc.Error(context.Background(), "Parse response failed", "error", err)
+		return nil, fmt.Errorf("parse response failed: %w", err)
+	}
+
+	return series, nil
+}
+
+// points 将 dps 按时间升序转换为 Metrics
+func (s OpenTSDBSeries) points() []Metrics {
+	var points []Metrics
+	for ts, value := range s.Dps {
+		timestamp, err := strconv.ParseFloat(ts, 64)
+		if err != nil {
+			continue
+		}
+		points = append(points, Metrics{
+			Value:     value,
+			Timestamp: timestamp,
+		})
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Timestamp < points[j].Timestamp
+	})
+	return points
+}
+
+func (s OpenTSDBSeries) labels() map[string]interface{} {
+	labels := make(map[string]interface{}, len(s.Tags)+1)
+	for k, v := range s.Tags {
+		labels[k] = v
+	}
+	labels["__name__"] = s.Metric
+	return labels
+}
+
+func (o OpenTSDBProvider) Check() (bool, error) {
+	res, err := utilsHttp.Get(utilsHttp.CreateBasicAuthHeader(o.username, o.password), o.address+"/api/version", 10)
+	if err != nil {
+		logc.Error(context.Background(), fmt.Errorf("health check failed: %w", err))
+		return false, fmt.Errorf("health check failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		logc.Error(context.Background(), fmt.Errorf("unhealthy status: %d", res.StatusCode))
+		return false, fmt.Errorf("unhealthy status: %d", res.StatusCode)
+	}
+	return true, nil
+}
+
+func (o OpenTSDBProvider) GetExternalLabels() map[string]interface{} {
+	return o.ExternalLabels
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/klauspost/compress/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// remoteWritePath Prometheus 需开启 --web.enable-remote-write-receiver，VictoriaMetrics 原生支持
+const remoteWritePath = "/api/v1/write"
+
+// RemoteWriteSeries 写入的单条序列，Timestamp 单位（毫秒）
+type RemoteWriteSeries struct {
+	Labels    map[string]string
+	Value     float64
+	Timestamp int64
+}
+
+// RemoteWriteClient 以 Prometheus remote-write 协议（snappy 压缩的 protobuf）写入数据
+type RemoteWriteClient struct {
+	url      string
+	username string
+	password string
+	timeout  int
+}
+
+func NewRemoteWriteClient(ds models.AlertDataSource) (RemoteWriteClient, error) {
+	switch ds.Type {
+	case PrometheusDsProvider, VictoriaMetricsDsProvider:
+	default:
+		return RemoteWriteClient{}, fmt.Errorf("datasource type %s does not support remote write", ds.Type)
+	}
+
+	timeout := int(ds.HTTP.Timeout)
+	if timeout <= 0 {
+		timeout = 10
+	}
+
+	return RemoteWriteClient{
+		url:      strings.TrimSuffix(ds.HTTP.URL, "/") + remoteWritePath,
+		username: ds.Auth.User,
+		password: ds.Auth.Pass,
+		timeout:  timeout,
+	}, nil
+}
+
+func (r RemoteWriteClient) Write(series []RemoteWriteSeries) error {
+	if len(series) == 0 {
+		return nil
+	}
+
+	headers := tools.CreateBasicAuthHeader(r.username, r.password)
+	headers["Content-Type"] = "application/x-protobuf"
+	headers["Content-Encoding"] = "snappy"
+	headers["X-Prometheus-Remote-Write-Version"] = "0.1.0"
+
+	res, err := tools.Post(headers, r.url, bytes.NewReader(encodeRemoteWriteBody(series)), r.timeout)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode/100 != 2 {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("remote write failed, Status: %d, Msg: %s", res.StatusCode, string(b))
+	}
+
+	return nil
+}
+
+// encodeRemoteWriteBody 请求体为 snappy block 格式压缩的 WriteRequest，非 framed 格式
+func encodeRemoteWriteBody(series []RemoteWriteSeries) []byte {
+	return snappy.Encode(nil, encodeWriteRequest(series))
+}
+
+// encodeWriteRequest 按 prompb.WriteRequest 编码：
+// WriteRequest{1: repeated TimeSeries}，TimeSeries{1: repeated Label, 2: repeated Sample}，
+// Label{1: name, 2: value}，Sample{1: double value, 2: int64 timestamp}
+func encodeWriteRequest(series []RemoteWriteSeries) []byte {
+	var req []byte
+	for _, s := range series {
+		// 协议要求标签按名称排序
+		names := make([]string, 0, len(s.Labels))
+		for name := range s.Labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var ts []byte
+		for _, name := range names {
+			var label []byte
+			label = protowire.AppendTag(label, 1, protowire.BytesType)
+			label = protowire.AppendString(label, name)
+			label = protowire.AppendTag(label, 2, protowire.BytesType)
+			label = protowire.AppendString(label, s.Labels[name])
+
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, label)
+		}
+
+		var sample []byte
+		sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+		sample = protowire.AppendFixed64(sample, math.Float64bits(s.Value))
+		sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+		sample = protowire.AppendVarint(sample, uint64(s.Timestamp))
+
+		ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+		ts = protowire.AppendBytes(ts, sample)
+
+		req = protowire.AppendTag(req, 1, protowire.BytesType)
+		req = protowire.AppendBytes(req, ts)
+	}
+
+	return req
+}
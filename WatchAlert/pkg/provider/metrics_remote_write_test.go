@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/klauspost/compress/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// decodedSeries 按 prompb.TimeSeries 解码的序列，标签保留编码顺序
+type decodedSeries struct {
+	Labels    [][2]string
+	Value     float64
+	Timestamp int64
+}
+
+// decodeFields 解码 protobuf 消息，返回各字段的原始值
+func decodeFields(t *testing.T, b []byte, fn func(num protowire.Number, typ protowire.Type, b []byte) int) {
+	t.Helper()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("invalid tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		n = fn(num, typ, b)
+		if n < 0 {
+			t.Fatalf("invalid field %d: %v", num, protowire.ParseError(n))
+		}
+		b = b[n:]
+	}
+}
+
+func decodeWriteRequest(t *testing.T, b []byte) []decodedSeries {
+	var result []decodedSeries
+	decodeFields(t, b, func(_ protowire.Number, _ protowire.Type, b []byte) int {
+		ts, n := protowire.ConsumeBytes(b)
+		var series decodedSeries
+		decodeFields(t, ts, func(num protowire.Number, _ protowire.Type, b []byte) int {
+			msg, n := protowire.ConsumeBytes(b)
+			switch num {
+			case 1:
+				var label [2]string
+				decodeFields(t, msg, func(num protowire.Number, _ protowire.Type, b []byte) int {
+					v, n := protowire.ConsumeString(b)
+					label[num-1] = v
+					return n
+				})
+				series.Labels = append(series.Labels, label)
+			case 2:
+				decodeFields(t, msg, func(num protowire.Number, typ protowire.Type, b []byte) int {
+					if num == 1 {
+						v, n := protowire.ConsumeFixed64(b)
+						series.Value = math.Float64frombits(v)
+						return n
+					}
+					v, n := protowire.ConsumeVarint(b)
+					series.Timestamp = int64(v)
+					return n
+				})
+			}
+			return n
+		})
+		result = append(result, series)
+		return n
+	})
+	return result
+}
+
+func TestEncodeWriteRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		series []RemoteWriteSeries
+		want   []decodedSeries
+	}{
+		{name: "empty", series: nil},
+		{
+			name: "labels sorted by name",
+			series: []RemoteWriteSeries{{
+				Labels:    map[string]string{"job": "api", "__name__": "http_requests:rate5m", "instance": "a:9090", "Zone": "z1"},
+				Value:     1.5,
+				Timestamp: 1700000000000,
+			}},
+			want: []decodedSeries{{
+				Labels:    [][2]string{{"Zone", "z1"}, {"__name__", "http_requests:rate5m"}, {"instance", "a:9090"}, {"job", "api"}},
+				Value:     1.5,
+				Timestamp: 1700000000000,
+			}},
+		},
+		{
+			name: "multiple series keep order",
+			series: []RemoteWriteSeries{
+				{Labels: map[string]string{"__name__": "b"}, Value: -2, Timestamp: 2},
+				{Labels: map[string]string{"__name__": "a", "le": "+Inf"}, Value: math.Inf(1), Timestamp: 1},
+			},
+			want: []decodedSeries{
+				{Labels: [][2]string{{"__name__", "b"}}, Value: -2, Timestamp: 2},
+				{Labels: [][2]string{{"__name__", "a"}, {"le", "+Inf"}}, Value: math.Inf(1), Timestamp: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeWriteRequest(t, encodeWriteRequest(tt.series))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("decoded = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeRemoteWriteBody(t *testing.T) {
+	tests := []struct {
+		name   string
+		series []RemoteWriteSeries
+	}{
+		{name: "single series", series: []RemoteWriteSeries{{Labels: map[string]string{"__name__": "up"}, Value: 1, Timestamp: 1}}},
+		{name: "many series", series: func() []RemoteWriteSeries {
+			var series []RemoteWriteSeries
+			for i := 0; i < 1000; i++ {
+				series = append(series, RemoteWriteSeries{Labels: map[string]string{"__name__": "up", "job": "node"}, Value: float64(i), Timestamp: int64(i)})
+			}
+			return series
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := encodeRemoteWriteBody(tt.series)
+			// 接收端使用 snappy block 格式解码
+			decoded, err := snappy.Decode(nil, body)
+			if err != nil {
+				t.Fatalf("snappy decode failed: %v", err)
+			}
+			if want := encodeWriteRequest(tt.series); !reflect.DeepEqual(decoded, want) {
+				t.Fatalf("decoded body does not match write request")
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"fmt"
+	"watchAlert/internal/models"
 	"watchAlert/pkg/tools"
 )
 
@@ -9,12 +11,74 @@ const (
 	HTTPEndpointProvider string = "HTTP"
 	TCPEndpointProvider  string = "TCP"
 	SSLEndpointProvider  string = "SSL"
+	// 多步骤 HTTP 事务
+	HTTPTransactionEndpointProvider string = "HTTPTransaction"
+	DNSEndpointProvider             string = "DNS"
+	GRPCEndpointProvider            string = "GRPC"
+	WebSocketEndpointProvider       string = "WebSocket"
 )
 
 type EndpointFactoryProvider interface {
 	Pilot(option EndpointOption) (EndpointValue, error)
 }
 
+// NewEndpointProvider 获取拨测类型对应的拨测器
+func NewEndpointProvider(ruleType string) (EndpointFactoryProvider, error) {
+	switch ruleType {
+	case ICMPEndpointProvider:
+		return NewEndpointPinger(), nil
+	case HTTPEndpointProvider:
+		return NewEndpointHTTPer(), nil
+	case TCPEndpointProvider:
+		return NewEndpointTcper(), nil
+	case SSLEndpointProvider:
+		return NewEndpointSSLer(), nil
+	case HTTPTransactionEndpointProvider:
+		return NewEndpointHTTPTransactioner(), nil
+	case DNSEndpointProvider:
+		return NewEndpointDNSer(), nil
+	case GRPCEndpointProvider:
+		return NewEndpointGRPCer(), nil
+	case WebSocketEndpointProvider:
+		return NewEndpointWebSocketer(), nil
+	}
+	return nil, fmt.Errorf("unsupported rule type: %s", ruleType)
+}
+
+// NewEndpointOption 由拨测规则配置构建拨测参数
+func NewEndpointOption(config models.ProbingEndpointConfig) EndpointOption {
+	return EndpointOption{
+		Endpoint: config.Endpoint,
+		Timeout:  config.Strategy.Timeout,
+		HTTP: Ehttp{
+			Method:         config.HTTP.Method,
+			Header:         config.HTTP.Header,
+			Body:           config.HTTP.Body,
+			Assertions:     config.HTTP.Assertions,
+			AssertionLogic: config.HTTP.AssertionLogic,
+		},
+		ICMP: Eicmp{
+			Interval: config.ICMP.Interval,
+			Count:    config.ICMP.Count,
+		},
+		Steps: config.Transaction.Steps,
+		DNS: Edns{
+			RecordType:      config.DNS.RecordType,
+			Resolver:        config.DNS.Resolver,
+			ExpectedAnswers: config.DNS.ExpectedAnswers,
+		},
+		GRPC: Egrpc{
+			Service: config.GRPC.Service,
+			TLS:     config.GRPC.TLS,
+		},
+		WebSocket: Ewebsocket{
+			Header: config.WebSocket.Header,
+			Send:   config.WebSocket.Send,
+			Expect: config.WebSocket.Expect,
+		},
+	}
+}
+
 type EndpointValue map[string]any
 
 func (e EndpointValue) GetLabels() map[string]interface{} {
@@ -30,12 +94,19 @@ type EndpointOption struct {
 	Timeout  int    `json:"timeout"`
 	HTTP     Ehttp  `json:"http"`
 	ICMP     Eicmp  `json:"icmp"`
+	// 多步骤 HTTP 事务的步骤
+	Steps     []models.HTTPTransactionStep `json:"steps"`
+	DNS       Edns                         `json:"dns"`
+	GRPC      Egrpc                        `json:"grpc"`
+	WebSocket Ewebsocket                   `json:"webSocket"`
 }
 
 type Ehttp struct {
-	Method string            `json:"method"`
-	Header map[string]string `json:"header"`
-	Body   string            `json:"body"`
+	Method         string                 `json:"method"`
+	Header         map[string]string      `json:"header"`
+	Body           string                 `json:"body"`
+	Assertions     []models.HTTPAssertion `json:"assertions"`
+	AssertionLogic string                 `json:"assertionLogic"`
 }
 
 type Eicmp struct {
@@ -43,6 +114,23 @@ type Eicmp struct {
 	Count    int `json:"count"`
 }
 
+type Edns struct {
+	RecordType      string   `json:"recordType"`
+	Resolver        string   `json:"resolver"`
+	ExpectedAnswers []string `json:"expectedAnswers"`
+}
+
+type Egrpc struct {
+	Service string `json:"service"`
+	TLS     bool   `json:"tls"`
+}
+
+type Ewebsocket struct {
+	Header map[string]string `json:"header"`
+	Send   string            `json:"send"`
+	Expect string            `json:"expect"`
+}
+
 type PingerInformation struct {
 	Address string `json:"address"`
 	// 发送的数据包数量
@@ -95,3 +183,42 @@ type TcperInformation struct {
 	// 错误信息（拨测失败时）
 	ErrorMessage string
 }
+
+type DnserInformation struct {
+	Address string
+	// 解析耗时, ms
+	ResolveTime float64
+	// 解析结果
+	Answers []string
+	// 是否解析成功且包含期望的结果
+	IsSuccessful bool
+	ErrorMessage string
+}
+
+type GrpcerInformation struct {
+	Address string
+	// 响应时间, ms
+	ResponseTime float64
+	// grpc.health.v1 服务状态，0 UNKNOWN、1 SERVING、2 NOT_SERVING、3 SERVICE_UNKNOWN
+	ServingStatus float64
+	IsSuccessful  bool
+	ErrorMessage  string
+}
+
+type WebSocketerInformation struct {
+	Address string
+	// 建立连接耗时, ms
+	ConnectTime float64
+	// 发送消息至收到响应的耗时, ms
+	ResponseTime float64
+	IsSuccessful bool
+	ErrorMessage string
+}
+
+// boolToFloat 拨测结果需可用 Strategy.Field 比较，布尔值转换为 1/0
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
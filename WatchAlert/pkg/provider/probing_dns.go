@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+)
+
+type Dnser struct{}
+
+func NewEndpointDNSer() EndpointFactoryProvider {
+	return Dnser{}
+}
+
+func (p Dnser) Pilot(option EndpointOption) (EndpointValue, error) {
+	timeout := option.Timeout
+	if timeout <= 0 {
+		timeout = 5
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	resolver := net.DefaultResolver
+	if option.DNS.Resolver != "" {
+		server := option.DNS.Resolver
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+
+	startTime := time.Now()
+	answers, err := lookupDNSRecord(ctx, resolver, option.DNS.RecordType, option.Endpoint)
+	detail := DnserInformation{
+		Address:     option.Endpoint,
+		ResolveTime: float64(time.Since(startTime).Milliseconds()),
+		Answers:     answers,
+	}
+
+	switch {
+	case err != nil:
+		detail.ErrorMessage = err.Error()
+	case len(answers) == 0:
+		detail.ErrorMessage = "no answer"
+	default:
+		detail.IsSuccessful = true
+		for _, expected := range option.DNS.ExpectedAnswers {
+			if !slices.Contains(answers, strings.TrimSuffix(expected, ".")) {
+				detail.IsSuccessful = false
+				detail.ErrorMessage = fmt.Sprintf("expected answer %s not found", expected)
+				break
+			}
+		}
+	}
+
+	return convertDnserToEndpointValue(detail), nil
+}
+
+// lookupDNSRecord 按记录类型解析，域名结果去除末尾的 .
+func lookupDNSRecord(ctx context.Context, resolver *net.Resolver, recordType, host string) ([]string, error) {
+	var answers []string
+	switch strings.ToUpper(recordType) {
+	case "", "A", "AAAA":
+		network := "ip4"
+		if strings.EqualFold(recordType, "AAAA") {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, strings.TrimSuffix(cname, "."))
+	case "MX":
+		records, err := resolver.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range records {
+			answers = append(answers, strings.TrimSuffix(mx.Host, "."))
+		}
+	case "TXT":
+		records, err := resolver.LookupTXT(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, records...)
+	case "NS":
+		records, err := resolver.LookupNS(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range records {
+			answers = append(answers, strings.TrimSuffix(ns.Host, "."))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported record type: %s", recordType)
+	}
+
+	return answers, nil
+}
+
+func convertDnserToEndpointValue(detail DnserInformation) EndpointValue {
+	return EndpointValue{
+		"address":      detail.Address,
+		"ResolveTime":  detail.ResolveTime,
+		"AnswerCount":  float64(len(detail.Answers)),
+		"Answers":      strings.Join(detail.Answers, ","),
+		"IsSuccessful": boolToFloat(detail.IsSuccessful),
+		"ErrorMessage": detail.ErrorMessage,
+	}
+}
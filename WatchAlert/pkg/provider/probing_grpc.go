@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// grpc.health.v1 Check 方法路径
+const grpcHealthCheckPath = "/grpc.health.v1.Health/Check"
+
+// grpc.health.v1.HealthCheckResponse.ServingStatus
+const grpcServingStatusServing = 1
+
+type Grpcer struct{}
+
+func NewEndpointGRPCer() EndpointFactoryProvider {
+	return Grpcer{}
+}
+
+// Pilot 通过 HTTP/2 直接调用标准的 grpc.health.v1.Health/Check，无需引入 gRPC 客户端
+func (p Grpcer) Pilot(option EndpointOption) (EndpointValue, error) {
+	timeout := option.Timeout
+	if timeout <= 0 {
+		timeout = 5
+	}
+
+	detail := GrpcerInformation{Address: option.Endpoint}
+	startTime := time.Now()
+	status, err := grpcHealthCheck(option.Endpoint, option.GRPC, time.Duration(timeout)*time.Second)
+	detail.ResponseTime = float64(time.Since(startTime).Milliseconds())
+	if err != nil {
+		detail.ErrorMessage = err.Error()
+	} else {
+		detail.ServingStatus = float64(status)
+		detail.IsSuccessful = status == grpcServingStatusServing
+		if !detail.IsSuccessful {
+			detail.ErrorMessage = fmt.Sprintf("serving status: %d", status)
+		}
+	}
+
+	return convertGrpcerToEndpointValue(detail), nil
+}
+
+func grpcHealthCheck(endpoint string, option Egrpc, timeout time.Duration) (int, error) {
+	transport := &http2.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	scheme := "https"
+	if !option.TLS {
+		// 明文 h2c
+		scheme = "http"
+		transport.AllowHTTP = true
+		transport.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		}
+	}
+	// 每次探测使用独立的连接，结束后关闭，避免空闲连接随探测周期累积
+	defer transport.CloseIdleConnections()
+	client := &http.Client{Transport: transport, Timeout: timeout}
+
+	// HealthCheckRequest{1: service}，按 gRPC 帧格式添加 1 字节压缩标记和 4 字节长度
+	var msg []byte
+	if option.Service != "" {
+		msg = protowire.AppendTag(msg, 1, protowire.BytesType)
+		msg = protowire.AppendString(msg, option.Service)
+	}
+	frame := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+	frame = append(frame, msg...)
+
+	req, err := http.NewRequest(http.MethodPost, scheme+"://"+endpoint+grpcHealthCheckPath, bytes.NewReader(frame))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("http status: %d", res.StatusCode)
+	}
+	// 无响应体时 grpc-status 位于响应头中
+	grpcStatus := res.Trailer.Get("Grpc-Status")
+	if grpcStatus == "" {
+		grpcStatus = res.Header.Get("Grpc-Status")
+	}
+	if grpcStatus != "0" {
+		return 0, fmt.Errorf("grpc status: %s, message: %s", grpcStatus,
+			strings.TrimSpace(res.Trailer.Get("Grpc-Message")+res.Header.Get("Grpc-Message")))
+	}
+
+	return parseGrpcHealthResponse(body)
+}
+
+// parseGrpcHealthResponse 解析 HealthCheckResponse{1: status}
+func parseGrpcHealthResponse(body []byte) (int, error) {
+	if len(body) < 5 {
+		return 0, fmt.Errorf("invalid grpc response")
+	}
+	size := binary.BigEndian.Uint32(body[1:5])
+	if body[0] != 0 || int(size) > len(body)-5 {
+		return 0, fmt.Errorf("invalid grpc response frame")
+	}
+
+	msg := body[5 : 5+size]
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		msg = msg[n:]
+		if num == 1 && typ == protowire.VarintType {
+			v, n := protowire.ConsumeVarint(msg)
+			if n < 0 {
+				return 0, protowire.ParseError(n)
+			}
+			return int(v), nil
+		}
+		n = protowire.ConsumeFieldValue(num, typ, msg)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		msg = msg[n:]
+	}
+
+	// 字段为默认值时不编码，即 UNKNOWN
+	return 0, nil
+}
+
+func convertGrpcerToEndpointValue(detail GrpcerInformation) EndpointValue {
+	return EndpointValue{
+		"address":       detail.Address,
+		"ResponseTime":  detail.ResponseTime,
+		"ServingStatus": detail.ServingStatus,
+		"IsSuccessful":  boolToFloat(detail.IsSuccessful),
+		"ErrorMessage":  detail.ErrorMessage,
+	}
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"bytes"
+	"crypto/tls"
 	"net/http"
 	"time"
 	"watchAlert/pkg/tools"
@@ -19,6 +20,10 @@ func NewEndpointHTTPer() EndpointFactoryProvider {
 }
 
 func (h HTTPer) Pilot(option EndpointOption) (EndpointValue, error) {
+	if len(option.HTTP.Assertions) > 0 {
+		return h.pilotWithAssertions(option)
+	}
+
 	var (
 		ev      EndpointValue
 		res     *http.Response
@@ -69,3 +74,29 @@ func convertHTTPerToEndpointValue(detail HttperInformation) EndpointValue {
 		"headers":    headers,
 	}
 }
+
+// 响应体读取上限
+const probingMaxBodySize = 4 << 20
+
+// probingHTTPTransport 拨测共用的连接池，与 tools.Get 一致跳过证书校验，
+// 拨测配置不影响连接参数，共用连接池避免每次拨测新建连接池导致空闲连接累积
+var probingHTTPTransport = &http.Transport{
+	TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
+	Proxy:                 http.ProxyFromEnvironment,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          100,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: 1 * time.Second,
+}
+
+// newProbingHTTPClient 拨测使用的 HTTP 客户端
+func newProbingHTTPClient(timeout int) *http.Client {
+	if timeout <= 0 {
+		timeout = 10
+	}
+	return &http.Client{
+		Timeout:   time.Duration(timeout) * time.Second,
+		Transport: probingHTTPTransport,
+	}
+}
@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+// 最大跟随重定向次数，与 net/http 默认一致
+const probingMaxRedirects = 10
+
+// httpAssertionContext 断言所需的响应信息
+type httpAssertionContext struct {
+	res       *http.Response
+	body      []byte
+	latency   float64
+	redirects int
+	json      interface{}
+	jsonErr   error
+	jsonReady bool
+}
+
+func (c *httpAssertionContext) getJSON() (interface{}, error) {
+	if !c.jsonReady {
+		c.jsonErr = json.Unmarshal(c.body, &c.json)
+		c.jsonReady = true
+	}
+	return c.json, c.jsonErr
+}
+
+// pilotWithAssertions 执行 HTTP 拨测并校验断言，AssertionsPassed 为 1 表示通过，未通过的断言记录在 FailedAssertions 中
+func (h HTTPer) pilotWithAssertions(option EndpointOption) (EndpointValue, error) {
+	method := strings.ToUpper(option.HTTP.Method)
+	if method == "" {
+		method = GetHTTPMethod
+	}
+	req, err := http.NewRequest(method, option.Endpoint, strings.NewReader(option.HTTP.Body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range option.HTTP.Header {
+		req.Header.Set(k, v)
+	}
+
+	ac := &httpAssertionContext{}
+	client := newProbingHTTPClient(option.Timeout)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= probingMaxRedirects {
+			return fmt.Errorf("stopped after %d redirects", probingMaxRedirects)
+		}
+		ac.redirects = len(via)
+		return nil
+	}
+
+	// 请求失败时站点不可用，视为断言未通过，与事务拨测的失败步骤一致
+	start := time.Now()
+	res, err := client.Do(req)
+	if err != nil {
+		return requestFailedAssertionValue(option.Endpoint, time.Since(start), ac.redirects, err), nil
+	}
+	defer res.Body.Close()
+	ac.res = res
+	ac.body, err = io.ReadAll(io.LimitReader(res.Body, probingMaxBodySize))
+	ac.latency = float64(time.Since(start).Milliseconds())
+	if err != nil {
+		return requestFailedAssertionValue(option.Endpoint, time.Since(start), ac.redirects, err), nil
+	}
+
+	var (
+		failed []string
+		passed int
+	)
+	for _, assertion := range option.HTTP.Assertions {
+		if msg := evalHTTPAssertion(assertion, ac); msg != "" {
+			failed = append(failed, msg)
+			continue
+		}
+		passed++
+	}
+
+	ok := len(failed) == 0
+	if strings.EqualFold(option.HTTP.AssertionLogic, models.HTTPAssertionLogicOr) {
+		ok = passed > 0
+	}
+
+	headers := make(map[string]interface{})
+	for k, v := range res.Header {
+		headers[k] = v[0]
+	}
+	ev := EndpointValue{
+		"address":          res.Request.URL.String(),
+		"StatusCode":       float64(res.StatusCode),
+		"Latency":          ac.latency,
+		"RedirectCount":    float64(ac.redirects),
+		"AssertionsPassed": boolToFloat(ok),
+		"FailedAssertions": strings.Join(failed, "; "),
+		"headers":          headers,
+	}
+	if days, ok := certDaysRemaining(res); ok {
+		ev["CertDaysRemaining"] = days
+	}
+
+	return ev, nil
+}
+
+// requestFailedAssertionValue 请求失败时的拨测结果，错误信息记录在 FailedAssertions 中
+func requestFailedAssertionValue(endpoint string, latency time.Duration, redirects int, err error) EndpointValue {
+	return EndpointValue{
+		"address":          endpoint,
+		"StatusCode":       float64(0),
+		"Latency":          float64(latency.Milliseconds()),
+		"RedirectCount":    float64(redirects),
+		"AssertionsPassed": float64(0),
+		"FailedAssertions": "request failed: " + err.Error(),
+		"headers":          map[string]interface{}{},
+	}
+}
+
+// evalHTTPAssertion 校验单个断言，返回未通过的说明
+func evalHTTPAssertion(assertion models.HTTPAssertion, ac *httpAssertionContext) string {
+	switch assertion.Type {
+	case models.HTTPAssertionStatusCode:
+		return compareAssertion("status code", assertion.Operator, strconv.Itoa(ac.res.StatusCode), assertion.Value)
+	case models.HTTPAssertionLatency:
+		return compareAssertion("latency", assertion.Operator, strconv.FormatFloat(ac.latency, 'f', -1, 64), assertion.Value)
+	case models.HTTPAssertionBodyContains:
+		if !strings.Contains(string(ac.body), assertion.Value) {
+			return fmt.Sprintf("body does not contain %q", assertion.Value)
+		}
+	case models.HTTPAssertionBodyNotContains:
+		if strings.Contains(string(ac.body), assertion.Value) {
+			return fmt.Sprintf("body contains %q", assertion.Value)
+		}
+	case models.HTTPAssertionBodyRegex:
+		re, err := regexp.Compile(assertion.Value)
+		if err != nil {
+			return fmt.Sprintf("invalid regex %q: %s", assertion.Value, err.Error())
+		}
+		if !re.Match(ac.body) {
+			return fmt.Sprintf("body does not match %q", assertion.Value)
+		}
+	case models.HTTPAssertionJSONPath:
+		data, err := ac.getJSON()
+		if err != nil {
+			return fmt.Sprintf("body is not json: %s", err.Error())
+		}
+		value := tools.GetJsonPathValue(data, assertion.Target)
+		if value == nil {
+			return fmt.Sprintf("json path %s not found", assertion.Target)
+		}
+		return compareAssertion(assertion.Target, assertion.Operator, tools.FormatJsonValue(value), assertion.Value)
+	case models.HTTPAssertionHeader:
+		if actual := ac.res.Header.Get(assertion.Target); actual != assertion.Value {
+			return fmt.Sprintf("header %s is %q, expected %q", assertion.Target, actual, assertion.Value)
+		}
+	case models.HTTPAssertionCertDays:
+		days, ok := certDaysRemaining(ac.res)
+		if !ok {
+			return "no tls certificate"
+		}
+		return compareAssertion("cert days remaining", assertion.Operator, strconv.FormatFloat(days, 'f', -1, 64), assertion.Value)
+	case models.HTTPAssertionRedirectCount:
+		return compareAssertion("redirect count", assertion.Operator, strconv.Itoa(ac.redirects), assertion.Value)
+	default:
+		return fmt.Sprintf("unsupported assertion type %s", assertion.Type)
+	}
+
+	return ""
+}
+
+// compareAssertion 比较实际值与期望值，两者均为数值时按数值比较，否则仅支持 == 与 !=
+func compareAssertion(name, operator, actual, expected string) string {
+	if operator == "" {
+		operator = "=="
+	}
+
+	var ok bool
+	a, errA := strconv.ParseFloat(actual, 64)
+	e, errE := strconv.ParseFloat(expected, 64)
+	if errA == nil && errE == nil {
+		switch operator {
+		case "==":
+			ok = a == e
+		case "!=":
+			ok = a != e
+		case ">":
+			ok = a > e
+		case ">=":
+			ok = a >= e
+		case "<":
+			ok = a < e
+		case "<=":
+			ok = a <= e
+		default:
+			return fmt.Sprintf("unsupported operator %s", operator)
+		}
+	} else {
+		switch operator {
+		case "==":
+			ok = actual == expected
+		case "!=":
+			ok = actual != expected
+		default:
+			return fmt.Sprintf("%s is %q, operator %s requires numeric values", name, actual, operator)
+		}
+	}
+
+	if !ok {
+		return fmt.Sprintf("%s is %s, expected %s %s", name, actual, operator, expected)
+	}
+	return ""
+}
+
+// certDaysRemaining 证书剩余有效天数
+func certDaysRemaining(res *http.Response) (float64, bool) {
+	if res.TLS == nil || len(res.TLS.PeerCertificates) == 0 {
+		return 0, false
+	}
+	return float64(int64(time.Until(res.TLS.PeerCertificates[0].NotAfter).Hours() / 24)), true
+}
@@ -0,0 +1,209 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+// TransactionStepsKey 事务拨测结果中各步骤明细的字段
+const TransactionStepsKey = "steps"
+
+var transactionVariableRe = regexp.MustCompile(`\$\{(\w+)\}`)
+
+type HTTPTransactioner struct{}
+
+func NewEndpointHTTPTransactioner() EndpointFactoryProvider {
+	return HTTPTransactioner{}
+}
+
+// Pilot 按顺序执行事务步骤，步骤间共享 Cookie 及提取的变量，任一步骤失败即终止
+func (h HTTPTransactioner) Pilot(option EndpointOption) (EndpointValue, error) {
+	if len(option.Steps) == 0 {
+		return nil, fmt.Errorf("http transaction has no steps")
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	client := newProbingHTTPClient(option.Timeout)
+	client.Jar = jar
+
+	var (
+		variables = make(map[string]string)
+		steps     = make([]EndpointValue, 0, len(option.Steps))
+		latency   int64
+		failed    EndpointValue
+	)
+	for i, step := range option.Steps {
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step-%d", i+1)
+		}
+
+		result := runTransactionStep(client, step, variables)
+		latency += int64(result["Latency"].(float64))
+		steps = append(steps, result)
+		if result["IsSuccessful"] != float64(1) {
+			failed = result
+			break
+		}
+	}
+
+	address := option.Endpoint
+	if address == "" {
+		address = option.Steps[0].URL
+	}
+
+	ev := EndpointValue{
+		"address":           address,
+		"Latency":           float64(latency),
+		"IsSuccessful":      boolToFloat(failed == nil),
+		"FailedStep":        "",
+		"ErrorMessage":      "",
+		TransactionStepsKey: steps,
+	}
+	if failed != nil {
+		ev["FailedStep"] = failed["step"]
+		ev["ErrorMessage"] = failed["ErrorMessage"]
+	}
+
+	return ev, nil
+}
+
+// runTransactionStep 执行单个步骤，返回步骤结果，成功时将提取的变量写入 variables
+func runTransactionStep(client *http.Client, step models.HTTPTransactionStep, variables map[string]string) EndpointValue {
+	render := func(s string) string {
+		return transactionVariableRe.ReplaceAllStringFunc(s, func(match string) string {
+			if v, ok := variables[match[2:len(match)-1]]; ok {
+				return v
+			}
+			return match
+		})
+	}
+
+	result := EndpointValue{
+		"step":         step.Name,
+		"address":      render(step.URL),
+		"StatusCode":   float64(0),
+		"Latency":      float64(0),
+		"IsSuccessful": float64(0),
+		"ErrorMessage": "",
+	}
+	fail := func(format string, args ...any) EndpointValue {
+		result["ErrorMessage"] = fmt.Sprintf(format, args...)
+		return result
+	}
+
+	method := strings.ToUpper(step.Method)
+	if method == "" {
+		method = GetHTTPMethod
+	}
+	req, err := http.NewRequest(method, render(step.URL), strings.NewReader(render(step.Body)))
+	if err != nil {
+		return fail("build request failed: %s", err.Error())
+	}
+	for k, v := range step.Header {
+		req.Header.Set(k, render(v))
+	}
+
+	start := time.Now()
+	res, err := client.Do(req)
+	if err != nil {
+		result["Latency"] = float64(time.Since(start).Milliseconds())
+		return fail("request failed: %s", err.Error())
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(res.Body, probingMaxBodySize))
+	result["Latency"] = float64(time.Since(start).Milliseconds())
+	result["StatusCode"] = float64(res.StatusCode)
+	if err != nil {
+		return fail("read body failed: %s", err.Error())
+	}
+
+	if msg := assertTransactionStep(step.Assert, res, body, result["Latency"].(float64)); msg != "" {
+		return fail("%s", msg)
+	}
+
+	for _, extract := range step.Extracts {
+		value, err := extractTransactionVariable(extract, res, body)
+		if err != nil {
+			return fail("extract %s failed: %s", extract.Name, err.Error())
+		}
+		variables[extract.Name] = value
+	}
+
+	result["IsSuccessful"] = float64(1)
+	return result
+}
+
+// assertTransactionStep 校验步骤断言，返回首个不满足的断言说明
+func assertTransactionStep(assert models.HTTPStepAssertion, res *http.Response, body []byte, latency float64) string {
+	if len(assert.StatusCodes) > 0 {
+		if !slices.Contains(assert.StatusCodes, res.StatusCode) {
+			return fmt.Sprintf("status code %d not in %v", res.StatusCode, assert.StatusCodes)
+		}
+	} else if res.StatusCode/100 != 2 {
+		return fmt.Sprintf("status code %d is not 2xx", res.StatusCode)
+	}
+
+	for k, v := range assert.Headers {
+		if !strings.Contains(res.Header.Get(k), v) {
+			return fmt.Sprintf("header %s does not contain %q", k, v)
+		}
+	}
+
+	if assert.BodyContains != "" && !strings.Contains(string(body), assert.BodyContains) {
+		return fmt.Sprintf("body does not contain %q", assert.BodyContains)
+	}
+
+	if assert.MaxLatency > 0 && latency > float64(assert.MaxLatency) {
+		return fmt.Sprintf("latency %vms exceeds %dms", latency, assert.MaxLatency)
+	}
+
+	return ""
+}
+
+func extractTransactionVariable(extract models.HTTPStepExtract, res *http.Response, body []byte) (string, error) {
+	switch {
+	case extract.Header != "":
+		value := res.Header.Get(extract.Header)
+		if value == "" {
+			return "", fmt.Errorf("header %s not found", extract.Header)
+		}
+		return value, nil
+	case extract.JSONPath != "":
+		var data interface{}
+		if err := json.Unmarshal(body, &data); err != nil {
+			return "", fmt.Errorf("body is not json: %s", err.Error())
+		}
+		value := tools.GetJsonPathValue(data, extract.JSONPath)
+		if value == nil {
+			return "", fmt.Errorf("json path %s not found", extract.JSONPath)
+		}
+		return tools.FormatJsonValue(value), nil
+	case extract.Regex != "":
+		re, err := regexp.Compile(extract.Regex)
+		if err != nil {
+			return "", err
+		}
+		match := re.FindSubmatch(body)
+		if match == nil {
+			return "", fmt.Errorf("regex %s not matched", extract.Regex)
+		}
+		if len(match) > 1 {
+			return string(match[1]), nil
+		}
+		return string(match[0]), nil
+	default:
+		return "", fmt.Errorf("no extract source")
+	}
+}
@@ -38,7 +38,7 @@ func convertTcperToEndpointValue(detail TcperInformation) EndpointValue {
 	return EndpointValue{
 		"address":      detail.Address,
 		"ResponseTime": detail.ResponseTime,
-		"IsSuccessful": detail.IsSuccessful,
+		"IsSuccessful": boolToFloat(detail.IsSuccessful),
 		"ErrorMessage": detail.ErrorMessage,
 	}
 }
@@ -0,0 +1,352 @@
+package provider
+
+import (
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"watchAlert/internal/models"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"golang.org/x/net/websocket"
+)
+
+// startDNSServer 启动本地 UDP DNS 服务，对 A 记录查询返回 answer
+func startDNSServer(t *testing.T, answer [4]byte) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var msg dnsmessage.Message
+			if err := msg.Unpack(buf[:n]); err != nil || len(msg.Questions) == 0 {
+				continue
+			}
+			msg.Header.Response = true
+			msg.Header.Authoritative = true
+			if q := msg.Questions[0]; q.Type == dnsmessage.TypeA {
+				msg.Answers = []dnsmessage.Resource{{
+					Header: dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: q.Class, TTL: 60},
+					Body:   &dnsmessage.AResource{A: answer},
+				}}
+			}
+			res, err := msg.Pack()
+			if err != nil {
+				continue
+			}
+			conn.WriteTo(res, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestDNSerPilot(t *testing.T) {
+	resolver := startDNSServer(t, [4]byte{10, 0, 0, 1})
+
+	ev, err := NewEndpointDNSer().Pilot(EndpointOption{
+		Endpoint: "svc.example.com",
+		Timeout:  2,
+		DNS:      Edns{RecordType: "A", Resolver: resolver, ExpectedAnswers: []string{"10.0.0.1"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev["IsSuccessful"] != float64(1) || ev["AnswerCount"] != float64(1) || ev["Answers"] != "10.0.0.1" {
+		t.Fatalf("unexpected result: %v", ev)
+	}
+
+	ev, _ = NewEndpointDNSer().Pilot(EndpointOption{
+		Endpoint: "svc.example.com",
+		Timeout:  2,
+		DNS:      Edns{Resolver: resolver, ExpectedAnswers: []string{"10.0.0.2"}},
+	})
+	if ev["IsSuccessful"] != float64(0) {
+		t.Fatalf("expected mismatch, got: %v", ev)
+	}
+}
+
+// grpcHealthHandler 模拟 grpc.health.v1.Health/Check 返回 status
+func grpcHealthHandler(status byte) http.Handler {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != grpcHealthCheckPath || r.Header.Get("Content-Type") != "application/grpc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// HealthCheckResponse{1: status}
+		msg := []byte{0x08, status}
+		frame := make([]byte, 5)
+		binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "Grpc-Status")
+		w.Write(append(frame, msg...))
+		w.Header().Set("Grpc-Status", "0")
+	})
+
+	return h2c.NewHandler(handler, &http2.Server{})
+}
+
+// startGRPCHealthServer 启动本地 h2c 服务，模拟 grpc.health.v1.Health/Check 返回 status
+func startGRPCHealthServer(t *testing.T, status byte) string {
+	srv := httptest.NewServer(grpcHealthHandler(status))
+	t.Cleanup(srv.Close)
+	return strings.TrimPrefix(srv.URL, "http://")
+}
+
+// startConnCountingServer 启动本地服务并统计服务端尚未关闭的连接数
+func startConnCountingServer(t *testing.T, handler http.Handler) (*httptest.Server, *atomic.Int64) {
+	open := new(atomic.Int64)
+	srv := httptest.NewUnstartedServer(handler)
+	srv.Listener = countingListener{Listener: srv.Listener, open: open}
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv, open
+}
+
+type countingListener struct {
+	net.Listener
+	open *atomic.Int64
+}
+
+func (l countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	l.open.Add(1)
+	return &countingConn{Conn: conn, open: l.open}, nil
+}
+
+type countingConn struct {
+	net.Conn
+	open *atomic.Int64
+	once sync.Once
+}
+
+func (c *countingConn) Close() error {
+	c.once.Do(func() { c.open.Add(-1) })
+	return c.Conn.Close()
+}
+
+// waitConnsClosed 等待服务端连接全部关闭，客户端关闭连接后服务端需读到 EOF 才会关闭
+func waitConnsClosed(t *testing.T, open *atomic.Int64) {
+	deadline := time.Now().Add(2 * time.Second)
+	for open.Load() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d connections still open after probe", open.Load())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestGrpcerPilot(t *testing.T) {
+	ev, err := NewEndpointGRPCer().Pilot(EndpointOption{Endpoint: startGRPCHealthServer(t, 1), Timeout: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev["IsSuccessful"] != float64(1) || ev["ServingStatus"] != float64(1) {
+		t.Fatalf("unexpected result: %v", ev)
+	}
+
+	ev, _ = NewEndpointGRPCer().Pilot(EndpointOption{Endpoint: startGRPCHealthServer(t, 2), Timeout: 2})
+	if ev["IsSuccessful"] != float64(0) || ev["ServingStatus"] != float64(2) {
+		t.Fatalf("expected not serving, got: %v", ev)
+	}
+}
+
+func TestGrpcerPilotClosesConnections(t *testing.T) {
+	srv, open := startConnCountingServer(t, grpcHealthHandler(1))
+	for i := 0; i < 3; i++ {
+		if _, err := NewEndpointGRPCer().Pilot(EndpointOption{Endpoint: strings.TrimPrefix(srv.URL, "http://"), Timeout: 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitConnsClosed(t, open)
+}
+
+func TestWebSocketerPilot(t *testing.T) {
+	srv := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		var msg string
+		if err := websocket.Message.Receive(conn, &msg); err != nil {
+			return
+		}
+		websocket.Message.Send(conn, "pong:"+msg)
+	}))
+	defer srv.Close()
+	endpoint := "ws://" + strings.TrimPrefix(srv.URL, "http://")
+
+	ev, err := NewEndpointWebSocketer().Pilot(EndpointOption{
+		Endpoint:  endpoint,
+		Timeout:   2,
+		WebSocket: Ewebsocket{Send: "ping", Expect: "pong:ping"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev["IsSuccessful"] != float64(1) {
+		t.Fatalf("unexpected result: %v", ev)
+	}
+
+	ev, _ = NewEndpointWebSocketer().Pilot(EndpointOption{
+		Endpoint:  endpoint,
+		Timeout:   2,
+		WebSocket: Ewebsocket{Send: "ping", Expect: "other"},
+	})
+	if ev["IsSuccessful"] != float64(0) {
+		t.Fatalf("expected mismatch, got: %v", ev)
+	}
+}
+
+func TestWebSocketerPilotHandshakeTimeout(t *testing.T) {
+	// 接受 TCP 连接但不响应握手
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	start := time.Now()
+	ev, _ := NewEndpointWebSocketer().Pilot(EndpointOption{Endpoint: "ws://" + ln.Addr().String(), Timeout: 1})
+	if ev["IsSuccessful"] != float64(0) || ev["ErrorMessage"] == "" {
+		t.Fatalf("expected handshake timeout, got: %v", ev)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("handshake did not honour timeout, took %s", elapsed)
+	}
+}
+
+func TestTcperPilot(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+
+	ev, _ := NewEndpointTcper().Pilot(EndpointOption{Endpoint: addr, Timeout: 1})
+	if ev["IsSuccessful"] != float64(1) {
+		t.Fatalf("unexpected result: %v", ev)
+	}
+
+	ln.Close()
+	ev, _ = NewEndpointTcper().Pilot(EndpointOption{Endpoint: addr, Timeout: 1})
+	if ev["IsSuccessful"] != float64(0) {
+		t.Fatalf("expected failure, got: %v", ev)
+	}
+}
+
+func TestHTTPerPilotWithAssertions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/status", http.StatusFound)
+	})
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Version", "v2")
+		w.Write([]byte(`{"status":"ok","items":[{"count":3}],"banner":"service error"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	assertions := []models.HTTPAssertion{
+		{Type: models.HTTPAssertionStatusCode, Value: "200"},
+		{Type: models.HTTPAssertionJSONPath, Target: "$.status", Value: "ok"},
+		{Type: models.HTTPAssertionJSONPath, Target: "$.items[0].count", Operator: ">=", Value: "2"},
+		{Type: models.HTTPAssertionHeader, Target: "X-Version", Value: "v2"},
+		{Type: models.HTTPAssertionRedirectCount, Operator: "<=", Value: "1"},
+		{Type: models.HTTPAssertionBodyRegex, Value: `"status":\s*"ok"`},
+	}
+	ev, err := NewEndpointHTTPer().Pilot(EndpointOption{
+		Endpoint: srv.URL + "/old",
+		Timeout:  2,
+		HTTP:     Ehttp{Method: GetHTTPMethod, Assertions: assertions},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev["AssertionsPassed"] != float64(1) || ev["RedirectCount"] != float64(1) {
+		t.Fatalf("unexpected result: %v", ev)
+	}
+
+	failing := append(assertions, models.HTTPAssertion{Type: models.HTTPAssertionBodyNotContains, Value: "error"})
+	ev, _ = NewEndpointHTTPer().Pilot(EndpointOption{
+		Endpoint: srv.URL + "/old",
+		Timeout:  2,
+		HTTP:     Ehttp{Method: GetHTTPMethod, Assertions: failing},
+	})
+	if ev["AssertionsPassed"] != float64(0) || !strings.Contains(ev["FailedAssertions"].(string), `body contains "error"`) {
+		t.Fatalf("expected failed assertion, got: %v", ev)
+	}
+
+	ev, _ = NewEndpointHTTPer().Pilot(EndpointOption{
+		Endpoint: srv.URL + "/old",
+		Timeout:  2,
+		HTTP:     Ehttp{Method: GetHTTPMethod, Assertions: failing, AssertionLogic: models.HTTPAssertionLogicOr},
+	})
+	if ev["AssertionsPassed"] != float64(1) {
+		t.Fatalf("expected OR logic to pass, got: %v", ev)
+	}
+}
+
+func TestHTTPerPilotReusesConnections(t *testing.T) {
+	srv, open := startConnCountingServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	for i := 0; i < 5; i++ {
+		ev, err := NewEndpointHTTPer().Pilot(EndpointOption{
+			Endpoint: srv.URL,
+			Timeout:  2,
+			HTTP:     Ehttp{Method: GetHTTPMethod, Assertions: []models.HTTPAssertion{{Type: models.HTTPAssertionStatusCode, Value: "200"}}},
+		})
+		if err != nil || ev["AssertionsPassed"] != float64(1) {
+			t.Fatalf("unexpected result: %v, err: %v", ev, err)
+		}
+	}
+	if n := open.Load(); n > 1 {
+		t.Fatalf("%d connections opened for sequential probes, want 1", n)
+	}
+}
+
+func TestHTTPerPilotWithAssertionsRequestFailed(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoint := "http://" + ln.Addr().String()
+	ln.Close()
+
+	ev, err := NewEndpointHTTPer().Pilot(EndpointOption{
+		Endpoint: endpoint,
+		Timeout:  1,
+		HTTP:     Ehttp{Method: GetHTTPMethod, Assertions: []models.HTTPAssertion{{Type: models.HTTPAssertionStatusCode, Value: "200"}}},
+	})
+	if err != nil {
+		t.Fatalf("request failure should be reported as a failed assertion, got error: %v", err)
+	}
+	if ev["AssertionsPassed"] != float64(0) || !strings.HasPrefix(ev["FailedAssertions"].(string), "request failed:") {
+		t.Fatalf("unexpected result: %v", ev)
+	}
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+type WebSocketer struct{}
+
+func NewEndpointWebSocketer() EndpointFactoryProvider {
+	return WebSocketer{}
+}
+
+// Pilot 建立 WebSocket 连接，配置了发送消息时校验收到的响应
+func (p WebSocketer) Pilot(option EndpointOption) (EndpointValue, error) {
+	timeout := option.Timeout
+	if timeout <= 0 {
+		timeout = 5
+	}
+
+	detail := WebSocketerInformation{Address: option.Endpoint}
+	if err := webSocketCheck(option.Endpoint, option.WebSocket, time.Duration(timeout)*time.Second, &detail); err != nil {
+		detail.ErrorMessage = err.Error()
+	} else {
+		detail.IsSuccessful = true
+	}
+
+	return convertWebSocketerToEndpointValue(detail), nil
+}
+
+// webSocketCheck timeout 覆盖建连、握手及消息收发的整个过程
+func webSocketCheck(endpoint string, option Ewebsocket, timeout time.Duration, detail *WebSocketerInformation) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	origin := "http://" + u.Host
+	if u.Scheme == "wss" {
+		origin = "https://" + u.Host
+	}
+
+	config, err := websocket.NewConfig(endpoint, origin)
+	if err != nil {
+		return err
+	}
+	for k, v := range option.Header {
+		config.Header.Set(k, v)
+	}
+
+	startTime := time.Now()
+	deadline := startTime.Add(timeout)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	// 握手阶段服务端无响应时由 ctx 超时中断
+	conn, err := config.DialContext(ctx)
+	detail.ConnectTime = float64(time.Since(startTime).Milliseconds())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if option.Send == "" {
+		return nil
+	}
+
+	startTime = time.Now()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	if err := websocket.Message.Send(conn, option.Send); err != nil {
+		return fmt.Errorf("send message failed: %s", err.Error())
+	}
+	var msg string
+	if err := websocket.Message.Receive(conn, &msg); err != nil {
+		return fmt.Errorf("receive message failed: %s", err.Error())
+	}
+	detail.ResponseTime = float64(time.Since(startTime).Milliseconds())
+
+	if option.Expect != "" && !strings.Contains(msg, option.Expect) {
+		return fmt.Errorf("message does not contain %q", option.Expect)
+	}
+
+	return nil
+}
+
+func convertWebSocketerToEndpointValue(detail WebSocketerInformation) EndpointValue {
+	return EndpointValue{
+		"address":      detail.Address,
+		"ConnectTime":  detail.ConnectTime,
+		"ResponseTime": detail.ResponseTime,
+		"IsSuccessful": boolToFloat(detail.IsSuccessful),
+		"ErrorMessage": detail.ErrorMessage,
+	}
+}
@@ -7,21 +7,37 @@ import (
 
 const (
 	JaegerDsProviderName string = "Jaeger"
+	TempoDsProviderName  string = "Tempo"
+	ZipkinDsProviderName string = "Zipkin"
 )
 
 type TracesFactoryProvider interface {
 	Query(options TraceQueryOptions) ([]Traces, error)
+	QuerySpans(options TraceQueryOptions) ([]Span, error)
 	Check() (bool, error)
 	GetJaegerService() (JaegerServiceData, error)
+	GetTraceURL(traceId string) string
 	GetExternalLabels() map[string]interface{}
 }
 
 type TraceQueryOptions struct {
-	Tags    string `json:"tags,omitempty"`    // 查询标签
-	Service string `json:"service,omitempty"` // 服务名称
-	Limit   int64  `json:"limit,omitempty"`   // 要返回的最大条目数
-	StartAt int64  `json:"startAt,omitempty"` // 查询的开始时间，以微秒 Unix 表示。
-	EndAt   int64  `json:"endAt,omitempty"`   // 查询的结束时间，以微秒 Unix 表示。
+	Tags      string `json:"tags,omitempty"`      // 查询标签
+	Service   string `json:"service,omitempty"`   // 服务名称
+	Operation string `json:"operation,omitempty"` // 接口名称
+	Query     string `json:"query,omitempty"`     // Tempo TraceQL 查询语句
+	Limit     int64  `json:"limit,omitempty"`     // 要返回的最大条目数
+	StartAt   int64  `json:"startAt,omitempty"`   // 查询的开始时间，以微秒 Unix 表示。
+	EndAt     int64  `json:"endAt,omitempty"`     // 查询的结束时间，以微秒 Unix 表示。
+}
+
+// Span 用于计算延迟分位数和错误率的 Span 信息
+type Span struct {
+	TraceId   string
+	SpanId    string
+	Service   string
+	Operation string
+	Duration  int64 // 耗时，单位（微秒）
+	Error     bool
 }
 
 type Traces struct {
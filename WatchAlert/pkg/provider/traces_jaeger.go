@@ -3,6 +3,7 @@ package provider
 import (
 	"fmt"
 	"io"
+	"net/url"
 	"strconv"
 	"time"
 	"watchAlert/internal/models"
@@ -31,7 +32,44 @@ type JaegerResult struct {
 }
 
 type JaegerData struct {
-	TraceId string `json:"traceID"`
+	TraceId   string                   `json:"traceID"`
+	Spans     []JaegerSpan             `json:"spans"`
+	Processes map[string]JaegerProcess `json:"processes"`
+}
+
+type JaegerSpan struct {
+	TraceId       string      `json:"traceID"`
+	SpanId        string      `json:"spanID"`
+	OperationName string      `json:"operationName"`
+	Duration      int64       `json:"duration"`
+	ProcessId     string      `json:"processID"`
+	Tags          []JaegerTag `json:"tags"`
+}
+
+type JaegerProcess struct {
+	ServiceName string `json:"serviceName"`
+}
+
+type JaegerTag struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// isError span 存在 error=true 标签或 OTel 状态为 ERROR 时视为错误
+func (s JaegerSpan) isError() bool {
+	for _, tag := range s.Tags {
+		switch tag.Key {
+		case "error":
+			if fmt.Sprintf("%v", tag.Value) == "true" {
+				return true
+			}
+		case "otel.status_code":
+			if fmt.Sprintf("%v", tag.Value) == "ERROR" {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func (j JaegerDsProvider) Query(options TraceQueryOptions) ([]Traces, error) {
@@ -73,6 +111,69 @@ func (j JaegerDsProvider) Query(options TraceQueryOptions) ([]Traces, error) {
 	return data, nil
 }
 
+// QuerySpans 查询服务的 Span 列表，Operation 不为空时仅返回该接口的 Span
+func (j JaegerDsProvider) QuerySpans(options TraceQueryOptions) ([]Span, error) {
+	if options.Limit == 0 {
+		options.Limit = models.TraceDefaultSampleLimit
+	}
+
+	params := url.Values{}
+	params.Add("service", options.Service)
+	params.Add("start", strconv.FormatInt(options.StartAt, 10))
+	params.Add("end", strconv.FormatInt(options.EndAt, 10))
+	params.Add("limit", strconv.FormatInt(options.Limit, 10))
+	if options.Operation != "" {
+		params.Add("operation", options.Operation)
+	}
+	if options.Tags != "" {
+		params.Add("tags", options.Tags)
+	}
+
+	res, err := tools.Get(nil, j.url+"/api/traces?"+params.Encode(), 30)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		b, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("后端服务请求异常, Status: %d, Msg: %s", res.StatusCode, string(b))
+	}
+
+	var jaegerResult JaegerResult
+	if err := tools.ParseReaderBody(res.Body, &jaegerResult); err != nil {
+		return nil, err
+	}
+
+	var spans []Span
+	for _, t := range jaegerResult.Data {
+		for _, s := range t.Spans {
+			service := t.Processes[s.ProcessId].ServiceName
+			if service != options.Service {
+				continue
+			}
+			if options.Operation != "" && s.OperationName != options.Operation {
+				continue
+			}
+
+			spans = append(spans, Span{
+				TraceId:   s.TraceId,
+				SpanId:    s.SpanId,
+				Service:   service,
+				Operation: s.OperationName,
+				Duration:  s.Duration,
+				Error:     s.isError(),
+			})
+		}
+	}
+
+	return spans, nil
+}
+
+func (j JaegerDsProvider) GetTraceURL(traceId string) string {
+	return fmt.Sprintf("%s/trace/%s", j.url, traceId)
+}
+
 func (j JaegerDsProvider) Check() (bool, error) {
 	res, err := tools.Get(nil, j.url, 10)
 	if err != nil {
@@ -90,8 +191,7 @@ type JaegerServiceData struct {
 }
 
 func (j JaegerDsProvider) GetJaegerService() (JaegerServiceData, error) {
-	url := j.url + "/api/services"
-	res, err := tools.Get(nil, url, 10)
+	res, err := tools.Get(nil, j.url+"/api/services", 10)
 	if err != nil {
 		return JaegerServiceData{}, err
 	}
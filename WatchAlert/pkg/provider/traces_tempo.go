@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+type TempoDsProvider struct {
+	ExternalLabels map[string]interface{}
+	url            string
+	username       string
+	password       string
+}
+
+func NewTempoClient(datasource models.AlertDataSource) (TracesFactoryProvider, error) {
+	return TempoDsProvider{
+		url:            strings.TrimSuffix(datasource.HTTP.URL, "/"),
+		username:       datasource.Auth.User,
+		password:       datasource.Auth.Pass,
+		ExternalLabels: datasource.Labels,
+	}, nil
+}
+
+type TempoSearchResult struct {
+	Traces []TempoTrace `json:"traces"`
+}
+
+type TempoTrace struct {
+	TraceId         string         `json:"traceID"`
+	RootServiceName string         `json:"rootServiceName"`
+	SpanSet         *TempoSpanSet  `json:"spanSet"`
+	SpanSets        []TempoSpanSet `json:"spanSets"`
+}
+
+type TempoSpanSet struct {
+	Spans []TempoSpan `json:"spans"`
+}
+
+type TempoSpan struct {
+	SpanId        string           `json:"spanID"`
+	Name          string           `json:"name"`
+	DurationNanos string           `json:"durationNanos"`
+	Attributes    []TempoAttribute `json:"attributes"`
+}
+
+type TempoAttribute struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+// attribute 获取 span 属性值，Tempo 按类型返回 stringValue、intValue 等字段
+func (s TempoSpan) attribute(key string) string {
+	for _, attr := range s.Attributes {
+		if attr.Key != key {
+			continue
+		}
+		for _, v := range attr.Value {
+			return fmt.Sprintf("%v", v)
+		}
+	}
+	return ""
+}
+
+// traceQL 未指定 TraceQL 时按服务和接口生成查询语句，并追加 select 以返回接口名称与状态
+func (t TempoDsProvider) traceQL(options TraceQueryOptions, onlyError bool) string {
+	query := options.Query
+	if query == "" {
+		conditions := []string{fmt.Sprintf("resource.service.name = %q", options.Service)}
+		if options.Operation != "" {
+			conditions = append(conditions, fmt.Sprintf("name = %q", options.Operation))
+		}
+		if onlyError {
+			conditions = append(conditions, "status = error")
+		}
+		query = fmt.Sprintf("{ %s }", strings.Join(conditions, " && "))
+	}
+
+	if !strings.Contains(query, "select(") {
+		query += " | select(name, status, resource.service.name)"
+	}
+	return query
+}
+
+func (t TempoDsProvider) search(query string, options TraceQueryOptions, limit int64) (TempoSearchResult, error) {
+	params := url.Values{}
+	params.Add("q", query)
+	params.Add("limit", strconv.FormatInt(limit, 10))
+	params.Add("spss", "100")
+	if options.StartAt != 0 {
+		params.Add("start", strconv.FormatInt(options.StartAt/1e6, 10))
+	}
+	if options.EndAt != 0 {
+		params.Add("end", strconv.FormatInt(options.EndAt/1e6, 10))
+	}
+
+	res, err := tools.Get(tools.CreateBasicAuthHeader(t.username, t.password), t.url+"/api/search?"+params.Encode(), 30)
+	if err != nil {
+		return TempoSearchResult{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		b, _ := io.ReadAll(res.Body)
+		return TempoSearchResult{}, fmt.Errorf("后端服务请求异常, Status: %d, Msg: %s", res.StatusCode, string(b))
+	}
+
+	var result TempoSearchResult
+	if err := tools.ParseReaderBody(res.Body, &result); err != nil {
+		return TempoSearchResult{}, err
+	}
+
+	return result, nil
+}
+
+// Query 查询存在错误 Span 的链路
+func (t TempoDsProvider) Query(options TraceQueryOptions) ([]Traces, error) {
+	if options.Limit == 0 {
+		options.Limit = 100
+	}
+
+	result, err := t.search(t.traceQL(options, true), options, options.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []Traces
+	for _, trace := range result.Traces {
+		data = append(data, Traces{
+			Service: options.Service,
+			TraceId: trace.TraceId,
+		})
+	}
+
+	return data, nil
+}
+
+func (t TempoDsProvider) QuerySpans(options TraceQueryOptions) ([]Span, error) {
+	if options.Limit == 0 {
+		options.Limit = models.TraceDefaultSampleLimit
+	}
+
+	result, err := t.search(t.traceQL(options, false), options, options.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var spans []Span
+	for _, trace := range result.Traces {
+		spanSets := trace.SpanSets
+		if len(spanSets) == 0 && trace.SpanSet != nil {
+			spanSets = []TempoSpanSet{*trace.SpanSet}
+		}
+
+		for _, spanSet := range spanSets {
+			for _, s := range spanSet.Spans {
+				operation := s.Name
+				if operation == "" {
+					operation = s.attribute("name")
+				}
+				if options.Operation != "" && operation != options.Operation {
+					continue
+				}
+
+				service := s.attribute("service.name")
+				if service == "" {
+					service = trace.RootServiceName
+				}
+
+				duration, _ := strconv.ParseInt(s.DurationNanos, 10, 64)
+				spans = append(spans, Span{
+					TraceId:   trace.TraceId,
+					SpanId:    s.SpanId,
+					Service:   service,
+					Operation: operation,
+					Duration:  duration / 1e3,
+					Error:     s.attribute("status") == "error",
+				})
+			}
+		}
+	}
+
+	return spans, nil
+}
+
+func (t TempoDsProvider) GetTraceURL(traceId string) string {
+	return fmt.Sprintf("%s/api/traces/%s", t.url, traceId)
+}
+
+func (t TempoDsProvider) Check() (bool, error) {
+	res, err := tools.Get(tools.CreateBasicAuthHeader(t.username, t.password), t.url+"/ready", 10)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return false, fmt.Errorf("unhealthy status: %d", res.StatusCode)
+	}
+	return true, nil
+}
+
+// GetJaegerService 获取 Tempo 中的服务列表
+func (t TempoDsProvider) GetJaegerService() (JaegerServiceData, error) {
+	res, err := tools.Get(tools.CreateBasicAuthHeader(t.username, t.password), t.url+"/api/search/tag/service.name/values", 10)
+	if err != nil {
+		return JaegerServiceData{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		b, _ := io.ReadAll(res.Body)
+		return JaegerServiceData{}, fmt.Errorf("后端服务请求异常, Status: %d, Msg: %s", res.StatusCode, string(b))
+	}
+
+	var resData struct {
+		TagValues []string `json:"tagValues"`
+	}
+	if err := tools.ParseReaderBody(res.Body, &resData); err != nil {
+		return JaegerServiceData{}, fmt.Errorf("json.Unmarshal failed, %s", err.Error())
+	}
+
+	return JaegerServiceData{Data: resData.TagValues}, nil
+}
+
+func (t TempoDsProvider) GetExternalLabels() map[string]interface{} {
+	return t.ExternalLabels
+}
@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+type ZipkinDsProvider struct {
+	ExternalLabels map[string]interface{}
+	url            string
+	username       string
+	password       string
+}
+
+func NewZipkinClient(datasource models.AlertDataSource) (TracesFactoryProvider, error) {
+	return ZipkinDsProvider{
+		url:            strings.TrimSuffix(datasource.HTTP.URL, "/"),
+		username:       datasource.Auth.User,
+		password:       datasource.Auth.Pass,
+		ExternalLabels: datasource.Labels,
+	}, nil
+}
+
+type ZipkinSpan struct {
+	TraceId       string            `json:"traceId"`
+	Id            string            `json:"id"`
+	Name          string            `json:"name"`
+	Duration      int64             `json:"duration"`
+	LocalEndpoint ZipkinEndpoint    `json:"localEndpoint"`
+	Tags          map[string]string `json:"tags"`
+}
+
+type ZipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+// traces 查询链路列表，annotationQuery 为 Zipkin 标签过滤条件，如 error 或 http.status_code=500
+func (z ZipkinDsProvider) traces(options TraceQueryOptions, annotationQuery string) ([][]ZipkinSpan, error) {
+	params := url.Values{}
+	params.Add("serviceName", options.Service)
+	params.Add("limit", strconv.FormatInt(options.Limit, 10))
+	if options.Operation != "" {
+		params.Add("spanName", options.Operation)
+	}
+	if annotationQuery != "" {
+		params.Add("annotationQuery", annotationQuery)
+	}
+	// Zipkin 使用毫秒时间戳
+	if options.EndAt != 0 {
+		params.Add("endTs", strconv.FormatInt(options.EndAt/1e3, 10))
+		if options.StartAt != 0 {
+			params.Add("lookback", strconv.FormatInt((options.EndAt-options.StartAt)/1e3, 10))
+		}
+	}
+
+	res, err := tools.Get(tools.CreateBasicAuthHeader(z.username, z.password), z.url+"/api/v2/traces?"+params.Encode(), 30)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		b, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("后端服务请求异常, Status: %d, Msg: %s", res.StatusCode, string(b))
+	}
+
+	var result [][]ZipkinSpan
+	if err := tools.ParseReaderBody(res.Body, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Query 查询存在错误 Span 的链路
+func (z ZipkinDsProvider) Query(options TraceQueryOptions) ([]Traces, error) {
+	if options.Limit == 0 {
+		options.Limit = 100
+	}
+
+	annotationQuery := options.Tags
+	if annotationQuery == "" {
+		annotationQuery = "error"
+	}
+
+	result, err := z.traces(options, annotationQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []Traces
+	for _, trace := range result {
+		if len(trace) == 0 {
+			continue
+		}
+		data = append(data, Traces{
+			Service: options.Service,
+			TraceId: trace[0].TraceId,
+		})
+	}
+
+	return data, nil
+}
+
+func (z ZipkinDsProvider) QuerySpans(options TraceQueryOptions) ([]Span, error) {
+	if options.Limit == 0 {
+		options.Limit = models.TraceDefaultSampleLimit
+	}
+
+	result, err := z.traces(options, options.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	var spans []Span
+	for _, trace := range result {
+		for _, s := range trace {
+			if s.LocalEndpoint.ServiceName != options.Service {
+				continue
+			}
+			if options.Operation != "" && s.Name != options.Operation {
+				continue
+			}
+
+			_, isError := s.Tags["error"]
+			spans = append(spans, Span{
+				TraceId:   s.TraceId,
+				SpanId:    s.Id,
+				Service:   s.LocalEndpoint.ServiceName,
+				Operation: s.Name,
+				Duration:  s.Duration,
+				Error:     isError,
+			})
+		}
+	}
+
+	return spans, nil
+}
+
+func (z ZipkinDsProvider) GetTraceURL(traceId string) string {
+	return fmt.Sprintf("%s/zipkin/traces/%s", z.url, traceId)
+}
+
+func (z ZipkinDsProvider) Check() (bool, error) {
+	res, err := tools.Get(tools.CreateBasicAuthHeader(z.username, z.password), z.url+"/api/v2/services", 10)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return false, fmt.Errorf("unhealthy status: %d", res.StatusCode)
+	}
+	return true, nil
+}
+
+// GetJaegerService 获取 Zipkin 中的服务列表
+func (z ZipkinDsProvider) GetJaegerService() (JaegerServiceData, error) {
+	res, err := tools.Get(tools.CreateBasicAuthHeader(z.username, z.password), z.url+"/api/v2/services", 10)
+	if err != nil {
+		return JaegerServiceData{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		b, _ := io.ReadAll(res.Body)
+		return JaegerServiceData{}, fmt.Errorf("后端服务请求异常, Status: %d, Msg: %s", res.StatusCode, string(b))
+	}
+
+	var services []string
+	if err := tools.ParseReaderBody(res.Body, &services); err != nil {
+		return JaegerServiceData{}, fmt.Errorf("json.Unmarshal failed, %s", err.Error())
+	}
+
+	return JaegerServiceData{Data: services}, nil
+}
+
+func (z ZipkinDsProvider) GetExternalLabels() map[string]interface{} {
+	return z.ExternalLabels
+}
@@ -0,0 +1,79 @@
+package aliyun
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"watchAlert/internal/ctx"
+	"watchAlert/pkg/tools"
+
+	openapi "github.com/alibabacloud-go/darabonba-openapi/v2/client"
+	util "github.com/alibabacloud-go/tea-utils/v2/service"
+	"github.com/alibabacloud-go/tea/tea"
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+type Sms struct {
+	Endpoint        string `json:"endpoint,omitempty"`
+	AccessKeyId     string `json:"accessKeyId,omitempty"`
+	AccessKeySecret string `json:"accessKeySecret,omitempty"`
+	SignName        string `json:"signName,omitempty"`
+	TemplateCode    string `json:"templateCode,omitempty"`
+	Client          *openapi.Client
+}
+
+func (s *Sms) CreateClient() error {
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = "dysmsapi.aliyuncs.com"
+	}
+
+	config := &openapi.Config{
+		AccessKeyId:     tea.String(s.AccessKeyId),
+		AccessKeySecret: tea.String(s.AccessKeySecret),
+		Endpoint:        tea.String(endpoint),
+	}
+	client, err := openapi.NewClient(config)
+	if err != nil {
+		return err
+	}
+	s.Client = client
+	return nil
+}
+
+// Send 调用 SendSms 接口，一次请求发送给全部号码
+func (s *Sms) Send(message string, phoneNumbers []string) error {
+	req := &openapi.OpenApiRequest{
+		Query: map[string]*string{
+			"PhoneNumbers":  tea.String(strings.Join(phoneNumbers, ",")),
+			"SignName":      tea.String(s.SignName),
+			"TemplateCode":  tea.String(s.TemplateCode),
+			"TemplateParam": tea.String(tools.JsonMarshalToString(map[string]string{"content": message})),
+		},
+	}
+	params := &openapi.Params{
+		Action:      tea.String("SendSms"),
+		Version:     tea.String("2017-05-25"),
+		Protocol:    tea.String("HTTPS"),
+		Pathname:    tea.String("/"),
+		Method:      tea.String("POST"),
+		AuthType:    tea.String("AK"),
+		Style:       tea.String("RPC"),
+		ReqBodyType: tea.String("formData"),
+		BodyType:    tea.String("json"),
+	}
+
+	result, err := s.Client.CallApi(params, req, &util.RuntimeOptions{})
+	if err != nil {
+		return err
+	}
+
+	body, _ := result["body"].(map[string]interface{})
+	if code := fmt.Sprintf("%v", body["Code"]); code != "OK" {
+		logc.Errorf(ctx.Ctx, "短信发送失败，号码：%v，原因：%v", phoneNumbers, body["Message"])
+		return errors.New(fmt.Sprintf("%s: %v", code, body["Message"]))
+	}
+
+	logc.Info(ctx.Ctx, fmt.Sprintf("短信发送成功，号码：%v", phoneNumbers))
+	return nil
+}
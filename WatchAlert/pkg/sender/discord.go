@@ -0,0 +1,46 @@
+package sender
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+type (
+	// DiscordSender Discord 发送策略
+	DiscordSender struct{}
+)
+
+func NewDiscordSender() SendInter {
+	return &DiscordSender{}
+}
+
+func (d *DiscordSender) Send(params SendParams) error {
+	return d.post(params.Hook, params.Content)
+}
+
+func (d *DiscordSender) Test(params SendParams) error {
+	msg := models.DiscordMsgTemplate{
+		Content: RobotTestContent,
+	}
+	return d.post(params.Hook, tools.JsonMarshalToString(msg))
+}
+
+func (d *DiscordSender) post(hook, content string) error {
+	res, err := tools.Post(nil, hook, bytes.NewReader([]byte(content)), 10)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// 成功时返回 204 No Content
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		bodyByte, _ := io.ReadAll(res.Body)
+		return errors.New(fmt.Sprintf("Discord response status: %d, body: %s", res.StatusCode, string(bodyByte)))
+	}
+
+	return nil
+}
@@ -20,10 +20,13 @@ func (e *EmailSender) Send(params SendParams) error {
 		return errors.New("获取 系统配置/邮箱配置 失败: " + err.Error())
 	}
 	eCli := client.NewEmailClient(setting.EmailConfig.ServerAddress, setting.EmailConfig.Email, setting.EmailConfig.Token, setting.EmailConfig.Port)
-	if params.IsRecovered {
-		params.Email.Subject = params.Email.Subject + "「已恢复」"
-	} else {
-		params.Email.Subject = params.Email.Subject + "「报警中」"
+	// 报告不区分告警状态
+	if !params.IsReport {
+		if params.IsRecovered {
+			params.Email.Subject = params.Email.Subject + "「已恢复」"
+		} else {
+			params.Email.Subject = params.Email.Subject + "「报警中」"
+		}
 	}
 	err = eCli.Send(params.Email.To, params.Email.CC, params.Email.Subject, []byte(params.Content))
 	if err != nil {
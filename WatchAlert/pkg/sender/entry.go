@@ -18,8 +18,11 @@ type (
 		// 基础
 		TenantId string
 		EventId  string
-		RuleName string
-		Severity string
+		// 告警事件所在故障中心及指纹
+		FaultCenterId string
+		Fingerprint   string
+		RuleName      string
+		Severity      string
 		// 通知
 		NoticeType string
 		NoticeId   string
@@ -36,6 +39,10 @@ type (
 		PhoneNumber []string
 		// 签名
 		Sign string `json:"sign,omitempty"`
+		// Telegram chat_id
+		ChatId string
+		// 报告类消息，邮件标题不追加告警状态
+		IsReport bool
 	}
 
 	// SendInter 发送通知的接口
@@ -55,7 +62,17 @@ func Sender(ctx *ctx.Context, sendParams SendParams) error {
 		return fmt.Errorf("Send alarm failed, %s", err.Error())
 	}
 
-	// 发送通知
+	// 发送通知，失败时加入重试队列
+	if err := send(ctx, sender, sendParams); err != nil {
+		enqueueRetry(ctx, sendParams, err)
+		return err
+	}
+
+	return nil
+}
+
+// send 发送通知并记录发送结果
+func send(ctx *ctx.Context, sender SendInter, sendParams SendParams) error {
 	if err := sender.Send(sendParams); err != nil {
 		addRecord(ctx, sendParams, 1, sendParams.Content, err.Error())
 		return fmt.Errorf("Send alarm failed to %s, err: %s", sendParams.NoticeType, err.Error())
@@ -101,8 +118,18 @@ func senderFactory(noticeType string) (SendInter, error) {
 		return NewWebHookSender(), nil
 	case "PhoneCall":
 		return NewPhoneCallSender(), nil
+	case "SMS":
+		return NewSmsSender(), nil
 	case "Slack":
 		return NewSlackSender(), nil
+	case "Teams":
+		return NewTeamsSender(), nil
+	case "Telegram":
+		return NewTelegramSender(), nil
+	case "Discord":
+		return NewDiscordSender(), nil
+	case "Mattermost":
+		return NewMattermostSender(), nil
 	default:
 		return nil, fmt.Errorf("无效的通知类型: %s", noticeType)
 	}
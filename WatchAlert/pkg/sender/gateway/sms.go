@@ -0,0 +1,47 @@
+package gateway
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"watchAlert/pkg/tools"
+)
+
+type Sms struct {
+	Url          string            `json:"url,omitempty"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	BodyTemplate string            `json:"bodyTemplate,omitempty"`
+}
+
+// Send 向通用 HTTP 短信网关推送，2xx 视为成功
+func (s *Sms) Send(message string, phoneNumbers []string) error {
+	var body string
+	if s.BodyTemplate == "" {
+		body = tools.JsonMarshalToString(map[string]interface{}{
+			"phoneNumbers": phoneNumbers,
+			"content":      message,
+		})
+	} else {
+		// 变量按 JSON 字符串转义，模版中需自行添加引号
+		content := tools.JsonMarshalToString(message)
+		body = strings.NewReplacer(
+			"${phoneNumbers}", strings.Join(phoneNumbers, ","),
+			"${content}", content[1:len(content)-1],
+		).Replace(s.BodyTemplate)
+	}
+
+	res, err := tools.Post(s.Headers, s.Url, bytes.NewReader([]byte(body)), 10)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		bodyByte, _ := io.ReadAll(res.Body)
+		return errors.New(fmt.Sprintf("SMS gateway response status: %d, body: %s", res.StatusCode, string(bodyByte)))
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package sender
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+type (
+	// MattermostSender Mattermost 发送策略
+	MattermostSender struct{}
+)
+
+func NewMattermostSender() SendInter {
+	return &MattermostSender{}
+}
+
+func (m *MattermostSender) Send(params SendParams) error {
+	return m.post(params.Hook, params.Content)
+}
+
+func (m *MattermostSender) Test(params SendParams) error {
+	msg := models.MattermostMsgTemplate{
+		Text: RobotTestContent,
+	}
+	return m.post(params.Hook, tools.JsonMarshalToString(msg))
+}
+
+func (m *MattermostSender) post(hook, content string) error {
+	res, err := tools.Post(nil, hook, bytes.NewReader([]byte(content)), 10)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	bodyByte, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errors.New(fmt.Sprintf("Error reading Mattermost response: %s", err.Error()))
+	}
+
+	if res.StatusCode != 200 {
+		return errors.New(fmt.Sprintf("Mattermost response status: %d, body: %s", res.StatusCode, string(bodyByte)))
+	}
+
+	return nil
+}
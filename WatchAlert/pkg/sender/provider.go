@@ -1,9 +1,16 @@
 package sender
 
 const (
-	PROVIDER_ALIYUN = "aliyun"
+	PROVIDER_ALIYUN  = "aliyun"
+	PROVIDER_TENCENT = "tencent"
+	PROVIDER_TWILIO  = "twilio"
+	PROVIDER_HTTP    = "http"
 )
 
 type PhoneCall interface {
 	Call(message string, phoneNumbers []string) error
 }
+
+type Sms interface {
+	Send(message string, phoneNumbers []string) error
+}
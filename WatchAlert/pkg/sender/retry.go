@@ -0,0 +1,191 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/global"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/zeromicro/go-zero/core/logc"
+)
+
+const (
+	// 重试队列扫描间隔
+	retryProcessInterval = 5 * time.Second
+	// 单次扫描处理的最大任务数
+	retryBatchSize = 100
+	// 任务抢占锁过期时间，节点异常退出后由其他节点接管
+	retryLockTTL = 60 * time.Second
+)
+
+// StartRetryWorker 启动通知重试任务，仅在 Leader 节点运行
+func StartRetryWorker(stop context.Context, ctx *ctx.Context) {
+	ticker := time.NewTicker(retryProcessInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			processRetryTasks(ctx)
+		case <-stop.Done():
+			return
+		}
+	}
+}
+
+// BuildRetryTaskId 事件ID + 通知对象 + 恢复状态 = 重试任务 ID，保证同一通知只会被重试一次
+func BuildRetryTaskId(tenantId, eventId, noticeId, hook string, isRecovered bool) string {
+	return tools.Md5Hash([]byte(tenantId + ":" + eventId + ":" + noticeId + ":" + hook + ":" + strconv.FormatBool(isRecovered)))
+}
+
+// RetryBackoff 计算第 attempts 次发送失败后的重试间隔，单位（秒）
+func RetryBackoff(noticeType string, attempts int) int64 {
+	policy := global.Config.Notice.Retry.GetRetryPolicy(noticeType)
+	interval := float64(policy.InitialInterval) * math.Pow(policy.Multiplier, float64(attempts-1))
+	if interval > float64(policy.MaxInterval) {
+		return policy.MaxInterval
+	}
+
+	return int64(interval)
+}
+
+// enqueueRetry 发送失败的通知加入重试队列
+func enqueueRetry(ctx *ctx.Context, sendParams SendParams, sendErr error) {
+	retryConf := global.Config.Notice.Retry
+	if retryConf.Disabled {
+		return
+	}
+
+	policy := retryConf.GetRetryPolicy(sendParams.NoticeType)
+	if policy.MaxAttempts <= 1 {
+		return
+	}
+
+	now := time.Now().Unix()
+	task := models.NoticeRetryTask{
+		ID:            BuildRetryTaskId(sendParams.TenantId, sendParams.EventId, sendParams.NoticeId, sendParams.Hook, sendParams.IsRecovered),
+		TenantId:      sendParams.TenantId,
+		EventId:       sendParams.EventId,
+		FaultCenterId: sendParams.FaultCenterId,
+		Fingerprint:   sendParams.Fingerprint,
+		RuleName:      sendParams.RuleName,
+		Severity:      sendParams.Severity,
+		NoticeType:    sendParams.NoticeType,
+		NoticeId:      sendParams.NoticeId,
+		NoticeName:    sendParams.NoticeName,
+		IsRecovered:   sendParams.IsRecovered,
+		Hook:          sendParams.Hook,
+		Email:         sendParams.Email,
+		Content:       sendParams.Content,
+		PhoneNumber:   sendParams.PhoneNumber,
+		Sign:          sendParams.Sign,
+		ChatId:        sendParams.ChatId,
+		IsReport:      sendParams.IsReport,
+		Attempts:      1,
+		MaxAttempts:   policy.MaxAttempts,
+		NextRetryAt:   now + RetryBackoff(sendParams.NoticeType, 1),
+		LastError:     sendErr.Error(),
+		CreateAt:      now,
+		UpdateAt:      now,
+	}
+
+	if ctx.Redis.NoticeRetry().Enqueue(task) {
+		logc.Infof(ctx.Ctx, "通知发送失败，已加入重试队列，任务ID: %s，通知对象: %s，下次重试时间: %d", task.ID, task.NoticeName, task.NextRetryAt)
+	}
+}
+
+// processRetryTasks 处理已到达重试时间的任务
+func processRetryTasks(ctx *ctx.Context) {
+	now := time.Now().Unix()
+	for _, id := range ctx.Redis.NoticeRetry().DueTasks(now, retryBatchSize) {
+		retryTask(ctx, id, now)
+	}
+}
+
+// retryTask 重试单个任务，成功后移除，超过最大次数后进入死信列表
+func retryTask(ctx *ctx.Context, id string, now int64) {
+	retryCache := ctx.Redis.NoticeRetry()
+	if !retryCache.Lock(id, retryLockTTL) {
+		return
+	}
+	defer retryCache.Unlock(id)
+
+	task, ok := retryCache.GetTask(id)
+	if !ok {
+		retryCache.RemoveTask(id)
+		return
+	}
+
+	// 已被其他节点处理并重新调度
+	if task.NextRetryAt > now {
+		return
+	}
+
+	// 告警已恢复或已被新的告警事件替代，不再补发过期的告警通知
+	if isStaleFiringTask(ctx, task) {
+		retryCache.RemoveTask(id)
+		logc.Infof(ctx.Ctx, "告警已恢复，丢弃重试任务，任务ID: %s，通知对象: %s", task.ID, task.NoticeName)
+		return
+	}
+
+	task.Attempts++
+	task.UpdateAt = now
+
+	sender, err := senderFactory(task.NoticeType)
+	if err == nil {
+		err = send(ctx, sender, SendParams{
+			TenantId:      task.TenantId,
+			EventId:       task.EventId,
+			FaultCenterId: task.FaultCenterId,
+			Fingerprint:   task.Fingerprint,
+			RuleName:      task.RuleName,
+			Severity:      task.Severity,
+			NoticeType:    task.NoticeType,
+			NoticeId:      task.NoticeId,
+			NoticeName:    task.NoticeName,
+			IsRecovered:   task.IsRecovered,
+			Hook:          task.Hook,
+			Email:         task.Email,
+			Content:       task.Content,
+			PhoneNumber:   task.PhoneNumber,
+			Sign:          task.Sign,
+			ChatId:        task.ChatId,
+			IsReport:      task.IsReport,
+		})
+		if err == nil {
+			retryCache.RemoveTask(id)
+			return
+		}
+	}
+
+	task.LastError = err.Error()
+	if task.Attempts >= task.MaxAttempts {
+		retryCache.RemoveTask(id)
+		retryCache.PushDeadLetter(task)
+		logc.Error(ctx.Ctx, fmt.Sprintf("通知重试次数已达上限，已移入死信列表，任务ID: %s，通知对象: %s，err: %s", task.ID, task.NoticeName, task.LastError))
+		return
+	}
+
+	task.NextRetryAt = now + RetryBackoff(task.NoticeType, task.Attempts)
+	retryCache.Reschedule(task)
+}
+
+// isStaleFiringTask 判断告警通知的重试任务是否已过期，恢复通知及非告警事件的通知不做判断
+func isStaleFiringTask(ctx *ctx.Context, task models.NoticeRetryTask) bool {
+	if task.IsRecovered || task.IsReport || task.FaultCenterId == "" || task.Fingerprint == "" {
+		return false
+	}
+
+	event, err := ctx.Redis.Alert().GetEventFromCache(task.TenantId, task.FaultCenterId, task.Fingerprint)
+	if err != nil {
+		// 事件已从缓存中移除，说明告警已恢复
+		return true
+	}
+
+	return event.IsRecovered || event.EventId != task.EventId
+}
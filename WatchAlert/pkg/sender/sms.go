@@ -0,0 +1,90 @@
+package sender
+
+import (
+	"errors"
+	"fmt"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/sender/aliyun"
+	"watchAlert/pkg/sender/gateway"
+	"watchAlert/pkg/sender/tencent"
+	"watchAlert/pkg/sender/twilio"
+)
+
+// SmsSender 短信发送策略
+type SmsSender struct{}
+
+func NewSmsSender() SendInter {
+	return &SmsSender{}
+}
+
+func (s *SmsSender) Send(params SendParams) error {
+	return s.send(params.Content, params.PhoneNumber)
+}
+
+func (s *SmsSender) Test(params SendParams) error {
+	return s.send(RobotTestContent, params.PhoneNumber)
+}
+
+func (s *SmsSender) send(message string, phoneNumbers []string) error {
+	if len(phoneNumbers) == 0 {
+		return errors.New("短信接收号码不能为空")
+	}
+
+	setting, err := ctx.DB.Setting().Get()
+	if err != nil {
+		return errors.New("获取系统配置失败: " + err.Error())
+	}
+
+	sms, err := newSmsProvider(setting.SmsConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := sms.Send(message, phoneNumbers); err != nil {
+		return errors.New("短信 类型报警发送失败: " + err.Error())
+	}
+
+	return nil
+}
+
+// newSmsProvider 根据配置创建短信服务提供商
+func newSmsProvider(config models.SmsConfig) (Sms, error) {
+	switch config.Provider {
+	case PROVIDER_ALIYUN:
+		aliyunSms := &aliyun.Sms{
+			Endpoint:        config.Aliyun.Endpoint,
+			AccessKeyId:     config.Aliyun.AccessKeyId,
+			AccessKeySecret: config.Aliyun.AccessKeySecret,
+			SignName:        config.Aliyun.SignName,
+			TemplateCode:    config.Aliyun.TemplateCode,
+		}
+		if err := aliyunSms.CreateClient(); err != nil {
+			return nil, fmt.Errorf("创建%s短信服务客户端失败: %v", config.Provider, err)
+		}
+		return aliyunSms, nil
+	case PROVIDER_TENCENT:
+		return &tencent.Sms{
+			Region:     config.Tencent.Region,
+			SecretId:   config.Tencent.SecretId,
+			SecretKey:  config.Tencent.SecretKey,
+			SdkAppId:   config.Tencent.SdkAppId,
+			SignName:   config.Tencent.SignName,
+			TemplateId: config.Tencent.TemplateId,
+		}, nil
+	case PROVIDER_TWILIO:
+		return &twilio.Sms{
+			AccountSid: config.Twilio.AccountSid,
+			AuthToken:  config.Twilio.AuthToken,
+			From:       config.Twilio.From,
+		}, nil
+	case PROVIDER_HTTP:
+		return &gateway.Sms{
+			Url:          config.Http.Url,
+			Headers:      config.Http.Headers,
+			BodyTemplate: config.Http.BodyTemplate,
+		}, nil
+	default:
+		return nil, errors.New("未知短信服务提供商: " + config.Provider)
+	}
+}
@@ -0,0 +1,59 @@
+package sender
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+type (
+	// TeamsSender Microsoft Teams 发送策略
+	TeamsSender struct{}
+)
+
+func NewTeamsSender() SendInter {
+	return &TeamsSender{}
+}
+
+func (t *TeamsSender) Send(params SendParams) error {
+	return t.post(params.Hook, params.Content)
+}
+
+func (t *TeamsSender) Test(params SendParams) error {
+	msg := models.TeamsMsgTemplate{
+		Type: "message",
+		Attachments: []models.TeamsAttachment{
+			{
+				ContentType: "application/vnd.microsoft.card.adaptive",
+				Content: models.TeamsAdaptiveCard{
+					Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+					Type:    "AdaptiveCard",
+					Version: "1.4",
+					Body: []models.TeamsTextBlock{
+						{Type: "TextBlock", Text: RobotTestContent, Wrap: true},
+					},
+				},
+			},
+		},
+	}
+	return t.post(params.Hook, tools.JsonMarshalToString(msg))
+}
+
+func (t *TeamsSender) post(hook, content string) error {
+	res, err := tools.Post(nil, hook, bytes.NewReader([]byte(content)), 10)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// Incoming Webhook 返回 200 "1"，Workflows 返回 202
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		bodyByte, _ := io.ReadAll(res.Body)
+		return errors.New(fmt.Sprintf("Teams response status: %d, body: %s", res.StatusCode, string(bodyByte)))
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package sender
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/bytedance/sonic"
+)
+
+type (
+	// TelegramSender Telegram 发送策略
+	// Hook 为 Bot 接口地址 https://api.telegram.org/bot<token>/sendMessage，未配置 ChatId 时兼容使用 Sign 作为 chat_id
+	TelegramSender struct{}
+
+	TelegramResponse struct {
+		Ok          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+)
+
+func NewTelegramSender() SendInter {
+	return &TelegramSender{}
+}
+
+func (t *TelegramSender) Send(params SendParams) error {
+	var msg models.TelegramMsgTemplate
+	if err := sonic.Unmarshal([]byte(params.Content), &msg); err != nil {
+		return errors.New(fmt.Sprintf("Error unmarshalling Telegram content: %s", err.Error()))
+	}
+	msg.ChatId = telegramChatId(params)
+
+	return t.post(params.Hook, msg)
+}
+
+func (t *TelegramSender) Test(params SendParams) error {
+	return t.post(params.Hook, models.TelegramMsgTemplate{
+		ChatId: telegramChatId(params),
+		Text:   RobotTestContent,
+	})
+}
+
+func telegramChatId(params SendParams) string {
+	if params.ChatId != "" {
+		return params.ChatId
+	}
+	return params.Sign
+}
+
+func (t *TelegramSender) post(hook string, msg models.TelegramMsgTemplate) error {
+	if msg.ChatId == "" {
+		return errors.New("Telegram chat_id 不能为空, 请填写 chat_id")
+	}
+
+	res, err := tools.Post(nil, hook, bytes.NewReader([]byte(tools.JsonMarshalToString(msg))), 10)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var response TelegramResponse
+	if err := tools.ParseReaderBody(res.Body, &response); err != nil {
+		return errors.New(fmt.Sprintf("Error unmarshalling Telegram response: %s", err.Error()))
+	}
+	if !response.Ok {
+		return errors.New(response.Description)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package tencent
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"watchAlert/pkg/tools"
+)
+
+const (
+	smsHost    = "sms.tencentcloudapi.com"
+	smsService = "sms"
+	smsVersion = "2021-01-11"
+	smsAction  = "SendSms"
+)
+
+type (
+	Sms struct {
+		Region     string `json:"region,omitempty"`
+		SecretId   string `json:"secretId,omitempty"`
+		SecretKey  string `json:"secretKey,omitempty"`
+		SdkAppId   string `json:"sdkAppId,omitempty"`
+		SignName   string `json:"signName,omitempty"`
+		TemplateId string `json:"templateId,omitempty"`
+	}
+
+	sendSmsRequest struct {
+		PhoneNumberSet   []string `json:"PhoneNumberSet"`
+		SmsSdkAppId      string   `json:"SmsSdkAppId"`
+		SignName         string   `json:"SignName"`
+		TemplateId       string   `json:"TemplateId"`
+		TemplateParamSet []string `json:"TemplateParamSet"`
+	}
+
+	sendSmsResponse struct {
+		Response struct {
+			Error *struct {
+				Code    string `json:"Code"`
+				Message string `json:"Message"`
+			} `json:"Error"`
+			SendStatusSet []struct {
+				PhoneNumber string `json:"PhoneNumber"`
+				Code        string `json:"Code"`
+				Message     string `json:"Message"`
+			} `json:"SendStatusSet"`
+		} `json:"Response"`
+	}
+)
+
+// Send 调用 SendSms 接口，使用 TC3-HMAC-SHA256 签名
+func (s *Sms) Send(message string, phoneNumbers []string) error {
+	region := s.Region
+	if region == "" {
+		region = "ap-guangzhou"
+	}
+
+	// 腾讯云要求 E.164 格式，未带国家码的号码默认为中国大陆
+	numbers := make([]string, 0, len(phoneNumbers))
+	for _, number := range phoneNumbers {
+		if !strings.HasPrefix(number, "+") {
+			number = "+86" + number
+		}
+		numbers = append(numbers, number)
+	}
+
+	payload := tools.JsonMarshalToString(sendSmsRequest{
+		PhoneNumberSet:   numbers,
+		SmsSdkAppId:      s.SdkAppId,
+		SignName:         s.SignName,
+		TemplateId:       s.TemplateId,
+		TemplateParamSet: []string{message},
+	})
+
+	timestamp := time.Now().Unix()
+	headers := map[string]string{
+		"Authorization":  s.authorization(payload, timestamp),
+		"Host":           smsHost,
+		"X-TC-Action":    smsAction,
+		"X-TC-Version":   smsVersion,
+		"X-TC-Timestamp": fmt.Sprintf("%d", timestamp),
+		"X-TC-Region":    region,
+	}
+
+	res, err := tools.Post(headers, "https://"+smsHost, bytes.NewReader([]byte(payload)), 10)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var response sendSmsResponse
+	if err := tools.ParseReaderBody(res.Body, &response); err != nil {
+		return errors.New(fmt.Sprintf("Error unmarshalling Tencent sms response: %s", err.Error()))
+	}
+	if response.Response.Error != nil {
+		return errors.New(response.Response.Error.Code + ": " + response.Response.Error.Message)
+	}
+
+	var failed []string
+	for _, status := range response.Response.SendStatusSet {
+		if status.Code != "Ok" {
+			failed = append(failed, fmt.Sprintf("%s %s", status.PhoneNumber, status.Message))
+		}
+	}
+	if len(failed) > 0 {
+		return errors.New(strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+// authorization 生成 TC3-HMAC-SHA256 签名
+func (s *Sms) authorization(payload string, timestamp int64) string {
+	date := time.Unix(timestamp, 0).UTC().Format("2006-01-02")
+	canonicalHeaders := "content-type:application/json\nhost:" + smsHost + "\n"
+	signedHeaders := "content-type;host"
+	canonicalRequest := strings.Join([]string{
+		"POST", "/", "", canonicalHeaders, signedHeaders, sha256Hex(payload),
+	}, "\n")
+
+	credentialScope := date + "/" + smsService + "/tc3_request"
+	stringToSign := strings.Join([]string{
+		"TC3-HMAC-SHA256", fmt.Sprintf("%d", timestamp), credentialScope, sha256Hex(canonicalRequest),
+	}, "\n")
+
+	secretDate := hmacSha256([]byte("TC3"+s.SecretKey), date)
+	secretService := hmacSha256(secretDate, smsService)
+	secretSigning := hmacSha256(secretService, "tc3_request")
+	signature := hex.EncodeToString(hmacSha256(secretSigning, stringToSign))
+
+	return fmt.Sprintf("TC3-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.SecretId, credentialScope, signedHeaders, signature)
+}
+
+func sha256Hex(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSha256(key []byte, s string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(s))
+	return h.Sum(nil)
+}
@@ -0,0 +1,64 @@
+package twilio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"watchAlert/pkg/tools"
+
+	"go.uber.org/multierr"
+)
+
+type (
+	Sms struct {
+		AccountSid string `json:"accountSid,omitempty"`
+		AuthToken  string `json:"authToken,omitempty"`
+		From       string `json:"from,omitempty"`
+	}
+
+	messageResponse struct {
+		Sid     string `json:"sid"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+)
+
+// Send 调用 Messages 接口，每个号码发送一条短信
+func (s *Sms) Send(message string, phoneNumbers []string) error {
+	api := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json", s.AccountSid)
+	headers := tools.CreateBasicAuthHeader(s.AccountSid, s.AuthToken)
+	headers["Content-Type"] = "application/x-www-form-urlencoded"
+
+	var resultError error
+	for _, phoneNumber := range phoneNumbers {
+		form := url.Values{}
+		form.Set("To", phoneNumber)
+		form.Set("From", s.From)
+		form.Set("Body", message)
+
+		if err := s.post(api, headers, form.Encode()); err != nil {
+			resultError = multierr.Append(resultError, fmt.Errorf("%s: %w", phoneNumber, err))
+		}
+	}
+
+	return resultError
+}
+
+func (s *Sms) post(api string, headers map[string]string, body string) error {
+	res, err := tools.Post(headers, api, bytes.NewReader([]byte(body)), 10)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var response messageResponse
+	if err := tools.ParseReaderBody(res.Body, &response); err != nil {
+		return errors.New(fmt.Sprintf("Error unmarshalling Twilio response: %s", err.Error()))
+	}
+	if res.StatusCode >= 300 {
+		return errors.New(fmt.Sprintf("%d %s", response.Code, response.Message))
+	}
+
+	return nil
+}
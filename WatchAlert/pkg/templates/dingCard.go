@@ -6,7 +6,7 @@ import (
 	"watchAlert/pkg/tools"
 )
 
-func dingdingTemplate(alert models2.AlertCurEvent, noticeTmpl models2.NoticeTemplateExample) string {
+func dingdingTemplate(alert models2.AlertCurEvent, noticeTmpl models2.NoticeTemplateExample, buttons []models2.ImActionButton) string {
 	Title := ParserTemplate("Title", alert, noticeTmpl.Template)
 	Footer := ParserTemplate("Footer", alert, noticeTmpl.Template)
 
@@ -24,6 +24,7 @@ func dingdingTemplate(alert models2.AlertCurEvent, noticeTmpl models2.NoticeTemp
 			Text: "**" + Title + "**" +
 				"\n" + "\n" +
 				ParserTemplate("Event", alert, noticeTmpl.Template) +
+				renderGroupedEvents(alert, noticeTmpl.Template, groupedEventsMarkdown) +
 				"\n" +
 				Footer,
 		},
@@ -42,5 +43,19 @@ func dingdingTemplate(alert models2.AlertCurEvent, noticeTmpl models2.NoticeTemp
 		}
 	}
 
+	// 钉钉群机器人卡片仅支持链接按钮，按钮以点击者身份向会话发送操作指令，由机器人 outgoing 回调处理
+	if len(buttons) > 0 {
+		return tools.JsonMarshalToString(models2.DingActionCardMsg{
+			Msgtype: "actionCard",
+			ActionCard: models2.DingActionCard{
+				Title:          t.Markdown.Title,
+				Text:           t.Markdown.Text,
+				BtnOrientation: "1",
+				Btns:           dingdingActionBtns(buttons),
+			},
+			At: t.At,
+		})
+	}
+
 	return tools.JsonMarshalToString(t)
 }
@@ -0,0 +1,42 @@
+package templates
+
+import (
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+const (
+	discordColorFiring  = 0xE74C3C
+	discordColorRecover = 0x2ECC71
+	// Discord embed description 最大长度
+	discordDescriptionLimit = 4096
+)
+
+// discordTemplate Discord Embed 消息模版
+func discordTemplate(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplateExample) string {
+	color := discordColorFiring
+	if alert.IsRecovered {
+		color = discordColorRecover
+	}
+
+	description := ParserTemplate("Event", alert, noticeTmpl.Template) + renderGroupedEvents(alert, noticeTmpl.Template, groupedEventsMarkdown)
+	if footer := ParserTemplate("Footer", alert, noticeTmpl.Template); footer != "" {
+		description += "\n" + footer
+	}
+	if runes := []rune(description); len(runes) > discordDescriptionLimit {
+		description = string(runes[:discordDescriptionLimit])
+	}
+
+	t := models.DiscordMsgTemplate{
+		Content: alert.DutyUser,
+		Embeds: []models.DiscordEmbed{
+			{
+				Title:       ParserTemplate("Title", alert, noticeTmpl.Template),
+				Description: description,
+				Color:       color,
+			},
+		},
+	}
+
+	return tools.JsonMarshalToString(t)
+}
@@ -3,5 +3,5 @@ package templates
 import "watchAlert/internal/models"
 
 func emailTemplate(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplateExample) string {
-	return ParserTemplate("Event", alert, noticeTmpl.Template)
+	return ParserTemplate("Event", alert, noticeTmpl.Template) + renderGroupedEvents(alert, noticeTmpl.Template, groupedEventsHTML)
 }
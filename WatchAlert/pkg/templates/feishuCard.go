@@ -9,7 +9,7 @@ import (
 )
 
 // Template 飞书消息卡片模版
-func feishuTemplate(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplateExample) string {
+func feishuTemplate(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplateExample, buttons []models.ImActionButton) string {
 
 	var cardContentString string
 	if *noticeTmpl.EnableFeiShuJsonCard {
@@ -60,7 +60,7 @@ func feishuTemplate(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplate
 							{
 								Tag: "div",
 								Text: models.Texts{
-									Content: ParserTemplate("Event", alert, noticeTmpl.Template),
+									Content: ParserTemplate("Event", alert, noticeTmpl.Template) + renderGroupedEvents(alert, noticeTmpl.Template, groupedEventsMarkdown),
 									Tag:     "lark_md",
 								},
 							},
@@ -83,6 +83,9 @@ func feishuTemplate(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplate
 		}
 
 		defaultTemplate.Card.Elements = tools.ConvertSliceToMapList(cardElements)
+		if len(buttons) > 0 {
+			defaultTemplate.Card.Elements = append(defaultTemplate.Card.Elements, feishuActionElement(buttons))
+		}
 		defaultTemplate.Card.Header = tools.ConvertStructToMap(cardHeader)
 		cardContentString = tools.JsonMarshalToString(defaultTemplate)
 
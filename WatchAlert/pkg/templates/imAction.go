@@ -0,0 +1,109 @@
+package templates
+
+import (
+	"net/url"
+	"strings"
+	"watchAlert/internal/ctx"
+	"watchAlert/internal/global"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+
+	"github.com/bytedance/sonic"
+)
+
+// imActionButtons 获取卡片按钮，未开启 IM 交互或恢复通知时不展示
+func imActionButtons(ctx *ctx.Context, alert models.AlertCurEvent, notice models.AlertNotice) ([]models.ImActionButton, models.ImActionConfig) {
+	setting, err := ctx.DB.Setting().Get()
+	if err != nil || !setting.ImActionConfig.GetEnable() || alert.IsRecovered || alert.Fingerprint == "" {
+		return nil, setting.ImActionConfig
+	}
+
+	return models.ImActionButtons(alert, notice.Uuid, global.StSignKey), setting.ImActionConfig
+}
+
+// ImActionResultCard 操作完成后重新渲染卡片，移除按钮并附加操作结果，用于更新原卡片
+func ImActionResultCard(ctx *ctx.Context, alert models.AlertCurEvent, notice models.AlertNotice, result string) string {
+	noticeTmpl := ctx.DB.NoticeTmpl().Get(notice.NoticeTmplId)
+	switch notice.NoticeType {
+	case "FeiShu":
+		var msg models.FeiShuJsonCardMsg
+		_ = sonic.Unmarshal([]byte(feishuTemplate(alert, noticeTmpl, nil)), &msg)
+		msg.Card.Elements = append(msg.Card.Elements, feishuNoteElement(result))
+		return tools.JsonMarshalToString(msg.Card)
+	case "Slack":
+		msg := buildSlackMsg(alert, noticeTmpl, nil)
+		msg.Blocks = append(msg.Blocks, map[string]interface{}{
+			"type":     "context",
+			"elements": []map[string]interface{}{{"type": "mrkdwn", "text": result}},
+		})
+		msg.ReplaceOriginal = true
+		return tools.JsonMarshalToString(msg)
+	}
+
+	return ""
+}
+
+// feishuActionElement 飞书卡片按钮
+func feishuActionElement(buttons []models.ImActionButton) map[string]interface{} {
+	var actions []map[string]interface{}
+	for _, button := range buttons {
+		actions = append(actions, map[string]interface{}{
+			"tag":   "button",
+			"text":  models.ActionsText{Tag: "plain_text", Content: button.Text},
+			"type":  button.Style,
+			"value": tools.ConvertStructToMap(button.Value),
+		})
+	}
+
+	return map[string]interface{}{
+		"tag":     "action",
+		"actions": actions,
+	}
+}
+
+func feishuNoteElement(content string) map[string]interface{} {
+	return map[string]interface{}{
+		"tag": "note",
+		"elements": []models.ElementsElements{
+			{Tag: "plain_text", Content: content},
+		},
+	}
+}
+
+// slackActionBlock Slack 卡片按钮
+func slackActionBlock(buttons []models.ImActionButton) map[string]interface{} {
+	var elements []map[string]interface{}
+	for _, button := range buttons {
+		element := map[string]interface{}{
+			"type":      "button",
+			"text":      map[string]interface{}{"type": "plain_text", "text": button.Text},
+			"action_id": button.Value.Action,
+			"value":     tools.JsonMarshalToString(button.Value),
+		}
+		switch button.Style {
+		case "primary", "danger":
+			element["style"] = button.Style
+		}
+		elements = append(elements, element)
+	}
+
+	return map[string]interface{}{
+		"type":     "actions",
+		"elements": elements,
+	}
+}
+
+// dingdingActionBtns 钉钉卡片按钮，点击后通过 dtmd 协议以点击者身份发送「@机器人 操作 指令」消息
+func dingdingActionBtns(buttons []models.ImActionButton) []models.DingActionCardBtn {
+	var btns []models.DingActionCardBtn
+	for _, button := range buttons {
+		query := url.Values{}
+		query.Set("content", button.Text+" "+button.Value.Encode())
+		btns = append(btns, models.DingActionCardBtn{
+			Title:     button.Text,
+			ActionURL: "dtmd://dingtalkclient/sendMessage?" + strings.ReplaceAll(query.Encode(), "+", "%20"),
+		})
+	}
+
+	return btns
+}
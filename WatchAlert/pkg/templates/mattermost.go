@@ -0,0 +1,34 @@
+package templates
+
+import (
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+// mattermostTemplate Mattermost 消息附件模版
+func mattermostTemplate(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplateExample) string {
+	color := "#E74C3C"
+	if alert.IsRecovered {
+		color = "#2ECC71"
+	}
+
+	title := ParserTemplate("Title", alert, noticeTmpl.Template)
+	text := ParserTemplate("Event", alert, noticeTmpl.Template) + renderGroupedEvents(alert, noticeTmpl.Template, groupedEventsMarkdown)
+	if footer := ParserTemplate("Footer", alert, noticeTmpl.Template); footer != "" {
+		text += "\n" + footer
+	}
+
+	t := models.MattermostMsgTemplate{
+		Text: alert.DutyUser,
+		Attachments: []models.MattermostAttachment{
+			{
+				Fallback: title,
+				Color:    color,
+				Title:    title,
+				Text:     text,
+			},
+		},
+	}
+
+	return tools.JsonMarshalToString(t)
+}
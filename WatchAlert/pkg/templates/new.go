@@ -13,9 +13,15 @@ func NewTemplate(ctx *ctx.Context, alert models.AlertCurEvent, notice models.Ale
 	noticeTmpl := ctx.DB.NoticeTmpl().Get(notice.NoticeTmplId)
 	switch notice.NoticeType {
 	case "FeiShu":
-		return Template{CardContentMsg: feishuTemplate(alert, noticeTmpl)}
+		buttons, _ := imActionButtons(ctx, alert, notice)
+		return Template{CardContentMsg: feishuTemplate(alert, noticeTmpl, buttons)}
 	case "DingDing":
-		return Template{CardContentMsg: dingdingTemplate(alert, noticeTmpl)}
+		buttons, config := imActionButtons(ctx, alert, notice)
+		// 未配置机器人 AppSecret 时无法校验回调，不展示按钮
+		if config.DingDingAppSecret == "" {
+			buttons = nil
+		}
+		return Template{CardContentMsg: dingdingTemplate(alert, noticeTmpl, buttons)}
 	case "Email":
 		return Template{CardContentMsg: emailTemplate(alert, noticeTmpl)}
 	case "WeChat":
@@ -26,8 +32,20 @@ func NewTemplate(ctx *ctx.Context, alert models.AlertCurEvent, notice models.Ale
 		return Template{CardContentMsg: wechatMiniProgramTemplate(alert, noticeTmpl)}
 	case "PhoneCall":
 		return Template{CardContentMsg: phoneCallTemplate(alert, noticeTmpl)}
+	case "SMS":
+		setting, _ := ctx.DB.Setting().Get()
+		return Template{CardContentMsg: smsTemplate(alert, noticeTmpl, setting.SmsConfig.GetMaxLength())}
 	case "Slack":
-		return Template{slackTemplate(alert, noticeTmpl)}
+		buttons, _ := imActionButtons(ctx, alert, notice)
+		return Template{slackTemplate(alert, noticeTmpl, buttons)}
+	case "Teams":
+		return Template{CardContentMsg: teamsTemplate(alert, noticeTmpl)}
+	case "Telegram":
+		return Template{CardContentMsg: telegramTemplate(alert, noticeTmpl)}
+	case "Discord":
+		return Template{CardContentMsg: discordTemplate(alert, noticeTmpl)}
+	case "Mattermost":
+		return Template{CardContentMsg: mattermostTemplate(alert, noticeTmpl)}
 	}
 
 	return Template{}
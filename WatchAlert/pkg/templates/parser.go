@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"html"
+	"strings"
 	"text/template"
 	"time"
 	"watchAlert/internal/global"
@@ -28,10 +30,75 @@ func ParserTemplate(defineName string, alert models.AlertCurEvent, templateStr s
 	return renderNamedTemplate(tmpl, defineName, alert)
 }
 
+// renderGroupedEvents 渲染合并通知的同组事件列表，仅在包含多条事件时生成；
+// 通知模版可通过 {{ define "GroupedEvents" }} 使用 .GroupedEvents 自定义，未定义时使用渠道默认格式 format
+func renderGroupedEvents(alert models.AlertCurEvent, templateStr string, format func(alert models.AlertCurEvent) string) string {
+	if len(alert.GroupedEvents) <= 1 {
+		return ""
+	}
+	alert = prepareAlertData(alert)
+
+	tmpl, err := template.New("tmpl").Parse(templateStr)
+	if err == nil && tmpl.Lookup("GroupedEvents") != nil {
+		return renderNamedTemplate(tmpl, "GroupedEvents", alert)
+	}
+	return format(alert)
+}
+
+// groupedEventLines 同组事件的标题及每条事件的摘要
+func groupedEventLines(alert models.AlertCurEvent) (string, []string) {
+	lines := make([]string, 0, len(alert.GroupedEvents))
+	for _, event := range alert.GroupedEvents {
+		status, eventTime := "告警中", event.FirstTriggerTimeFormat
+		if event.IsRecovered {
+			status, eventTime = "已恢复", event.RecoverTimeFormat
+		}
+		lines = append(lines, fmt.Sprintf("[%s] %s %s %s %s", event.Severity, event.RuleName, status, eventTime, event.Fingerprint))
+	}
+	return fmt.Sprintf("同组告警 (%d)", len(alert.GroupedEvents)), lines
+}
+
+// groupedEventsText 纯文本格式的同组事件列表
+func groupedEventsText(alert models.AlertCurEvent) string {
+	title, lines := groupedEventLines(alert)
+	return "\n\n" + title + ":\n- " + strings.Join(lines, "\n- ")
+}
+
+// groupedEventsMarkdown Markdown 格式的同组事件列表
+func groupedEventsMarkdown(alert models.AlertCurEvent) string {
+	title, lines := groupedEventLines(alert)
+	return "\n\n**" + title + "**\n- " + strings.Join(lines, "\n- ")
+}
+
+// groupedEventsHTML 邮件使用的 HTML 格式同组事件列表
+func groupedEventsHTML(alert models.AlertCurEvent) string {
+	title, lines := groupedEventLines(alert)
+
+	var b strings.Builder
+	b.WriteString("<p><b>" + html.EscapeString(title) + "</b></p><ul>")
+	for _, line := range lines {
+		b.WriteString("<li>" + html.EscapeString(line) + "</li>")
+	}
+	b.WriteString("</ul>")
+	return b.String()
+}
+
+// groupedEventsCount 短信使用的同组事件数量
+func groupedEventsCount(alert models.AlertCurEvent) string {
+	return fmt.Sprintf("\n同组共 %d 条告警", len(alert.GroupedEvents))
+}
+
 // prepareAlertData 预处理告警数据，格式化时间字段
 func prepareAlertData(alert models.AlertCurEvent) models.AlertCurEvent {
 	alert.FirstTriggerTimeFormat = time.Unix(alert.FirstTriggerTime, 0).Format(global.Layout)
 	alert.RecoverTimeFormat = time.Unix(alert.RecoverTime, 0).Format(global.Layout)
+	if len(alert.GroupedEvents) > 0 {
+		groupedEvents := make([]models.AlertCurEvent, 0, len(alert.GroupedEvents))
+		for _, event := range alert.GroupedEvents {
+			groupedEvents = append(groupedEvents, prepareAlertData(event))
+		}
+		alert.GroupedEvents = groupedEvents
+	}
 	return alert
 }
 
@@ -0,0 +1,47 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+	"watchAlert/internal/models"
+)
+
+func groupedTestAlert() models.AlertCurEvent {
+	return models.AlertCurEvent{
+		RuleName: "cpu",
+		GroupedEvents: []models.AlertCurEvent{
+			{RuleName: "cpu", Severity: "P1", Fingerprint: "a"},
+			{RuleName: "cpu", Severity: "P1", Fingerprint: "<b>", IsRecovered: true},
+		},
+	}
+}
+
+func TestParserTemplateDoesNotAppendGroupedEvents(t *testing.T) {
+	got := ParserTemplate("Event", groupedTestAlert(), `{{ define "Event" }}{{ .RuleName }}{{ end }}`)
+	if got != "cpu" {
+		t.Fatalf("ParserTemplate = %q, want %q", got, "cpu")
+	}
+}
+
+func TestRenderGroupedEvents(t *testing.T) {
+	alert := groupedTestAlert()
+
+	if got := renderGroupedEvents(models.AlertCurEvent{GroupedEvents: alert.GroupedEvents[:1]}, "", groupedEventsMarkdown); got != "" {
+		t.Fatalf("single event should not render a group, got %q", got)
+	}
+
+	markdown := renderGroupedEvents(alert, "", groupedEventsMarkdown)
+	if !strings.Contains(markdown, "**同组告警 (2)**") || !strings.Contains(markdown, "- [P1] cpu 已恢复") {
+		t.Fatalf("unexpected markdown: %q", markdown)
+	}
+
+	html := renderGroupedEvents(alert, "", groupedEventsHTML)
+	if !strings.Contains(html, "<li>") || !strings.Contains(html, "&lt;b&gt;") {
+		t.Fatalf("unexpected html: %q", html)
+	}
+
+	custom := renderGroupedEvents(alert, `{{ define "GroupedEvents" }}{{ range .GroupedEvents }}{{ .Fingerprint }};{{ end }}{{ end }}`, groupedEventsMarkdown)
+	if custom != "a;<b>;" {
+		t.Fatalf("custom template = %q", custom)
+	}
+}
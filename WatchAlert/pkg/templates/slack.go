@@ -5,10 +5,24 @@ import (
 	"watchAlert/pkg/tools"
 )
 
-func slackTemplate(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplateExample) string {
+func slackTemplate(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplateExample, buttons []models.ImActionButton) string {
+	return tools.JsonMarshalToString(buildSlackMsg(alert, noticeTmpl, buttons))
+}
+
+func buildSlackMsg(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplateExample, buttons []models.ImActionButton) models.SlackMsgTemplate {
 	t := models.SlackMsgTemplate{
-		Text: ParserTemplate("Event", alert, noticeTmpl.Template),
+		Text: ParserTemplate("Event", alert, noticeTmpl.Template) + renderGroupedEvents(alert, noticeTmpl.Template, groupedEventsText),
+	}
+
+	if len(buttons) > 0 {
+		t.Blocks = []map[string]interface{}{
+			{
+				"type": "section",
+				"text": map[string]interface{}{"type": "mrkdwn", "text": t.Text},
+			},
+			slackActionBlock(buttons),
+		}
 	}
 
-	return tools.JsonMarshalToString(t)
+	return t
 }
@@ -0,0 +1,42 @@
+package templates
+
+import (
+	"regexp"
+	"strings"
+	"watchAlert/internal/models"
+)
+
+var (
+	smsMarkdownRe  = regexp.MustCompile("(\\*\\*|__|`|^#+\\s*|^>\\s*)")
+	smsBlankLineRe = regexp.MustCompile(`\n{2,}`)
+)
+
+// smsTemplate 短信模版，去除 Markdown 标记并按最大长度截断
+func smsTemplate(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplateExample, maxLength int) string {
+	var lines []string
+	for _, line := range strings.Split(ParserTemplate("Event", alert, noticeTmpl.Template), "\n") {
+		line = strings.TrimSpace(smsMarkdownRe.ReplaceAllString(strings.TrimSpace(line), ""))
+		lines = append(lines, line)
+	}
+	content := strings.TrimSpace(smsBlankLineRe.ReplaceAllString(strings.Join(lines, "\n"), "\n"))
+	content += renderGroupedEvents(alert, noticeTmpl.Template, groupedEventsCount)
+
+	if title := strings.TrimSpace(ParserTemplate("Title", alert, noticeTmpl.Template)); title != "" {
+		content = title + "\n" + content
+	}
+
+	return truncateRunes(content, maxLength)
+}
+
+// truncateRunes 按字符截断，超出时以省略号结尾
+func truncateRunes(s string, maxLength int) string {
+	runes := []rune(s)
+	if maxLength <= 0 || len(runes) <= maxLength {
+		return s
+	}
+	if maxLength <= 1 {
+		return string(runes[:maxLength])
+	}
+
+	return string(runes[:maxLength-1]) + "…"
+}
@@ -0,0 +1,52 @@
+package templates
+
+import (
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+// teamsTemplate Teams Adaptive Card 模版
+func teamsTemplate(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplateExample) string {
+	color := "attention"
+	if alert.IsRecovered {
+		color = "good"
+	}
+
+	t := models.TeamsMsgTemplate{
+		Type: "message",
+		Attachments: []models.TeamsAttachment{
+			{
+				ContentType: "application/vnd.microsoft.card.adaptive",
+				Content: models.TeamsAdaptiveCard{
+					Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+					Type:    "AdaptiveCard",
+					Version: "1.4",
+					Body: []models.TeamsTextBlock{
+						{
+							Type:   "TextBlock",
+							Text:   ParserTemplate("Title", alert, noticeTmpl.Template),
+							Weight: "bolder",
+							Size:   "medium",
+							Color:  color,
+							Wrap:   true,
+						},
+						{
+							Type: "TextBlock",
+							Text: ParserTemplate("Event", alert, noticeTmpl.Template) + renderGroupedEvents(alert, noticeTmpl.Template, groupedEventsMarkdown),
+							Wrap: true,
+						},
+						{
+							Type: "TextBlock",
+							Text: ParserTemplate("Footer", alert, noticeTmpl.Template),
+							Size: "small",
+							Wrap: true,
+						},
+					},
+					MsTeams: map[string]any{"width": "Full"},
+				},
+			},
+		},
+	}
+
+	return tools.JsonMarshalToString(t)
+}
@@ -0,0 +1,28 @@
+package templates
+
+import (
+	"strings"
+	"watchAlert/internal/models"
+	"watchAlert/pkg/tools"
+)
+
+// telegramTemplate Telegram 消息模版，chat_id 由发送时按通知对象配置填充
+func telegramTemplate(alert models.AlertCurEvent, noticeTmpl models.NoticeTemplateExample) string {
+	var lines []string
+	for _, name := range []string{"Title", "Event", "Footer"} {
+		content := ParserTemplate(name, alert, noticeTmpl.Template)
+		if name == "Event" {
+			content += renderGroupedEvents(alert, noticeTmpl.Template, groupedEventsText)
+		}
+		if content = strings.TrimSpace(content); content != "" {
+			lines = append(lines, content)
+		}
+	}
+
+	t := models.TelegramMsgTemplate{
+		Text:                  strings.Join(lines, "\n\n"),
+		DisableWebPagePreview: true,
+	}
+
+	return tools.JsonMarshalToString(t)
+}
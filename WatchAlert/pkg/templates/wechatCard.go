@@ -15,6 +15,7 @@ func wechatTemplate(alert models2.AlertCurEvent, noticeTmpl models2.NoticeTempla
 			Content: "**" + Title + "**" +
 				"\n" + "\n" +
 				ParserTemplate("Event", alert, noticeTmpl.Template) +
+				renderGroupedEvents(alert, noticeTmpl.Template, groupedEventsMarkdown) +
 				"\n" +
 				Footer,
 		},
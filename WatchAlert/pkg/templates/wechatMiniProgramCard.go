@@ -8,7 +8,7 @@ import (
 
 func wechatMiniProgramTemplate(alert models2.AlertCurEvent, noticeTmpl models2.NoticeTemplateExample) string {
 	Title := ParserTemplate("Title", alert, noticeTmpl.Template)
-	Event := ParserTemplate("Event", alert, noticeTmpl.Template)
+	Event := ParserTemplate("Event", alert, noticeTmpl.Template) + renderGroupedEvents(alert, noticeTmpl.Template, groupedEventsMarkdown)
 	Footer := ParserTemplate("Footer", alert, noticeTmpl.Template)
 
 	// 默认使用markdown格式
@@ -45,7 +45,7 @@ func wechatWorkTemplate(alert models2.AlertCurEvent, noticeTmpl models2.NoticeTe
 		return match
 	})
 
-	content := tools.ParserVariables(templateStr, data)
+	content := tools.ParserVariables(templateStr, data) + renderGroupedEvents(alert, templateContent, groupedEventsText)
 
 	toUser := "@all"
 	agentID := 0
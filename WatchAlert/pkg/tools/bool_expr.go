@@ -0,0 +1,199 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// BoolExpr 布尔表达式，由标识符、&&、||、!、括号组成，也支持 AND、OR、NOT 关键字
+type BoolExpr struct {
+	node boolNode
+}
+
+type boolNode interface {
+	eval(values func(ident string) bool) bool
+	idents(result map[string]struct{})
+}
+
+type identNode string
+
+type notNode struct{ x boolNode }
+
+type binaryNode struct {
+	and  bool
+	l, r boolNode
+}
+
+func (n identNode) eval(values func(string) bool) bool { return values(string(n)) }
+func (n identNode) idents(result map[string]struct{})  { result[string(n)] = struct{}{} }
+
+func (n notNode) eval(values func(string) bool) bool { return !n.x.eval(values) }
+func (n notNode) idents(result map[string]struct{})  { n.x.idents(result) }
+
+func (n binaryNode) eval(values func(string) bool) bool {
+	if n.and {
+		return n.l.eval(values) && n.r.eval(values)
+	}
+	return n.l.eval(values) || n.r.eval(values)
+}
+
+func (n binaryNode) idents(result map[string]struct{}) {
+	n.l.idents(result)
+	n.r.idents(result)
+}
+
+// Eval 计算表达式，values 返回标识符对应的值
+func (e *BoolExpr) Eval(values func(ident string) bool) bool {
+	return e.node.eval(values)
+}
+
+// Idents 表达式中引用的标识符
+func (e *BoolExpr) Idents() []string {
+	set := make(map[string]struct{})
+	e.node.idents(set)
+
+	result := make([]string, 0, len(set))
+	for ident := range set {
+		result = append(result, ident)
+	}
+	return result
+}
+
+// ParseBoolExpr 解析布尔表达式，优先级 ! > && > ||
+func ParseBoolExpr(expr string) (*BoolExpr, error) {
+	tokens, err := tokenizeBoolExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("表达式不能为空")
+	}
+
+	p := &boolParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("表达式存在多余的内容: %s", p.tokens[p.pos])
+	}
+
+	return &BoolExpr{node: node}, nil
+}
+
+func tokenizeBoolExpr(expr string) ([]string, error) {
+	var (
+		tokens []string
+		runes  = []rune(expr)
+	)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(' || c == ')' || c == '!':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '&' || c == '|':
+			if i+1 >= len(runes) || runes[i+1] != c {
+				return nil, fmt.Errorf("无效的操作符 '%c'，位置 %d", c, i)
+			}
+			tokens = append(tokens, string([]rune{c, c}))
+			i += 2
+		case c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			word := string(runes[start:i])
+			switch strings.ToUpper(word) {
+			case "AND":
+				word = "&&"
+			case "OR":
+				word = "||"
+			case "NOT":
+				word = "!"
+			}
+			tokens = append(tokens, word)
+		default:
+			return nil, fmt.Errorf("无效的字符 '%c'，位置 %d", c, i)
+		}
+	}
+	return tokens, nil
+}
+
+type boolParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *boolParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *boolParser) parseOr() (boolNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "||" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{and: false, l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *boolParser) parseAnd() (boolNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&&" {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{and: true, l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *boolParser) parseUnary() (boolNode, error) {
+	token := p.peek()
+	switch token {
+	case "":
+		return nil, fmt.Errorf("表达式不完整")
+	case "!":
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x: x}, nil
+	case "(":
+		p.pos++
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("缺少右括号")
+		}
+		p.pos++
+		return x, nil
+	case ")", "&&", "||":
+		return nil, fmt.Errorf("表达式中 '%s' 的位置无效", token)
+	default:
+		p.pos++
+		return identNode(token), nil
+	}
+}
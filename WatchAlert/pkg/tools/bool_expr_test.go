@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseBoolExpr(t *testing.T) {
+	// 各条件的取值，用于校验运算符优先级
+	values := map[string]bool{"a": true, "b": false, "c": true, "d": false}
+
+	tests := []struct {
+		name       string
+		expr       string
+		want       bool
+		wantIdents string
+		wantErr    bool
+	}{
+		{name: "single ident", expr: "a", want: true, wantIdents: "a"},
+		{name: "and binds tighter than or", expr: "b && a || c", want: true, wantIdents: "a,b,c"},
+		{name: "and binds tighter than or on right", expr: "c || a && b", want: true, wantIdents: "a,b,c"},
+		{name: "parentheses override precedence", expr: "(c || a) && b", want: false, wantIdents: "a,b,c"},
+		{name: "not binds tighter than and", expr: "!b && a", want: true, wantIdents: "a,b"},
+		{name: "not applies to group", expr: "!(a && c)", want: false, wantIdents: "a,c"},
+		{name: "double not", expr: "!!a", want: true, wantIdents: "a"},
+		{name: "keywords", expr: "a AND NOT b OR d", want: true, wantIdents: "a,b,d"},
+		{name: "lowercase keywords", expr: "not a or (c and not d)", want: true, wantIdents: "a,c,d"},
+		{name: "repeated ident", expr: "a && a", want: true, wantIdents: "a"},
+		{name: "empty", expr: "", wantErr: true},
+		{name: "blank", expr: "   ", wantErr: true},
+		{name: "missing right paren", expr: "(a && b", wantErr: true},
+		{name: "extra right paren", expr: "a && b)", wantErr: true},
+		{name: "empty parens", expr: "()", wantErr: true},
+		{name: "trailing operator", expr: "a &&", wantErr: true},
+		{name: "leading operator", expr: "|| a", wantErr: true},
+		{name: "trailing ident", expr: "a b", wantErr: true},
+		{name: "single ampersand", expr: "a & b", wantErr: true},
+		{name: "invalid character", expr: "a + b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := ParseBoolExpr(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBoolExpr(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := expr.Eval(func(ident string) bool { return values[ident] }); got != tt.want {
+				t.Fatalf("Eval(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+			idents := expr.Idents()
+			sort.Strings(idents)
+			if got := strings.Join(idents, ","); got != tt.wantIdents {
+				t.Fatalf("Idents(%q) = %s, want %s", tt.expr, got, tt.wantIdents)
+			}
+		})
+	}
+}
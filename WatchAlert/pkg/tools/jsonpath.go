@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var jsonPathTemplateRe = regexp.MustCompile(`\$\{(.*?)\}`)
+
+// GetJsonPathValue 按简化的 JSONPath 获取字段值，支持 $.a.b、a.b、$.list[0].name 形式
+func GetJsonPathValue(data interface{}, path string) interface{} {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return data
+	}
+
+	// 将 list[0] 转换为 list.0，统一按 . 逐级解析
+	path = strings.ReplaceAll(path, "[", ".")
+	path = strings.ReplaceAll(path, "]", "")
+
+	current := data
+	for _, key := range strings.Split(path, ".") {
+		if key == "" {
+			continue
+		}
+
+		switch v := current.(type) {
+		case map[string]interface{}:
+			value, ok := v[key]
+			if !ok {
+				return nil
+			}
+			current = value
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil
+			}
+			current = v[idx]
+		default:
+			return nil
+		}
+	}
+
+	return current
+}
+
+// RenderJsonPathValue 渲染取值表达式，以 $ 开头按 JSONPath 取值，否则将模板中的 ${path} 替换为对应字段值
+func RenderJsonPathValue(expr string, data interface{}) string {
+	if strings.HasPrefix(expr, "$") && !strings.HasPrefix(expr, "${") {
+		return FormatJsonValue(GetJsonPathValue(data, expr))
+	}
+
+	return jsonPathTemplateRe.ReplaceAllStringFunc(expr, func(match string) string {
+		return FormatJsonValue(GetJsonPathValue(data, match[2:len(match)-1]))
+	})
+}
+
+// FormatJsonValue 将 JSON 字段值转换为字符串，对象与数组输出为 JSON
+func FormatJsonValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		return JsonMarshalToString(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}